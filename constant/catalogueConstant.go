@@ -0,0 +1,18 @@
+package constant
+
+// 图书目录服务驱动（配置项 catalogue.driver），用于根据 ISBN 补全书籍信息
+const (
+	CatalogueDriverNone        = "none"        // 不查询
+	CatalogueDriverOpenLibrary = "openlibrary" // Open Library 的 Books API，也可以指向兼容该接口的本地服务
+)
+
+const (
+	DefaultOpenLibraryBaseURL      = "https://openlibrary.org"
+	DefaultOpenLibraryCoverURL     = "https://covers.openlibrary.org/b/id/%d-L.jpg?default=false" // 封面图片地址，%d 为封面 ID
+	DefaultCatalogueTimeoutSeconds = 5
+	ISBNCacheKeyPrefix             = "isbn:metadata:" // 查询结果缓存，完整格式为 isbn:metadata:ISBN-13
+	ISBNCacheTTLHours              = 30 * 24          // 查询到的书籍信息的缓存时间
+	ISBNNotFoundCacheTTLHours      = 24               // 查询不到的 ISBN 的缓存时间，避免重复请求
+	MaxCatalogueCoverSize          = 5 << 20          // 从目录服务下载的封面大小上限 (5MB)
+	MaxCatalogueIntroductionRunes  = 2000             // 补全的简介长度上限
+)
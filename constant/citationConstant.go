@@ -0,0 +1,26 @@
+package constant
+
+// 引用格式
+const (
+	CitationFormatBibTeX  = "bibtex"
+	CitationFormatRIS     = "ris"
+	CitationFormatGBT7714 = "gbt7714" // GB/T 7714-2015 顺序编码制
+	CitationFormatAPA     = "apa"     // APA 第 7 版
+)
+
+const (
+	DefaultCitationFormat = CitationFormatGBT7714
+	CitationMaxAuthors    = 3                // GB/T 7714 列出的责任者数，超过时加“等”或 et al
+	CitationFileName      = "citations-%s%s" // 批量导出的文件名，%s 为日期和扩展名
+)
+
+// CitationFileTypes 各引用格式导出文件的扩展名和类型
+var CitationFileTypes = map[string][2]string{
+	CitationFormatBibTeX:  {".bib", "application/x-bibtex; charset=utf-8"},
+	CitationFormatRIS:     {".ris", "application/x-research-info-systems; charset=utf-8"},
+	CitationFormatGBT7714: {".txt", "text/plain; charset=utf-8"},
+	CitationFormatAPA:     {".txt", "text/plain; charset=utf-8"},
+}
+
+// CitationAuthorRoles 作者字段末尾表示责任方式的词，生成引用时去掉（以“译”结尾的译者不列为作者）
+var CitationAuthorRoles = []string{"编著", "主编", "著", "编"}
@@ -23,3 +23,62 @@ const (
 	TypeOfKeyIntroduction = "introduction"
 	TypeOfKeyTag          = "tag"
 )
+
+// 文档列表和搜索结果的排序方式，不传时保持原有顺序
+const (
+	DocumentSortRating = "rating" // 按平均星级、评分人数从高到低
+)
+
+// 文档难度
+const (
+	DifficultyBeginner     = "beginner"     // 入门
+	DifficultyIntermediate = "intermediate" // 进阶
+	DifficultyAdvanced     = "advanced"     // 高级
+)
+
+// 上传时 AI 生成的元数据建议的状态
+const (
+	MetadataSuggestionPending  = "pending"  // 待处理
+	MetadataSuggestionAccepted = "accepted" // 已采纳（全部或部分字段）
+)
+
+// 可在修改文档时采纳的元数据建议字段
+const (
+	SuggestionFieldIntroduction = "introduction"
+	SuggestionFieldTags         = "tags"
+	SuggestionFieldCategory     = "category"
+	SuggestionFieldDifficulty   = "difficulty"
+	SuggestionFieldAll          = "all"
+)
+
+// 文档版本
+const (
+	DocumentVersionInitialNote  = "初始版本"     // 上传文档时第一个版本的说明
+	DocumentVersionRollbackNote = "回滚至版本 %d" // 回滚时新版本的默认说明
+)
+
+// 文档正文的语言（ISO 639-1），学习文档时按正文自动检测
+const (
+	LanguageChinese  = "zh"
+	LanguageJapanese = "ja"
+	LanguageKorean   = "ko"
+	LanguageEnglish  = "en"
+	LanguageFrench   = "fr"
+	LanguageGerman   = "de"
+	LanguageSpanish  = "es"
+	LanguageRussian  = "ru"
+	LanguageUnknown  = "und" // 无法判断
+)
+
+const (
+	LanguageDetectSampleRunes = 20000 // 检测语言时最多取正文前多少个字符
+	LanguageDetectMinLetters  = 20    // 字母或汉字少于该数量时不判断语言
+)
+
+// 拉丁字母文本中用于区分语言的常见词
+var LanguageStopWords = map[string][]string{
+	LanguageEnglish: {"the", "and", "of", "to", "is", "in", "that", "for", "with", "are"},
+	LanguageFrench:  {"le", "la", "les", "et", "des", "est", "une", "dans", "pour", "que"},
+	LanguageGerman:  {"der", "die", "und", "das", "ist", "nicht", "mit", "den", "von", "ein"},
+	LanguageSpanish: {"el", "los", "las", "y", "es", "una", "por", "con", "para", "del"},
+}
@@ -0,0 +1,20 @@
+package constant
+
+// 文档下载
+const (
+	DocumentDownloadPath            = "/api/document/%d/download" // 获取下载地址的接口，文档详情中的 URL 字段返回该路径
+	DocumentFilePath                = "/api/document/%d/file"     // 带水印文件的访问路径，需带下载接口签发的签名参数
+	DefaultDownloadURLExpireSeconds = 300                         // 下载地址的默认有效期
+	DownloadDedupeKeyPrefix         = "download:dedupe:"          // 下载计数去重，完整格式为 download:dedupe:文档ID:用户ID
+	DownloadDedupeMinutes           = 10                          // 同一用户在该时间内重复获取下载地址只计一次下载
+)
+
+// PDF 水印
+const (
+	DefaultWatermarkCommand = "qpdf" // 将水印页叠加到 PDF 每一页的命令
+	WatermarkTimeoutSeconds = 60
+	WatermarkPageWidth      = 595 // 水印页尺寸（A4，单位 pt），叠加时按文档页面大小缩放
+	WatermarkPageHeight     = 842
+	WatermarkFontSize       = 16
+	WatermarkCacheDir       = "document-watermark" // 带水印文件在系统临时目录下的缓存目录
+)
@@ -0,0 +1,61 @@
+package constant
+
+// 封面和头像的缩略图规格，缩略图保存在原图旁边，文件名为 <原文件名>_<规格><扩展名>（WebP 原图的缩略图为 .jpg）
+const (
+	ImageVariantList   = "list"   // 列表页
+	ImageVariantDetail = "detail" // 详情页
+	ImageVariantRetina = "retina" // 详情页高分屏（两倍尺寸）
+)
+
+// ImageVariants 全部缩略图规格
+var ImageVariants = []string{ImageVariantList, ImageVariantDetail, ImageVariantRetina}
+
+// 各规格缩略图的宽度（像素），高度按原图比例缩放，原图更小时不放大
+var CoverVariantWidths = map[string]int{
+	ImageVariantList:   200,
+	ImageVariantDetail: 480,
+	ImageVariantRetina: 960,
+}
+
+var AvatarVariantWidths = map[string]int{
+	ImageVariantList:   48,
+	ImageVariantDetail: 128,
+	ImageVariantRetina: 256,
+}
+
+const (
+	ThumbnailJPEGQuality = 85
+	MaxImagePixels       = 40000000 // 解码前检查图片像素数，防止解压炸弹
+)
+
+// 自动生成封面
+const (
+	GeneratedCoverWidth         = 600 // 文字封面尺寸（3:4）
+	GeneratedCoverHeight        = 800
+	PDFCoverRenderWidth         = 960        // PDF 首页渲染宽度，与 retina 规格一致
+	DefaultPDFRenderer          = "pdftoppm" // 渲染 PDF 首页的命令（poppler-utils），不可用时生成文字封面
+	PDFRenderTimeoutSeconds     = 30
+	GeneratedCoverTitleMaxLines = 5
+	GeneratedCoverRenderWidth   = 960            // 文字封面渲染为 PNG 的宽度，与 retina 规格一致
+	DefaultSVGRenderer          = "rsvg-convert" // 渲染文字封面的命令（librsvg），不可用时保存 SVG
+	SVGRenderTimeoutSeconds     = 10
+)
+
+// 文字封面上方显示的文档类型
+var GeneratedCoverTypeLabels = map[string]string{
+	BookType:  "书籍",
+	FileType:  "资料",
+	VideoType: "视频",
+}
+
+// 文字封面的配色（背景色、文字色），按文档名称选择
+var GeneratedCoverPalettes = [][2]string{
+	{"#2d4059", "#f6f6f6"},
+	{"#6a2c70", "#fbe8d3"},
+	{"#1f6f5c", "#f1f7ed"},
+	{"#b83b5e", "#fdf6f0"},
+	{"#3e497a", "#f1d00a"},
+	{"#5c3d2e", "#f2e5d5"},
+	{"#0f4c75", "#bbe1fa"},
+	{"#393e46", "#ffd369"},
+}
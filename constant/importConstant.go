@@ -0,0 +1,36 @@
+package constant
+
+// 文档批量导入任务状态
+const (
+	ImportJobRunning   = "running"   // 执行中
+	ImportJobCompleted = "completed" // 已完成（部分行失败也算完成，失败的行见错误报告）
+	ImportJobFailed    = "failed"    // 任务整体失败，如压缩包无法读取
+)
+
+const (
+	ImportJobKeyPrefix           = "import:job:" // 批量导入任务，完整格式为 import:job:任务ID
+	ImportJobTTLHours            = 7 * 24        // 任务进度和错误报告的保留时间
+	MaxImportArchiveSize         = 4 << 30       // 压缩包大小上限 (4GB)
+	MaxImportRows                = 1000          // 清单最多行数
+	MaxImportManifestSize        = 10 << 20      // 清单文件大小上限 (10MB)
+	DefaultImportPipelineWorkers = 2             // 同时学习（提取正文、向量化）的文档数，避免大批量导入时压垮向量化服务
+	ImportCategoryPathSeparator  = "/"           // 清单中分类路径的分隔符，如 计算机/操作系统
+	ImportManifestTagSeparators  = ";；|、"        // 清单中标签的分隔符（CSV 中逗号用于分列）
+	ImportReportFileName         = "import-errors-%s.csv"
+)
+
+// ImportManifestNames 未单独上传清单时，在压缩包根目录查找的清单文件名
+var ImportManifestNames = []string{"manifest.csv", "manifest.json"}
+
+// ImportManifestColumns 清单的列名（CSV 表头或 JSON 字段名，不区分大小写）及其别名
+var ImportManifestColumns = map[string][]string{
+	"file":         {"file", "filename", "path", "文件"},
+	"name":         {"name", "title", "名称", "书名"},
+	"author":       {"author", "作者"},
+	"isbn":         {"isbn"},
+	"category":     {"category", "category_path", "categorypath", "分类"},
+	"tags":         {"tags", "tag", "标签"},
+	"type":         {"type", "类型"},
+	"year":         {"year", "create_year", "createyear", "年份"},
+	"introduction": {"introduction", "description", "简介"},
+}
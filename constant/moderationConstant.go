@@ -0,0 +1,37 @@
+package constant
+
+// 内容审核对象类型
+const (
+	ModerationTypeDocument = "document"
+	ModerationTypePost     = "post"
+	ModerationTypeComment  = "comment"
+	ModerationTypeMessage  = "message"
+)
+
+// 内容风险类别
+const (
+	ModerationCategorySpam     = "spam"      // 垃圾广告、引流
+	ModerationCategoryAbuse    = "abuse"     // 辱骂、人身攻击、违法有害信息
+	ModerationCategoryOffTopic = "off_topic" // 与学习交流无关
+)
+
+// 内容审核状态
+const (
+	ModerationStatusPass     = "pass"     // 低风险，自动通过
+	ModerationStatusReview   = "review"   // 疑似违规，等待人工复核
+	ModerationStatusHidden   = "hidden"   // 高风险，已自动隐藏，等待人工复核
+	ModerationStatusApproved = "approved" // 人工复核通过
+	ModerationStatusRejected = "rejected" // 人工复核驳回
+)
+
+// 人工复核决定
+const (
+	ModerationDecisionApprove = "approve"
+	ModerationDecisionReject  = "reject"
+)
+
+const (
+	DefaultModerationHideThreshold   = 0.8  // 风险分达到该值时自动隐藏帖子和评论
+	DefaultModerationReviewThreshold = 0.5  // 风险分达到该值时进入人工复核队列
+	ModerationSourceMaxRunes         = 4000 // 送审文本的最大字符数
+)
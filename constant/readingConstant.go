@@ -0,0 +1,14 @@
+package constant
+
+const (
+	MaxReadingBookmarks    = 200  // 每个用户在一个文档中最多的书签数
+	MaxReadingHighlights   = 1000 // 每个用户在一个文档中最多的划线数
+	ReadingFinishedPercent = 100  // 阅读进度达到该百分比视为读完，不再出现在继续阅读列表中
+	HomepageContinueLimit  = 10   // 个人主页显示的继续阅读文档数
+	DefaultHighlightColor  = "yellow"
+	DefaultBookmarkName    = "第%d页" // 未命名书签的默认名称
+	DefaultBookmarkNoPage  = "书签"   // 没有页码的文档中未命名书签的默认名称
+)
+
+// HighlightColors 划线可选的颜色
+var HighlightColors = []string{"yellow", "green", "blue", "pink", "purple"}
@@ -0,0 +1,61 @@
+package constant
+
+// 推荐结果类型：book、file、video 为文档资源，post 为社区帖子
+const (
+	RecommendTypeBook  = BookType
+	RecommendTypeFile  = FileType
+	RecommendTypeVideo = VideoType
+	RecommendTypePost  = PostType
+)
+
+// 用户与资源的交互行为
+const (
+	InteractionView     = "view"     // 浏览
+	InteractionFavorite = "favorite" // 收藏
+	InteractionLike     = "like"     // 点赞（仅帖子）
+	InteractionComment  = "comment"  // 评论
+	InteractionOwn      = "own"      // 自己上传的文档或发布的帖子
+)
+
+// 各交互行为在兴趣画像和协同过滤中的权重
+var InteractionWeights = map[string]float64{
+	InteractionView:     1,
+	InteractionFavorite: 3,
+	InteractionLike:     2,
+	InteractionComment:  2,
+}
+
+const (
+	DefaultRecommendContentWeight    = 0.4 // 内容相似度（分类、标签）在混合得分中的默认权重
+	DefaultRecommendCFWeight         = 0.4 // 物品协同过滤在混合得分中的默认权重
+	DefaultRecommendPopularityWeight = 0.2 // 热度在混合得分中的默认权重
+	DefaultRecommendLimit            = 10  // 默认推荐条数
+	RecommendSeedLimit               = 50  // 参与计算的用户最近交互数
+	RecommendNeighborLimit           = 200 // 协同过滤中参与计算的相似用户数
+	RecommendCandidateLimit          = 200 // 内容和热度召回的候选数
+
+	RecommendInteractionLimit         = 500  // 计算推荐时每类交互来源读取的用户最近记录数
+	RecommendNeighborWindowDays       = 180  // 协同过滤只使用相似用户最近这些天内的交互
+	RecommendNeighborInteractionLimit = 5000 // 协同过滤中每类交互来源读取的相似用户记录总数，统计内容的交互人数时同样适用
+	RecommendActiveUserLimit          = 5000 // 批量预计算每次最多处理的活跃用户数（最近活跃的优先）
+)
+
+// RecommendKindAIBook 基于浏览向量检索和大模型重排的书籍推荐（/ai/:userId/book-recommendations）在缓存中的类型名
+const RecommendKindAIBook = "ai_book"
+
+// 推荐结果预计算与缓存
+const (
+	RecommendCacheKeyPrefix                = "recommend:"            // 推荐缓存 key 前缀，完整格式为 recommend:类型:用户ID
+	RecommendStaleAtKey                    = "recommend:stale_at"    // 全局失效时间戳，早于该时间生成的推荐都视为过期（如有新资源审核通过）
+	RecommendJobLockKey                    = "recommend:job_lock"    // 定时任务锁，多实例部署时同一时间只有一个实例执行批量预计算
+	RecommendRefreshLockPrefix             = "recommend:refreshing:" // 单个用户推荐的刷新锁，避免重复计算
+	RecommendCacheSize                     = 50                      // 每个用户每种类型预计算的推荐条数
+	RecommendCacheTTLHours                 = 7 * 24                  // 推荐缓存的最长保留时间
+	RecommendRefreshLockSeconds            = 120                     // 单个用户推荐刷新锁的超时时间
+	DefaultRecommendFreshMinutes           = 60                      // 推荐生成后多久内视为新鲜，过期后先返回旧结果再后台刷新
+	DefaultRecommendRefreshIntervalMinutes = 60                      // 批量预计算的执行间隔
+	DefaultRecommendActiveDays             = 30                      // 只为最近这些天内有交互的用户批量预计算
+)
+
+// RecommendKinds 批量预计算的混合推荐类型（书籍推荐接口的类型取决于用户所用的推荐策略）
+var RecommendKinds = []string{RecommendTypeBook, RecommendTypeFile, RecommendTypeVideo, RecommendTypePost}
@@ -0,0 +1,29 @@
+package constant
+
+// 书籍推荐策略（A/B 实验的变体）
+const (
+	RecommendStrategyVectorOnly = "vector_only" // 浏览兴趣向量检索，不经大模型重排
+	RecommendStrategyLLMRerank  = "llm_rerank"  // 向量检索后由大模型重排（默认策略）
+	RecommendStrategyPopularity = "popularity"  // 阅读量最高的书籍
+	RecommendStrategyHybrid     = "hybrid"      // 内容相似度 + 协同过滤 + 热度的混合推荐
+)
+
+// RecommendStrategyKinds 各推荐策略在推荐缓存中的类型名
+var RecommendStrategyKinds = map[string]string{
+	RecommendStrategyVectorOnly: "ai_book_vector",
+	RecommendStrategyLLMRerank:  RecommendKindAIBook,
+	RecommendStrategyPopularity: "ai_book_popular",
+	RecommendStrategyHybrid:     "ai_book_hybrid",
+}
+
+// 推荐实验状态
+const (
+	RecommendExperimentDraft   = "draft"   // 草稿，尚未开始
+	RecommendExperimentRunning = "running" // 进行中，同一时间只能有一个实验进行
+	RecommendExperimentStopped = "stopped" // 已结束
+)
+
+const (
+	RecommendAttributionWindowHours = 24   // 曝光后多长时间内的点击、收藏归因到该次曝光
+	RecommendConfidenceZ            = 1.96 // 95% 置信区间对应的 z 值
+)
@@ -41,10 +41,8 @@ const (
 	DocumentUpdateSuccess       = "文档更新成功"
 	DocumentDeletedFailed       = "文档删除失败"
 	DocumentUpdateFail          = "文档更新失败"
-	OldCoverDeleteFailed        = "旧封面删除失败"
 	OpenDocumentCoverFailed     = "打开文档封面失败"
 	UploadCoverImageFailed      = "封面上传失败"
-	OldDocumentDeleteFailed     = "旧文档删除失败"
 	DocumentOpenFailed          = "打开文档失败"
 	DocumentUploadFailed        = "文档上传失败"
 	CollectionUpdateFailed      = "更新文档收藏数失败"
@@ -63,6 +61,10 @@ const (
 	DocumentSummaryAccessDenied = "无权对此文档生成摘要"
 	AISummarySuccess              = "获取摘要成功"
 	AISummaryInvalidContentType   = "contentType 仅支持 document 或 post"
+	AISummaryInvalidStyle         = "style 仅支持 abstract、key_points、outline 或 glossary"
+	GetAISummaryHistorySuccess    = "获取摘要历史成功"
+	DocumentSourceFetchFailed     = "获取文档正文失败，请稍后重试"
+	AISummaryGenerateFailed       = "生成摘要失败，请稍后重试"
 )
 
 // Tag相关常量
@@ -233,6 +235,275 @@ const (
 	CancelAISessionSuccess = "终止AI输出成功"
 	CancelAISessionFailed  = "终止AI输出失败"
 	AISessionTaskNotExist  = "任务不存在或已结束"
+	SearchAIMessageSuccess = "搜索AI对话记录成功"
+	SearchAIMessageFailed  = "搜索AI对话记录失败"
+)
+
+// 课程助教相关常量
+const (
+	CategoryNotCourse          = "该分类不是课程"
+	GetCoursePersonaSuccess    = "获取课程助教设定成功"
+	UpdateCoursePersonaSuccess = "修改课程助教设定成功"
+	UpdateCoursePersonaFailed  = "修改课程助教设定失败"
+)
+
+// AI回答反馈相关常量
+const (
+	AIMessageNotExist         = "AI消息不存在"
+	AIFeedbackOnlyAssistant   = "只能评价AI的回答"
+	AIFeedbackSaveFailed      = "保存评价失败"
+	AIFeedbackSaveSuccess     = "评价成功"
+	GetAIFeedbackStatsSuccess = "获取AI回答质量统计成功"
+)
+
+// 提示词模板相关常量
+const (
+	PromptKeyNotExist            = "提示词标识不存在"
+	PromptVersionNotExist        = "提示词版本不存在"
+	PromptTemplateSyntaxError    = "提示词模板语法错误: "
+	PromptActiveVersionNoDelete  = "不能删除正在启用的提示词版本"
+	GetPromptTemplatesSuccess    = "获取提示词模板成功"
+	CreatePromptTemplateSuccess  = "新增提示词版本成功"
+	CreatePromptTemplateFailed   = "新增提示词版本失败"
+	PreviewPromptTemplateSuccess = "提示词预览成功"
+	RollbackPromptSuccess        = "提示词回滚成功"
+	RollbackPromptFailed         = "提示词回滚失败"
+	DeletePromptTemplateSuccess  = "删除提示词版本成功"
+	DeletePromptTemplateFailed   = "删除提示词版本失败"
+)
+
+// 测验相关常量
+const (
+	QuizPageRangeInvalid   = "页码范围无效"
+	QuizPageRangeNoText    = "所选页码范围内没有可用于出题的正文"
+	QuizCountRequired      = "题目和记忆卡片数量不能全部为0"
+	QuizGenerateFailed     = "生成测验失败，请稍后重试"
+	QuizGenerateSuccess    = "生成测验成功"
+	QuizContentInvalid     = "测验内容不合法: "
+	QuizSaveFailed         = "保存测验失败"
+	QuizSaveSuccess        = "保存测验成功"
+	QuizNotExist           = "测验不存在"
+	GetQuizzesSuccess      = "获取测验成功"
+	QuizDeleteSuccess      = "删除测验成功"
+	QuizDeleteFailed       = "删除测验失败"
+	QuizAnswerIndexInvalid = "题目下标无效"
+	QuizAttemptSaveFailed  = "保存答题记录失败"
+	QuizAttemptSubmitted   = "提交答题成功"
+	GetQuizAttemptsSuccess = "获取答题记录成功"
+)
+
+// 文档元数据建议相关常量
+const (
+	MetadataSuggestionNotExist   = "该文档暂无 AI 元数据建议"
+	GetMetadataSuggestionSuccess = "获取 AI 元数据建议成功"
+	InvalidSuggestionField       = "不支持采纳的建议字段"
+	InvalidDifficulty            = "难度只能是 beginner、intermediate、advanced 之一"
+)
+
+// 内容审核相关常量
+const (
+	ModerationRecordNotExist  = "审核记录不存在"
+	GetModerationQueueSuccess = "获取审核队列成功"
+	ModerationReviewSuccess   = "复核成功"
+	ModerationReviewFailed    = "复核失败"
+)
+
+// 敏感词相关常量
+const (
+	SensitiveWordRejected      = "内容包含敏感词，请修改后再提交："
+	SensitiveWordEmpty         = "敏感词不能为空"
+	SensitiveWordNotExist      = "敏感词不存在"
+	GetSensitiveWordsSuccess   = "获取敏感词成功"
+	SensitiveWordSaveSuccess   = "保存敏感词成功"
+	SensitiveWordSaveFailed    = "保存敏感词失败"
+	SensitiveWordDeleteSuccess = "删除敏感词成功"
+	SensitiveWordDeleteFailed  = "删除敏感词失败"
+	TestSensitiveTextSuccess   = "敏感词检测完成"
+)
+
+// 个性化推荐相关常量
+const (
+	GetRecommendationsSuccess = "获取推荐成功"
+	GetRecommendationsFailed  = "获取推荐失败"
+)
+
+// 推荐实验相关常量
+const (
+	RecommendExperimentNotExist         = "推荐实验不存在"
+	RecommendExperimentNameExists       = "实验名称已存在"
+	RecommendVariantNameDuplicate       = "变体名称不能为空或重复"
+	RecommendExperimentNotEditable      = "只能修改尚未开始的实验"
+	RecommendExperimentAlreadyRunning   = "已有正在进行的推荐实验"
+	RecommendExperimentStatusInvalid    = "当前实验状态不允许该操作"
+	RecommendExperimentSaveFailed       = "保存推荐实验失败"
+	RecommendExperimentSaveSuccess      = "保存推荐实验成功"
+	GetRecommendExperimentsSuccess      = "获取推荐实验成功"
+	GetRecommendExperimentReportSuccess = "获取推荐实验报告成功"
+)
+
+// 文档版本相关常量
+const (
+	DocumentVersionNotExist       = "文档版本不存在"
+	DocumentVersionAccessDenied   = "只有上传者或管理员可以管理文档版本"
+	DocumentVersionAlreadyCurrent = "该版本已是当前版本"
+	GetDocumentVersionsSuccess    = "获取文档版本成功"
+	GetDocumentVersionURLSuccess  = "获取文档版本下载地址成功"
+	DocumentRollbackSuccess       = "回滚文档版本成功"
+	DocumentRollbackFailed        = "回滚文档版本失败"
+	DocumentVersionRetentionOff   = "未配置文档版本保留策略"
+	PruneDocumentVersionsSuccess  = "清理文档旧版本成功"
+	PruneDocumentVersionsFailed   = "清理文档旧版本失败"
+)
+
+// 对象存储相关常量
+const (
+	StorageFileNotExist     = "文件不存在"
+	StorageSignatureInvalid = "访问地址签名无效或已过期"
+)
+
+// 分片上传和直传相关常量
+const (
+	UploadSessionCreateSuccess = "创建上传会话成功"
+	UploadSessionCreateFailed  = "创建上传会话失败"
+	UploadSessionNotExist      = "上传会话不存在或已过期"
+	UploadPartNumberInvalid    = "分片序号无效"
+	UploadPartSizeMismatch     = "分片大小与会话不一致"
+	UploadPartChecksumMismatch = "分片校验和不一致"
+	UploadPartSuccess          = "上传分片成功"
+	UploadPartFailed           = "上传分片失败"
+	GetUploadSessionSuccess    = "获取上传进度成功"
+	GetUploadSessionFailed     = "获取上传进度失败"
+	UploadPartsIncomplete      = "仍有分片未上传"
+	UploadFileChecksumMismatch = "文件校验和不一致，请重新上传"
+	UploadCompleteSuccess      = "上传完成"
+	UploadCompleteFailed       = "合并分片失败"
+	UploadAbortSuccess         = "已取消上传"
+	UploadAbortFailed          = "取消上传失败"
+	UploadPresignSuccess       = "获取直传地址成功"
+	UploadPresignFailed        = "获取直传地址失败"
+	UploadedObjectNotExist     = "上传的文件不存在、已过期或不属于当前用户"
+	UploadedObjectTooLarge     = "上传的文件超过大小限制"
+	UploadedObjectCheckFailed  = "读取上传的文件失败"
+)
+
+// 上传文件校验相关常量
+const (
+	UploadFileTypeNotAllowed   = "文件格式不受支持，只能上传 PDF、EPUB、DOCX、PPTX"
+	UploadCoverTypeNotAllowed  = "封面只支持 JPEG、PNG、WebP 格式"
+	UploadAvatarTypeNotAllowed = "头像只支持 JPEG、PNG、WebP 格式"
+	UploadPDFEncrypted         = "不支持加密的 PDF 文件"
+	UploadPDFCorrupted         = "PDF 文件已损坏或格式错误"
+	UploadPDFPageInvalid       = "PDF 文件页数异常"
+	UploadFileInfected         = "文件未通过安全扫描"
+	UploadScanUnavailable      = "文件安全扫描暂不可用，请稍后重试"
+)
+
+// 封面和缩略图相关常量
+const (
+	ImageDecodeFailed     = "图片无法解析，请检查文件是否损坏"
+	GenerateCoversStarted = "已开始生成缺失的封面和缩略图"
+)
+
+// 文件元数据相关常量
+const (
+	RefreshFileMetadataStarted = "已开始提取文档的文件元数据"
+	InvalidPageRange           = "页数范围不正确"
+)
+
+// 文档下载相关常量
+const (
+	GetDocumentDownloadSuccess   = "获取下载地址成功"
+	DocumentDownloadFailed       = "获取下载地址失败"
+	DocumentDownloadDenied       = "文档未开放，只有上传者和管理员可以下载"
+	DocumentDownloadNotSupported = "视频没有可下载的文件"
+	DocumentWatermarkFailed      = "添加水印失败，请稍后重试"
+)
+
+// 文档批量导入相关常量
+const (
+	DocumentImportStarted         = "已开始批量导入文档"
+	DocumentImportDryRunStarted   = "已开始校验导入清单（试运行，不会创建文档）"
+	GetDocumentImportSuccess      = "获取导入任务成功"
+	DocumentImportNotExist        = "导入任务不存在或已过期"
+	ImportJobInterrupted          = "服务重启，导入任务已中断，请重新导入"
+	ImportArchiveInvalid          = "压缩包无法读取，请上传 ZIP 文件"
+	ImportArchiveTooLarge         = "压缩包不能超过4GB"
+	ImportManifestMissing         = "缺少导入清单，请上传 manifest 或在压缩包根目录放置 manifest.csv / manifest.json"
+	ImportManifestInvalid         = "导入清单格式错误"
+	ImportManifestEncodingInvalid = "导入清单编码无法识别，请使用 UTF-8 或 GBK"
+	ImportManifestEmpty           = "导入清单没有数据"
+	ImportManifestNoFileColumn    = "导入清单缺少 file 列"
+	ImportTooManyRows             = "导入清单不能超过1000行"
+	ImportRowFileRequired         = "缺少文件路径"
+	ImportRowNameRequired         = "缺少名称"
+	ImportRowFieldTooLong         = "%s不能超过%d个字"
+	ImportRowTypeInvalid          = "类型只能是 book 或 file"
+	ImportRowCategoryNotFound     = "分类不存在或不唯一"
+	ImportRowYearInvalid          = "年份格式不正确"
+	ImportRowFileNotFound         = "压缩包中找不到该文件，或匹配到多个文件"
+	ImportRowFileTooLarge         = "文件大小不能超过160MB"
+	ImportRowDuplicate            = "与已有文档（ID %d）的文件相同"
+	ImportRowDuplicateInManifest  = "与清单第%d行的文件相同"
+)
+
+// ISBN 书籍信息补全相关常量
+const (
+	InvalidISBN              = "ISBN 格式或校验位不正确"
+	DocumentNameRequired     = "请填写文档名称，或提供可查询到书籍信息的 ISBN"
+	GetBookMetadataSuccess   = "查询书籍信息成功"
+	BookMetadataNotFound     = "未查询到该 ISBN 的书籍信息"
+	BookCatalogueUnavailable = "图书目录服务暂不可用，请稍后重试或手动填写"
+)
+
+// 引用导出相关常量
+const (
+	GetCitationSuccess    = "生成引用成功"
+	CitationFormatInvalid = "引用格式只能是 bibtex、ris、gbt7714 或 apa"
+	CitationAccessDenied  = "无权引用该文档"
+)
+
+// 阅读进度、书签和划线相关常量
+const (
+	GetReadingStateSuccess     = "获取阅读状态成功"
+	ReadingAccessDenied        = "无权阅读该文档"
+	ReadingPageInvalid         = "页码超出文档页数"
+	SyncReadingProgressSuccess = "同步阅读进度成功"
+	ReadingProgressOutdated    = "已有更新的阅读进度，本次进度未保存"
+	SyncReadingProgressFailed  = "同步阅读进度失败"
+	GetContinueReadingSuccess  = "获取继续阅读列表成功"
+	BookmarkNotExist           = "书签不存在"
+	BookmarkLimitExceeded      = "每个文档最多添加200个书签"
+	BookmarkSaveSuccess        = "保存书签成功"
+	BookmarkSaveFailed         = "保存书签失败"
+	BookmarkDeleteSuccess      = "删除书签成功"
+	BookmarkDeleteFailed       = "删除书签失败"
+	HighlightNotExist          = "划线不存在"
+	HighlightLimitExceeded     = "每个文档最多添加1000条划线"
+	HighlightColorInvalid      = "划线颜色只能是 yellow、green、blue、pink 或 purple"
+	HighlightSaveSuccess       = "保存划线成功"
+	HighlightSaveFailed        = "保存划线失败"
+	HighlightDeleteSuccess     = "删除划线成功"
+	HighlightDeleteFailed      = "删除划线失败"
+)
+
+// 文档评分和评价相关常量
+const (
+	SaveReviewSuccess     = "评价成功"
+	SaveReviewFailed      = "评价失败"
+	ReviewDocumentNotOpen = "只能评价已开放的文档"
+	ReviewOwnDocument     = "不能评价自己上传的文档"
+	ReviewAccessDenied    = "无权查看该文档的评价"
+	GetReviewsSuccess     = "获取评价列表成功"
+	ReviewNotExist        = "评价不存在"
+	ReviewDeleteDenied    = "只能删除自己的评价"
+	DeleteReviewSuccess   = "删除评价成功"
+	DeleteReviewFailed    = "删除评价失败"
+	HelpfulOwnReview      = "不能给自己的评价投票"
+	AlreadyVotedHelpful   = "已经标记过有帮助"
+	NotVotedHelpful       = "还没有标记为有帮助"
+	VoteHelpfulSuccess    = "已标记为有帮助"
+	CancelHelpfulSuccess  = "已取消有帮助"
+	VoteHelpfulFailed     = "操作失败"
 )
 
 // 帖子相关常量
@@ -0,0 +1,15 @@
+package constant
+
+const (
+	MinReviewStars         = 1
+	MaxReviewStars         = 5
+	DefaultReviewPageSize  = 10
+	ReviewNotificationType = "review"
+	ReviewNotification     = "你上传的文档《%s》收到了用户\"%s\"的%d星评价"
+)
+
+// 评价列表的排序方式
+const (
+	ReviewSortHelpful = "helpful" // 有帮助人数最多的在前（默认）
+	ReviewSortLatest  = "latest"  // 最近修改的在前
+)
@@ -0,0 +1,13 @@
+package constant
+
+// 敏感词命中后的处理方式（按严重程度从低到高）
+const (
+	SensitiveActionFlag   = "flag"   // 放行，但交由内容审核进入人工复核
+	SensitiveActionMask   = "mask"   // 将敏感词替换为 * 后保存
+	SensitiveActionReject = "reject" // 拒绝提交
+)
+
+const (
+	DefaultSensitiveAction     = SensitiveActionMask      // 分类未配置处理方式时的默认处理方式
+	SensitiveWordReloadChannel = "sensitive_words:reload" // 词库变更后通知各实例重新加载的 Redis 频道
+)
@@ -0,0 +1,18 @@
+package constant
+
+// 对象存储驱动（配置项 storage.driver）
+const (
+	StorageDriverCOS   = "cos"   // 腾讯云 COS
+	StorageDriverLocal = "local" // 本地磁盘，用于开发和无云环境部署
+	StorageDriverS3    = "s3"    // S3 兼容存储（AWS S3、MinIO 等）
+)
+
+const (
+	DefaultLocalStorageRoot   = "./storage"   // 本地存储的默认根目录
+	LocalStorageRoutePrefix   = "/storage"    // 本地存储文件的访问路由前缀
+	DefaultS3Region           = "us-east-1"   // S3 未配置区域时使用的默认区域（MinIO 默认区域）
+	MaxSignedURLExpireSeconds = 7 * 24 * 3600 // 签名地址的最长有效期（S3 签名的上限）
+)
+
+// PrivateBlobPrefixes 本地存储中必须使用签名地址访问的路径前缀（文档主文件和分片上传的临时文件）
+var PrivateBlobPrefixes = []string{"files/", UploadTempKeyPrefix}
@@ -0,0 +1,47 @@
+package constant
+
+// 分片上传和直传
+const (
+	UploadSessionKeyPrefix     = "upload:session:" // 分片上传会话，完整格式为 upload:session:会话ID
+	UploadPartsKeyPrefix       = "upload:parts:"   // 已上传分片的校验和（hash，分片序号 -> SHA-256）
+	UploadObjectKeyPrefix      = "upload:object:"  // 已完成上传、等待提交文档的对象及其所属用户
+	UploadTempKeyPrefix        = "uploads/tmp/"    // 分片在存储中的临时路径前缀，可为其配置生命周期规则自动清理
+	UploadSessionTTLHours      = 24                // 上传会话和已完成对象的保留时间
+	UploadPresignExpireMinutes = 30                // 直传签名地址的有效期
+	DefaultUploadPartSize      = 5 << 20           // 默认分片大小 (5MB)
+	MinUploadPartSize          = 1 << 20           // 最小分片大小（最后一个分片除外）
+	MaxUploadPartSize          = 32 << 20          // 最大分片大小
+)
+
+// 各类上传文件允许的格式（按文件内容检测，不信任扩展名）
+var AllowedUploadMIMETypes = map[string][]string{
+	BookType: {MIMEPDF, MIMEEPUB, MIMEDOCX, MIMEPPTX},
+	FileType: {MIMEPDF, MIMEEPUB, MIMEDOCX, MIMEPPTX},
+}
+
+// 封面允许的图片格式
+var AllowedCoverMIMETypes = []string{MIMEJPEG, MIMEPNG, MIMEWebP}
+
+const (
+	MIMEPDF  = "application/pdf"
+	MIMEEPUB = "application/epub+zip"
+	MIMEDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMEPPTX = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	MIMEJPEG = "image/jpeg"
+	MIMEPNG  = "image/png"
+	MIMEWebP = "image/webp"
+)
+
+const MaxPDFPages = 10000 // PDF 页数上限，超过视为异常文件
+
+// 恶意文件扫描驱动（配置项 scanner.driver）
+const (
+	ScannerDriverNone   = "none"   // 不扫描
+	ScannerDriverClamAV = "clamav" // ClamAV（clamd 的 INSTREAM 协议）
+)
+
+const (
+	DefaultClamAVAddress        = "tcp://127.0.0.1:3310"
+	DefaultClamAVTimeoutSeconds = 60
+	ClamAVChunkSize             = 64 << 10 // INSTREAM 每个数据块的大小
+)
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PostAIMessageFeedback 用户对AI回答点赞/点踩（可附带原因），重复评价会覆盖之前的评价
+// POST /api/ai/chat/messages/:messageId/feedback
+func PostAIMessageFeedback(c *gin.Context) {
+	messageID, err := strconv.ParseUint(c.Param("messageId"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	var req dto.AIMessageFeedbackDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+	if userClaims.UserID != req.UserID {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.NonSelf)
+		return
+	}
+
+	// 只能评价自己会话中的 AI 回答
+	message, err := dao.GetAIMessageByID(messageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.AIMessageNotExist)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if message.Role != "assistant" {
+		response.Fail(c, http.StatusBadRequest, nil, constant.AIFeedbackOnlyAssistant)
+		return
+	}
+	session, err := dao.GetAISessionByID(message.AISessionsID)
+	if err != nil {
+		response.Fail(c, http.StatusNotFound, nil, constant.AISessionNotExist)
+		return
+	}
+	if session.UserID != userClaims.UserID {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.NonSelf)
+		return
+	}
+
+	feedback := models.AIMessageFeedback{
+		AIMessageID: message.ID,
+		UserID:      userClaims.UserID,
+		Rating:      req.Rating,
+		Reason:      req.Reason,
+	}
+	if err := dao.SaveAIMessageFeedback(&feedback); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.AIFeedbackSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildAIMessageFeedbackResponse(feedback), constant.AIFeedbackSaveSuccess)
+}
+
+// AdminGetAIFeedbackDashboard 管理员查看AI回答质量看板：按天、模型、提示词版本聚合的评价统计，以及差评最多的回答及其检索片段
+// GET /api/admin/ai/feedback?startDate=2026-01-01&endDate=2026-01-31&limit=10
+func AdminGetAIFeedbackDashboard(c *gin.Context) {
+	var req dto.AIFeedbackStatsDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	// 解析时间范围，结束日期包含当天
+	today := time.Now()
+	endDay := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+	if req.EndDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, nil, constant.TimeFormatError)
+			return
+		}
+		endDay = parsed
+	}
+	startDay := endDay.AddDate(0, 0, -29)
+	if req.StartDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, nil, constant.TimeFormatError)
+			return
+		}
+		startDay = parsed
+	}
+	end := endDay.AddDate(0, 0, 1)
+
+	dailyStats, err := dao.GetAIFeedbackDailyStats(startDay, end)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	worstStats, err := dao.GetWorstRatedAIMessages(startDay, end, req.Limit)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	worstAnswers := make([]response.AIWorstAnswerResponse, 0, len(worstStats))
+	for _, stat := range worstStats {
+		answer, err := response.BuildAIWorstAnswerResponse(stat)
+		if err != nil {
+			// 回答已被删除等情况，跳过该条
+			continue
+		}
+		worstAnswers = append(worstAnswers, answer)
+	}
+
+	response.SuccessWithData(c, response.AIFeedbackDashboardResponse{
+		StartDate:    startDay.Format("2006-01-02"),
+		EndDate:      endDay.Format("2006-01-02"),
+		DailyStats:   response.BuildAIFeedbackDailyStatResponses(dailyStats),
+		WorstAnswers: worstAnswers,
+	}, constant.GetAIFeedbackStatsSuccess)
+}
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,6 +16,7 @@ import (
 	"github.com/antidote-kt/SSE_Library-back/response"
 	"github.com/antidote-kt/SSE_Library-back/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // AIChatRequest 表示 AI 聊天请求
@@ -54,9 +57,10 @@ func TestStreamChat(c *gin.Context) {
 				contextStr += fmt.Sprintf("\n[片段%d]: %s", i+1, txt)
 			}
 
-			augmentedPrompt := fmt.Sprintf(`请结合以下参考资料回答我的问题。如果参考资料中没有相关信息，请明确告知，不要编造。
-			参考资料：%s
-			我的问题：%s`, contextStr, userQuery)
+			augmentedPrompt, _ := renderPrompt(constant.PromptKeyRAGAugment, map[string]string{
+				"Context":  contextStr,
+				"Question": userQuery,
+			})
 
 			// 替换最新一条消息的内容为增强后的 Prompt
 			req.Messages[len(req.Messages)-1].Content = augmentedPrompt
@@ -65,7 +69,8 @@ func TestStreamChat(c *gin.Context) {
 
 	// 调用 StreamChatWithSessionID 函数处理流式响应，默认不启用思考内容推送
 	// 使用临时 sessionId : 80000
-	result, err := utils.StreamChatWithSessionID(c, "80000", req.Messages, true)
+	systemPrompt, _ := renderPrompt(constant.PromptKeyAIChatSystem, nil)
+	result, err := utils.StreamChatWithSessionID(c, "80000", req.Messages, true, systemPrompt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -97,7 +102,8 @@ func TestStreamChat(c *gin.Context) {
 func TestGenerateTitle(c *gin.Context) {
 	userInput := "你好，我想了解一下图书馆的开放时间"
 
-	title, err := utils.GenerateSessionTitle(userInput)
+	titlePrompt, _ := renderPrompt(constant.PromptKeyAISessionTitle, nil)
+	title, err := utils.GenerateSessionTitle(userInput, titlePrompt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -197,6 +203,16 @@ func SendAISessionMessages(c *gin.Context) {
 		return
 	}
 
+	session, err := dao.GetAISessionByID(sessionId)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if session.UserID != userClaims.UserID {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.NonSelf)
+		return
+	}
+
 	// 补充逻辑
 	// 在将当前消息存入数据库之前，查询该会话是否已经有历史消息，若没有说明是第一条信息，自动根据用户输入智能更新标题
 	isFirstMessage := false
@@ -205,14 +221,10 @@ func SendAISessionMessages(c *gin.Context) {
 		isFirstMessage = true
 	}
 	if isFirstMessage {
-		session, err := dao.GetAISessionByID(sessionId)
-		if err != nil {
-			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
-			return
-		}
 		if session.Title == "新对话" {
 			// 如果标题默认，则使用智能生成
-			title, err := utils.GenerateSessionTitle(req.Content)
+			titlePrompt, _ := renderPrompt(constant.PromptKeyAISessionTitle, nil)
+			title, err := utils.GenerateSessionTitle(req.Content, titlePrompt)
 			if err != nil {
 				response.Fail(c, http.StatusInternalServerError, nil, constant.GenerateSessionTitleFailed)
 				return
@@ -270,24 +282,48 @@ func SendAISessionMessages(c *gin.Context) {
 		}
 	}
 
+	// 课程助教会话：使用课程人设和大纲作为系统提示词，检索范围限定在课程子树下的文档
+	var courseCtx *courseTutorContext
+	if session.CategoryID != nil {
+		ctx, err := buildCourseTutorContext(*session.CategoryID)
+		switch {
+		case err == nil:
+			courseCtx = &ctx
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// 课程分类已被删除，会话退化为普通 AI 助手，避免后续消息都无法发送
+			log.Printf("[CourseTutor] 会话 %d 的课程分类 %d 已不存在，改为普通助手回答", session.ID, *session.CategoryID)
+		default:
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+			return
+		}
+	}
+
 	// 5. 知识库检索增强
 	enhancedContent := req.Content // 默认使用原问题
+	var retrievedChunks []string   // 本次回答所用的知识片段，随 AI 回复一并保存，便于回答质量分析
+	ragPromptVersion := ""         // 使用了检索增强提示词时记录其版本
 
 	// 对用户当前问题进行向量化
 	queryVec, err := utils.GetEmbeddings([]string{req.Content})
 	if err == nil && len(queryVec) > 0 {
-		// 在 Milvus 中检索 Top-3 的知识片段
-		relatedChunks, searchErr := utils.SearchKnowledge(queryVec[0], 3)
+		// 在 Milvus 中检索 Top-3 的知识片段（课程会话只在课程资料中检索）
+		var relatedChunks []string
+		var searchErr error
+		if courseCtx != nil {
+			relatedChunks, searchErr = utils.SearchKnowledgeInFiles(queryVec[0], 3, courseCtx.FileIDs)
+		} else {
+			relatedChunks, searchErr = utils.SearchKnowledge(queryVec[0], 3)
+		}
 		if searchErr == nil && len(relatedChunks) > 0 {
+			retrievedChunks = relatedChunks
 			// 将检索到的片段拼接
 			contextInfo := strings.Join(relatedChunks, "\n\n---\n\n")
 
 			// 替换为增强型 Prompt
-			enhancedContent = fmt.Sprintf(
-				"你是一个智能图书助手。请根据以下[已知知识库信息]回答用户的[问题]。\n如果已知信息中没有相关内容，请明确告知，不要自行编造。\n\n[已知知识库信息]：\n%s\n\n[用户问题]：%s",
-				contextInfo,
-				req.Content,
-			)
+			enhancedContent, ragPromptVersion = renderPrompt(constant.PromptKeyRAGAugment, map[string]string{
+				"Context":  contextInfo,
+				"Question": req.Content,
+			})
 		}
 	} else {
 		fmt.Printf("[RAG Warning] 问题 “%s” 向量化失败: %v\n", req.Content, err)
@@ -306,19 +342,30 @@ func SendAISessionMessages(c *gin.Context) {
 		})
 	}
 
-	// 7. 调用流式推流工具
-	streamResult, err := utils.StreamChatWithSessionID(c, sessionIdStr, messages, req.IsThink)
+	// 7. 调用流式推流工具（系统提示词取后台当前启用的版本）
+	systemPrompt, promptVersion := renderPrompt(constant.PromptKeyAIChatSystem, nil)
+	if courseCtx != nil {
+		systemPrompt, promptVersion = courseCtx.SystemPrompt, courseCtx.PromptVersion
+	}
+	if ragPromptVersion != "" {
+		promptVersion += "," + ragPromptVersion
+	}
+	streamResult, err := utils.StreamChatWithSessionID(c, sessionIdStr, messages, req.IsThink, systemPrompt)
 	if err != nil {
 		fmt.Printf("流式回复推送失败: %v\n", err)
 		// 如果推送失败，也建议存入一条错误提示到数据库，保证会话连贯性
 	}
 
-	// 8. 保存 AI 完整回复
+	// 8. 保存 AI 完整回复（同时记录模型、提示词版本和检索上下文）
+	retrievalContext, _ := json.Marshal(retrievedChunks)
 	aiMsg := &models.AIMessage{
-		AISessionsID:    sessionId,
-		Role:            "assistant",
-		Content:         streamResult.Content,
-		ThinkingContent: streamResult.ThinkingContent,
+		AISessionsID:     sessionId,
+		Role:             "assistant",
+		Content:          streamResult.Content,
+		ThinkingContent:  streamResult.ThinkingContent,
+		Model:            utils.GetChatModelName(),
+		PromptVersion:    promptVersion,
+		RetrievalContext: string(retrievalContext),
 	}
 	// 持久化 AI 的回复
 	err = dao.CreateAIMessage(aiMsg)
@@ -38,6 +38,20 @@ func CreateAISession(c *gin.Context) {
 		Title:  "新对话",
 	}
 
+	// 从课程创建的会话使用课程助教，分类必须存在且为课程
+	if req.CategoryID != nil {
+		category, err := dao.GetCategoryByID(*req.CategoryID)
+		if err != nil {
+			response.Fail(c, http.StatusNotFound, nil, constant.CategoryNotExist)
+			return
+		}
+		if !category.IsCourse {
+			response.Fail(c, http.StatusBadRequest, nil, constant.CategoryNotCourse)
+			return
+		}
+		newAISession.CategoryID = &category.ID
+	}
+
 	if err := dao.CreateAISession(&newAISession); err != nil {
 		response.Fail(c, http.StatusInternalServerError, nil, constant.CreateAISessionFailed)
 		return
@@ -109,7 +123,11 @@ func UpdateAISession(c *gin.Context) {
 		return
 	}
 
-	aiSession.Title = req.NewTitle
+	title, ok := filterSensitiveText(c, req.NewTitle)
+	if !ok {
+		return
+	}
+	aiSession.Title = title
 
 	if err := dao.UpdateAISession(&aiSession); err != nil {
 		response.Fail(c, http.StatusInternalServerError, nil, constant.UpdateAISessionFailed)
@@ -166,3 +184,35 @@ func DeleteAISessions(c *gin.Context) {
 
 	response.Success(c, nil, constant.DeleteAISessionSuccess)
 }
+
+// SearchAIMessages 在当前用户的全部AI会话中搜索消息内容（分页，已删除的会话不参与搜索）
+// GET /api/ai/chat/search?key=xxx&page=1&pageSize=10
+func SearchAIMessages(c *gin.Context) {
+	var req dto.SearchAIMessagesDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.SearchKeyLack)
+		return
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	messages, total, err := dao.SearchAIMessagesByUserAndKeyword(userClaims.UserID, req.Key, req.Page, req.PageSize)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.SearchAIMessageFailed)
+		return
+	}
+
+	searchResponse := response.BuildAIMessageSearchResponse(messages, req.Key, total, req.Page, req.PageSize)
+	response.SuccessWithData(c, searchResponse, constant.SearchAIMessageSuccess)
+}
@@ -9,45 +9,66 @@ import (
 
 func LearnDocument(fid int, cosUrl string) {
 	// 开启异步 Goroutine
-	go func(fid int64, url string) {
-		// 1. 下载到本地临时文件
-		tmpPath, err := utils.DownloadFromCOSToTemp(url)
-		if err != nil {
-			log.Printf("MilVus [错误]: 下载文档 %d 失败: %v\n", fid, err)
-			return
-		}
-		log.Printf("MilVus: 下载文档 %d 成功，临时路径为: %s\n", fid, tmpPath)
-		defer os.Remove(tmpPath)
+	go learnDocument(int64(fid), cosUrl)
+}
 
-		// 2. 提取、清洗并切片
-		text, err := utils.ExtractTextFromPDF(tmpPath)
-		if err != nil {
-			log.Printf("MilVus [错误]: 提取文档 %d 的 PDF 文本失败: %v\n", fid, err)
-			return
-		}
+// learnDocument 下载文档、提取正文并存入知识库，同时生成元数据建议和内容预审，在当前 goroutine 中执行完向量化才返回
+func learnDocument(fid int64, url string) {
+	// 1. 下载到本地临时文件
+	tmpPath, err := utils.DownloadFromCOSToTemp(url)
+	if err != nil {
+		log.Printf("MilVus [错误]: 下载文档 %d 失败: %v\n", fid, err)
+		go moderateDocument(uint64(fid), "")
+		return
+	}
+	log.Printf("MilVus: 下载文档 %d 成功，临时路径为: %s\n", fid, tmpPath)
+	defer os.Remove(tmpPath)
 
-		cleanedText := utils.CleanText(text)
-		chunks := utils.ChunkText(cleanedText, 500, 50)
+	// 2. 提取、清洗并切片
+	text, err := utils.ExtractTextFromPDF(tmpPath)
+	// 记录页数、字数、语言等文件元数据，提取不到正文时只记录文件本身的信息
+	updateDocumentFileMetadata(uint64(fid), tmpPath, text)
+	if err != nil {
+		// EPUB、DOCX、PPTX 等非 PDF 文件和提取失败的 PDF 没有正文，只审核名称和简介
+		log.Printf("MilVus [错误]: 提取文档 %d 的 PDF 文本失败: %v\n", fid, err)
+		go moderateDocument(uint64(fid), "")
+		return
+	}
 
-		if len(chunks) == 0 {
-			log.Printf("MilVus [警告]: 文档 %d 未提取到任何文本内容(可能为扫描件)\n", fid)
-			return
-		}
+	cleanedText := utils.CleanText(text)
+	chunks := utils.ChunkText(cleanedText, 500, 50)
 
-		// 3. 批量向量化
-		vectors, err := utils.GetEmbeddingsInBatches(chunks, 20)
-		if err != nil {
-			log.Printf("MilVus [错误]: 文档 %d 向量化失败: %v\n", fid, err)
-			return
-		}
+	if len(chunks) == 0 {
+		log.Printf("MilVus [警告]: 文档 %d 未提取到任何文本内容(可能为扫描件)\n", fid)
+		go moderateDocument(uint64(fid), "")
+		return
+	}
 
-		// 4. 存入 Milvus
-		err = utils.InsertChunks(fid, chunks, vectors)
-		if err != nil {
-			log.Printf("MilVus [错误]: 文档 %d 存入 Milvus 失败: %v\n", fid, err)
+	// 根据正文生成简介、标签、分类和难度建议，与向量化互不影响
+	go func() {
+		if err := suggestDocumentMetadata(uint64(fid), cleanedText); err != nil {
+			log.Printf("[元数据建议] 文档 %d 生成失败: %v\n", fid, err)
 			return
 		}
+		log.Printf("[元数据建议] 文档 %d 生成完成\n", fid)
+	}()
+
+	// 对正文进行内容预审，审核结果供管理员审核待发布文档时参考
+	go moderateDocument(uint64(fid), cleanedText)
+
+	// 3. 批量向量化
+	vectors, err := utils.GetEmbeddingsInBatches(chunks, 20)
+	if err != nil {
+		log.Printf("MilVus [错误]: 文档 %d 向量化失败: %v\n", fid, err)
+		return
+	}
+
+	// 4. 存入 Milvus
+	err = utils.InsertChunks(fid, chunks, vectors)
+	if err != nil {
+		log.Printf("MilVus [错误]: 文档 %d 存入 Milvus 失败: %v\n", fid, err)
+		return
+	}
 
-		log.Printf("MilVus [成功]: 文档 %d 学习完成！共成功处理 %d 个切片\n", fid, len(chunks))
-	}(int64(fid), cosUrl)
+	log.Printf("MilVus [成功]: 文档 %d 学习完成！共成功处理 %d 个切片\n", fid, len(chunks))
 }
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+)
+
+// canAccessDocument 判断用户能否使用文档（下载、引用、摘要、测验、记录阅读进度等）：开放的文档登录用户均可使用，其他状态的文档只有上传者和管理员可以使用
+func canAccessDocument(document models.Document, userClaims *utils.MyClaims) bool {
+	return document.Status == constant.DocumentStatusOpen ||
+		document.UploaderID == userClaims.UserID ||
+		userClaims.Role == "admin"
+}
+
+// canViewPost 被内容审核隐藏的帖子只有发帖人和管理员可以查看，userClaims 为空时视为未登录
+func canViewPost(post models.Post, userClaims *utils.MyClaims) bool {
+	return !post.IsHidden ||
+		(userClaims != nil && (userClaims.UserID == post.SenderID || userClaims.Role == "admin"))
+}
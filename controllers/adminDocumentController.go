@@ -7,6 +7,7 @@ import (
 	"github.com/antidote-kt/SSE_Library-back/constant"
 	"github.com/antidote-kt/SSE_Library-back/dao"
 	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
 	"github.com/antidote-kt/SSE_Library-back/response"
 	"github.com/antidote-kt/SSE_Library-back/utils"
 	"github.com/gin-gonic/gin"
@@ -54,18 +55,33 @@ func AdminModifyDocumentStatus(c *gin.Context) {
 
 	// 如果请求中包含状态更新信息，则更新文档状态
 	if request.Status != nil {
-		document.Status = *request.Status
+		if err := setDocumentStatus(document, *request.Status); err != nil {
+			// 文档更新失败，返回错误响应
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentStatusUpdateFailed)
+			return
+		}
 	}
 
-	// 更新数据库中的文档信息
+	// 返回成功响应
+	response.Success(c, nil, constant.DocumentStatusUpdateSuccess)
+}
+
+// setDocumentStatus 修改文档状态：新资源开放、开放的文档被关闭或撤回后所有用户的推荐需要重新计算。
+// 管理员修改文档状态和内容审核的人工复核共用该流程
+func setDocumentStatus(document models.Document, status string) error {
+	previous := document.Status
+	if previous == status {
+		return nil
+	}
+	document.Status = status
 	if err := dao.UpdateDocument(document); err != nil {
-		// 文档更新失败，返回错误响应
-		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentStatusUpdateFailed)
-		return
+		return err
+	}
+	if status == constant.DocumentStatusOpen || previous == constant.DocumentStatusOpen {
+		markRecommendationsStale()
 	}
 
-	// 返回成功响应
-	response.Success(c, nil, constant.DocumentStatusUpdateSuccess)
+	return nil
 }
 
 // AdminGetDocumentList 管理员获取文档列表
@@ -90,15 +106,31 @@ func AdminGetDocumentList(c *gin.Context) {
 		return
 	}
 
+	// 批量获取文档的内容审核结果，供管理员审核待发布文档时参考
+	documentIDs := make([]uint64, 0, len(documents))
+	for _, document := range documents {
+		documentIDs = append(documentIDs, document.ID)
+	}
+	moderationRecords, err := dao.GetModerationRecordsByContentIDs(constant.ModerationTypeDocument, documentIDs)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
 	// 构建文档详情响应列表
-	var documentDetailResponses []response.DocumentDetailResponse
+	var documentDetailResponses []response.AdminDocumentDetailResponse
 	for _, document := range documents {
-		docDetailResponse, err := response.BuildDocumentDetailResponse(document)
+		docDetailResponse, err := response.BuildDocumentDetailResponseForViewer(document, userClaims)
 		if err != nil {
 			// 如果构建某个文档详情失败，记录错误但继续处理其他文档
 			continue
 		}
-		documentDetailResponses = append(documentDetailResponses, docDetailResponse)
+		adminResponse := response.AdminDocumentDetailResponse{DocumentDetailResponse: docDetailResponse}
+		if record, ok := moderationRecords[document.ID]; ok {
+			moderation := response.BuildModerationBriefResponse(record)
+			adminResponse.Moderation = &moderation
+		}
+		documentDetailResponses = append(documentDetailResponses, adminResponse)
 	}
 
 	// 返回成功响应
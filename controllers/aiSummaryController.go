@@ -6,18 +6,43 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
-	"github.com/antidote-kt/SSE_Library-back/config"
 	"github.com/antidote-kt/SSE_Library-back/constant"
 	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
 	"github.com/antidote-kt/SSE_Library-back/response"
 	"github.com/antidote-kt/SSE_Library-back/utils"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-// PostAISummary 查看/生成 AI 摘要：未要求 regenerate 且 Redis 中已有与当前正文 hash 一致的缓存则直接返回；否则调用 Chat 生成并写入 Redis（不新增业务表）。
+// parseSummaryStyle 校验摘要风格，为空时默认为一段话摘要
+func parseSummaryStyle(raw string) (string, bool) {
+	style := strings.ToLower(strings.TrimSpace(raw))
+	if style == "" {
+		return constant.SummaryStyleAbstract, true
+	}
+	_, ok := constant.SummaryStylePromptKeys[style]
+	return style, ok
+}
+
+// saveAISummary 将生成的摘要写入摘要表（保留历史），流式与非流式接口共用
+func saveAISummary(contentType string, contentID uint64, style, summaryText, srcHash, promptVersion string, creatorID uint64) (models.AISummary, error) {
+	summary := models.AISummary{
+		ContentType:   contentType,
+		ContentID:     contentID,
+		Style:         style,
+		Summary:       summaryText,
+		SrcHash:       srcHash,
+		Model:         utils.GetChatModelName(),
+		PromptVersion: promptVersion,
+		CreatorID:     creatorID,
+	}
+	err := dao.CreateAISummary(&summary)
+	return summary, err
+}
+
+// PostAISummary 查看/生成 AI 摘要：未要求 regenerate 且摘要表中该风格的最新摘要与当前正文 hash 一致则直接返回；否则调用 Chat 生成并保存（保留历史版本）。
 func PostAISummary(c *gin.Context) {
 	contentType := strings.ToLower(strings.TrimSpace(c.Param("contentType")))
 	if contentType != "document" && contentType != "post" {
@@ -33,9 +58,15 @@ func PostAISummary(c *gin.Context) {
 	}
 
 	var reqBody struct {
-		Regenerate bool `json:"regenerate"`
+		Regenerate bool   `json:"regenerate"`
+		Style      string `json:"style"` // 摘要风格：abstract（默认）、key_points、outline、glossary
 	}
 	_ = c.ShouldBindJSON(&reqBody)
+	style, ok := parseSummaryStyle(reqBody.Style)
+	if !ok {
+		response.Fail(c, http.StatusBadRequest, nil, constant.AISummaryInvalidStyle)
+		return
+	}
 
 	claims, exists := c.Get(constant.UserClaims)
 	if !exists {
@@ -44,7 +75,7 @@ func PostAISummary(c *gin.Context) {
 	}
 	userClaims := claims.(*utils.MyClaims)
 
-	var sourceText string
+	var sourceText, title string
 
 	switch contentType {
 	case "document":
@@ -57,10 +88,7 @@ func PostAISummary(c *gin.Context) {
 			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
 			return
 		}
-		can := doc.Status == constant.DocumentStatusOpen ||
-			doc.UploaderID == userClaims.UserID ||
-			userClaims.Role == "admin"
-		if !can {
+		if !canAccessDocument(doc, userClaims) {
 			response.Fail(c, http.StatusForbidden, nil, constant.DocumentSummaryAccessDenied)
 			return
 		}
@@ -68,7 +96,8 @@ func PostAISummary(c *gin.Context) {
 			response.Fail(c, http.StatusBadRequest, nil, constant.DocumentSummaryNotPDF)
 			return
 		}
-		sourceText, err = utils.ExtractDocumentPDFPlainText(utils.GetFileURL(doc.URL), documentSummaryMaxRunes)
+		title = doc.Name
+		sourceText, err = utils.ExtractDocumentPDFPlainText(utils.GetFileURL(doc.URL))
 		if err != nil {
 			if errors.Is(err, utils.ErrSummaryEmptyText) {
 				response.Fail(c, http.StatusBadRequest, nil, constant.DocumentSummaryNoText)
@@ -78,7 +107,8 @@ func PostAISummary(c *gin.Context) {
 				response.Fail(c, http.StatusBadRequest, nil, constant.DocumentSummaryNotPDF)
 				return
 			}
-			response.Fail(c, http.StatusBadGateway, nil, err.Error())
+			log.Printf("[AISummary] 提取文档 %d 正文失败: %v", contentID, err)
+			response.Fail(c, http.StatusBadGateway, nil, constant.DocumentSourceFetchFailed)
 			return
 		}
 
@@ -92,6 +122,11 @@ func PostAISummary(c *gin.Context) {
 			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
 			return
 		}
+		if !canViewPost(post, userClaims) {
+			response.Fail(c, http.StatusNotFound, nil, constant.PostNotExist)
+			return
+		}
+		title = post.Title
 		src := "标题：" + post.Title + "\n\n正文：\n" + post.Content
 		sourceText = utils.TruncateRunesForSummary(src, documentSummaryMaxRunes)
 		if strings.TrimSpace(sourceText) == "" {
@@ -101,50 +136,113 @@ func PostAISummary(c *gin.Context) {
 	}
 
 	srcHash := utils.HashSourceText(sourceText)
-	ctx := config.Ctx
 
 	if !reqBody.Regenerate {
-		cached, err := utils.GetAISummaryFromCache(ctx, contentType, contentID, srcHash)
+		latest, err := dao.GetLatestAISummary(contentType, contentID, style)
 		if err != nil {
-			log.Printf("[AISummary] redis get: %v", err)
-		} else if cached != nil {
-			response.SuccessWithDataCodeZero(c, response.AISummaryData{
-				FromCache:   true,
-				ContentType: contentType,
-				ContentID:   contentID,
-				SummaryID:   cached.SummaryID,
-				Summary:     cached.Summary,
-			}, constant.AISummarySuccess)
+			log.Printf("[AISummary] 查询已保存摘要失败: %v", err)
+		} else if latest != nil && latest.SrcHash == srcHash {
+			response.SuccessWithDataCodeZero(c, response.BuildAISummaryData(*latest, true), constant.AISummarySuccess)
 			return
 		}
 	}
 
-	userBlock := "请根据以下素材输出摘要：\n\n" + sourceText
+	prompts := resolveSummaryPrompts(style, sourceText)
+	userBlock, err := buildSummaryUserMessage(c.Request.Context(), title, sourceText, prompts, nil)
+	if err != nil {
+		log.Printf("[AISummary] %s %d 分段摘要失败: %v", contentType, contentID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.AISummaryGenerateFailed)
+		return
+	}
 	msgs := []utils.Message{
-		{Role: "system", Content: constant.DocumentSummarySystemPrompt},
+		{Role: "system", Content: prompts.System},
 		{Role: "user", Content: userBlock},
 	}
 	summaryText, err := utils.Chat(msgs)
 	if err != nil {
-		response.Fail(c, http.StatusInternalServerError, nil, err.Error())
+		log.Printf("[AISummary] %s %d 生成摘要失败: %v", contentType, contentID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.AISummaryGenerateFailed)
+		return
+	}
+
+	summary, err := saveAISummary(contentType, contentID, style, summaryText, srcHash, prompts.PromptVersion, userClaims.UserID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithDataCodeZero(c, response.BuildAISummaryData(summary, false), constant.AISummarySuccess)
+}
+
+// GetAISummaryHistory 查看内容的摘要历史（可按风格筛选），权限与生成摘要一致
+// GET /api/ai/summaries/:contentType/:contentId?style=outline
+func GetAISummaryHistory(c *gin.Context) {
+	contentType := strings.ToLower(strings.TrimSpace(c.Param("contentType")))
+	if contentType != "document" && contentType != "post" {
+		response.Fail(c, http.StatusBadRequest, nil, constant.AISummaryInvalidContentType)
+		return
+	}
+
+	contentID, err := strconv.ParseUint(strings.TrimSpace(c.Param("contentId")), 10, 64)
+	if err != nil || contentID == 0 {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
 		return
 	}
 
-	summaryID := uint64(time.Now().UnixNano())
-	cacheVal := &utils.AISummaryCacheValue{
-		Summary:   summaryText,
-		SummaryID: summaryID,
-		SrcHash:   srcHash,
+	style := ""
+	if c.Query("style") != "" {
+		var ok bool
+		style, ok = parseSummaryStyle(c.Query("style"))
+		if !ok {
+			response.Fail(c, http.StatusBadRequest, nil, constant.AISummaryInvalidStyle)
+			return
+		}
 	}
-	if err := utils.SetAISummaryCache(ctx, contentType, contentID, cacheVal); err != nil {
-		log.Printf("[AISummary] redis set: %v", err)
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	switch contentType {
+	case "document":
+		doc, err := dao.GetDocumentByID(contentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				response.Fail(c, http.StatusNotFound, nil, constant.DocumentNotExist)
+				return
+			}
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+			return
+		}
+		if !canAccessDocument(doc, userClaims) {
+			response.Fail(c, http.StatusForbidden, nil, constant.DocumentSummaryAccessDenied)
+			return
+		}
+
+	case "post":
+		post, err := dao.GetPostByID(contentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				response.Fail(c, http.StatusNotFound, nil, constant.PostNotExist)
+				return
+			}
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+			return
+		}
+		if !canViewPost(post, userClaims) {
+			response.Fail(c, http.StatusNotFound, nil, constant.PostNotExist)
+			return
+		}
+	}
+
+	summaries, err := dao.GetAISummaryHistory(contentType, contentID, style)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
 	}
 
-	response.SuccessWithDataCodeZero(c, response.AISummaryData{
-		FromCache:   false,
-		ContentType: contentType,
-		ContentID:   contentID,
-		SummaryID:   summaryID,
-		Summary:     summaryText,
-	}, constant.AISummarySuccess)
+	response.SuccessWithData(c, response.BuildAISummaryDataList(summaries), constant.GetAISummaryHistorySuccess)
 }
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetBookMetadata 根据 ISBN 查询书籍信息，用于上传书籍时自动填写
+// GET /api/isbn/:isbn
+func GetBookMetadata(c *gin.Context) {
+	isbn, ok := utils.NormalizeISBN(c.Param("isbn"))
+	if !ok {
+		response.Fail(c, http.StatusBadRequest, nil, constant.InvalidISBN)
+		return
+	}
+	metadata, err := lookupBookMetadata(isbn)
+	if errors.Is(err, utils.ErrBookNotFound) {
+		response.Fail(c, http.StatusNotFound, nil, constant.BookMetadataNotFound)
+		return
+	}
+	if err != nil {
+		response.Fail(c, http.StatusServiceUnavailable, nil, constant.BookCatalogueUnavailable)
+		return
+	}
+	response.SuccessWithData(c, metadata, constant.GetBookMetadataSuccess)
+}
+
+// lookupBookMetadata 根据 ISBN-13 查询书籍信息（结果有缓存），文本经过敏感词过滤：
+// 命中 reject 类的字段清空，命中 mask 类的字段打码
+func lookupBookMetadata(isbn string) (*utils.BookMetadata, error) {
+	found, err := utils.LookupBookMetadata(isbn)
+	if err != nil {
+		if !errors.Is(err, utils.ErrBookNotFound) {
+			log.Printf("[ISBN] 查询 %s 失败: %v", isbn, err)
+		}
+		return nil, err
+	}
+
+	metadata := *found
+	metadata.Title = maskCatalogueText(metadata.Title)
+	metadata.Publisher = maskCatalogueText(metadata.Publisher)
+	metadata.Description = maskCatalogueText(metadata.Description)
+	metadata.Authors = nil
+	for _, author := range found.Authors {
+		if author = maskCatalogueText(author); author != "" {
+			metadata.Authors = append(metadata.Authors, author)
+		}
+	}
+	return &metadata, nil
+}
+
+// maskCatalogueText 过滤目录服务返回的文本，命中 reject 类敏感词时返回空字符串
+func maskCatalogueText(text string) string {
+	masked, err := maskSensitiveText(text)
+	if err != nil {
+		return ""
+	}
+	return masked
+}
+
+// applyBookMetadata 用查询到的书籍信息补全文档中留空的名称、作者、出版社、年份和简介，不覆盖上传者填写的内容
+func applyBookMetadata(document *models.Document, metadata *utils.BookMetadata) {
+	if metadata == nil {
+		return
+	}
+	if document.Name == "" {
+		document.Name = metadata.Title
+	}
+	if document.Author == "" {
+		document.Author = metadata.AuthorNames()
+	}
+	if document.Publisher == "" {
+		document.Publisher = metadata.Publisher
+	}
+	if document.CreateYear == "" {
+		document.CreateYear = metadata.PublishYear
+	}
+	if document.Introduction == "" {
+		document.Introduction = metadata.Description
+	}
+}
+
+// downloadBookCover 有 ISBN 的书籍下载目录服务提供的封面，没有封面或下载失败时返回空字符串
+func downloadBookCover(document models.Document, category string) string {
+	if document.Type != constant.BookType || document.BookISBN == "" {
+		return ""
+	}
+	isbn, ok := utils.NormalizeISBN(document.BookISBN)
+	if !ok {
+		return ""
+	}
+	metadata, err := lookupBookMetadata(isbn)
+	if err != nil || metadata.CoverURL == "" {
+		return ""
+	}
+	cover, err := utils.DownloadCatalogueCover(metadata.CoverURL, category)
+	if err != nil {
+		log.Printf("[Cover] 文档 %d 下载 ISBN 封面失败: %v", document.ID, err)
+		return ""
+	}
+	return cover
+}
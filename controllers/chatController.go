@@ -272,6 +272,12 @@ func SendMessage(c *gin.Context) {
 		return
 	}
 
+	// 敏感词过滤
+	content, ok := filterSensitiveText(c, req.Content)
+	if !ok {
+		return
+	}
+
 	// 3. 获取当前登录用户ID
 	claims, exists := c.Get(constant.UserClaims)
 	if !exists {
@@ -339,7 +345,7 @@ func SendMessage(c *gin.Context) {
 	message := models.Message{
 		SessionID: targetSessionID,
 		SenderID:  currentUserID,
-		Content:   req.Content,
+		Content:   content,
 		Status:    "unread", // 默认为未读
 	}
 
@@ -348,6 +354,9 @@ func SendMessage(c *gin.Context) {
 		return
 	}
 
+	// 异步进行内容预审，疑似违规的私信进入人工复核队列
+	go moderateContent(constant.ModerationTypeMessage, message.ID, message.SenderID, message.Content)
+
 	// 6. 更新会话的最后活动时间
 	// 这样该会话在列表中就会排到最前面
 	if err := dao.UpdateSessionTime(targetSessionID); err != nil {
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetDocumentCitation 生成文档的引用（bibtex、ris、gbt7714、apa，默认 gbt7714）。
+// 开放的文档登录用户均可引用，其他状态的文档只有上传者和管理员可以引用
+// GET /api/document/:id/cite?format=bibtex
+func GetDocumentCitation(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	format, ok := citationFormat(c)
+	if !ok {
+		return
+	}
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.DocumentNotExist)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if !canAccessDocument(document, userClaims) {
+		response.Fail(c, http.StatusForbidden, nil, constant.CitationAccessDenied)
+		return
+	}
+
+	responseData := gin.H{
+		"format":   format,
+		"citation": utils.FormatCitation(document, format),
+	}
+	response.SuccessWithData(c, responseData, constant.GetCitationSuccess)
+}
+
+// ExportFavoriteCitations 将当前用户收藏的文档导出为引用文件（bibtex 为 .bib，ris 为 .ris，gbt7714 和 apa 为 .txt），
+// 跳过已不能访问的文档
+// GET /api/user/favorites/cite?format=ris
+func ExportFavoriteCitations(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	format, ok := citationFormat(c)
+	if !ok {
+		return
+	}
+	favorites, err := dao.GetFavoriteDocumentsByUserID(userClaims.UserID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.GetFavoriteDocumentFailed)
+		return
+	}
+	var documents []models.Document
+	for _, document := range favorites {
+		if canAccessDocument(document, userClaims) {
+			documents = append(documents, document)
+		}
+	}
+
+	fileType := constant.CitationFileTypes[format]
+	fileName := fmt.Sprintf(constant.CitationFileName, time.Now().Format("20060102"), fileType[0])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Data(http.StatusOK, fileType[1], []byte(utils.FormatCitations(documents, format)))
+}
+
+// citationFormat 读取并校验引用格式参数，不支持的格式已写入响应
+func citationFormat(c *gin.Context) (string, bool) {
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", constant.DefaultCitationFormat)))
+	if !utils.IsCitationFormat(format) {
+		response.Fail(c, http.StatusBadRequest, nil, constant.CitationFormatInvalid)
+		return "", false
+	}
+	return format, true
+}
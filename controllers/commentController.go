@@ -179,10 +179,16 @@ func PostComment(c *gin.Context) {
 		}
 	}
 
+	// 敏感词过滤
+	content, ok := filterSensitiveText(c, request.Content)
+	if !ok {
+		return
+	}
+
 	// 创建评论
 	comment := &models.Comment{
 		UserID:     request.Commenter.UserID,
-		Content:    request.Content,
+		Content:    content,
 		SourceID:   sourceID,
 		SourceType: sourceType,
 		ParentID:   request.ParentID,
@@ -194,6 +200,9 @@ func PostComment(c *gin.Context) {
 		return
 	}
 
+	// 异步进行内容预审，高风险评论会被自动隐藏
+	go moderateContent(constant.ModerationTypeComment, comment.ID, comment.UserID, comment.Content)
+
 	// 将评论结果处理成通知格式并插入通知表
 	// 1.检查评论的对象资源类型（是document还是post）
 	// 2.根据类型查找资源
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// courseSyllabusMaxDocuments 自动生成课程大纲时最多列出的资料数量
+const courseSyllabusMaxDocuments = 20
+
+// courseTutorContext 课程助教会话的提示词和检索范围
+type courseTutorContext struct {
+	SystemPrompt  string
+	PromptVersion string
+	FileIDs       []int64 // 课程子树下的公开文档ID，检索只在这些文档中进行
+}
+
+// buildCourseTutorContext 根据课程分类构建助教系统提示词（人设 + 课程大纲）及检索范围
+func buildCourseTutorContext(categoryID uint64) (courseTutorContext, error) {
+	course, err := dao.GetCategoryByID(categoryID)
+	if err != nil {
+		return courseTutorContext{}, err
+	}
+	persona, err := dao.GetCoursePersonaByCategoryID(categoryID)
+	if err != nil {
+		return courseTutorContext{}, err
+	}
+	documents, err := dao.GetOpenDocumentsInCategoryTree(categoryID)
+	if err != nil {
+		return courseTutorContext{}, err
+	}
+
+	fileIDs := make([]int64, len(documents))
+	for i, doc := range documents {
+		fileIDs[i] = int64(doc.ID)
+	}
+
+	vars := map[string]string{
+		"CourseName":        course.Name,
+		"CourseDescription": course.Description,
+	}
+	if persona != nil {
+		vars["Persona"] = persona.Persona
+		vars["Syllabus"] = persona.Syllabus
+	}
+	// 管理员未填写大纲时，使用课程下的子分类和资料列表作为大纲
+	if vars["Syllabus"] == "" {
+		syllabus, err := buildCourseSyllabus(course, documents)
+		if err != nil {
+			return courseTutorContext{}, err
+		}
+		vars["Syllabus"] = syllabus
+	}
+
+	systemPrompt, promptVersion := renderPrompt(constant.PromptKeyCourseTutorSystem, vars)
+	return courseTutorContext{
+		SystemPrompt:  systemPrompt,
+		PromptVersion: promptVersion,
+		FileIDs:       fileIDs,
+	}, nil
+}
+
+// buildCourseSyllabus 根据课程的直接子分类和阅读量最高的资料生成课程大纲
+func buildCourseSyllabus(course models.Category, documents []models.Document) (string, error) {
+	children, err := dao.GetCategoriesByParentID(course.ID)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if len(children) > 0 {
+		builder.WriteString("课程章节：\n")
+		for _, child := range children {
+			builder.WriteString("- " + child.Name)
+			if child.Description != "" {
+				builder.WriteString("：" + child.Description)
+			}
+			builder.WriteString("\n")
+		}
+	}
+	if len(documents) > 0 {
+		builder.WriteString("课程资料：\n")
+		for i, doc := range documents {
+			if i >= courseSyllabusMaxDocuments {
+				break
+			}
+			builder.WriteString(fmt.Sprintf("- 《%s》 %s\n", doc.Name, doc.Author))
+		}
+	}
+	return strings.TrimSpace(builder.String()), nil
+}
+
+// getCourseByParam 从路径参数中取出课程分类并校验其为课程，校验失败时已写入响应
+func getCourseByParam(c *gin.Context) (models.Category, bool) {
+	categoryID, err := strconv.ParseUint(c.Param("categoryId"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.Category{}, false
+	}
+	course, err := dao.GetCategoryByID(categoryID)
+	if err != nil {
+		response.Fail(c, http.StatusNotFound, nil, constant.CategoryNotExist)
+		return models.Category{}, false
+	}
+	if !course.IsCourse {
+		response.Fail(c, http.StatusBadRequest, nil, constant.CategoryNotCourse)
+		return models.Category{}, false
+	}
+	return course, true
+}
+
+// AdminGetCoursePersona 管理员获取课程的助教设定
+// GET /api/admin/category/:categoryId/persona
+func AdminGetCoursePersona(c *gin.Context) {
+	course, ok := getCourseByParam(c)
+	if !ok {
+		return
+	}
+
+	persona, err := dao.GetCoursePersonaByCategoryID(course.ID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildCoursePersonaResponse(course, persona), constant.GetCoursePersonaSuccess)
+}
+
+// AdminUpdateCoursePersona 管理员修改课程的助教人设和课程大纲
+// PUT /api/admin/category/:categoryId/persona
+func AdminUpdateCoursePersona(c *gin.Context) {
+	course, ok := getCourseByParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCoursePersonaDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	persona := models.CoursePersona{
+		CategoryID: course.ID,
+		Persona:    req.Persona,
+		Syllabus:   req.Syllabus,
+		UpdaterID:  userClaims.UserID,
+	}
+	if err := dao.SaveCoursePersona(&persona); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UpdateCoursePersonaFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildCoursePersonaResponse(course, &persona), constant.UpdateCoursePersonaSuccess)
+}
@@ -3,8 +3,11 @@ package controllers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/antidote-kt/SSE_Library-back/config"
 	"github.com/antidote-kt/SSE_Library-back/constant"
@@ -22,8 +25,6 @@ func ModifyDocument(c *gin.Context) {
 	var request dto.ModifyDocumentDTO
 	// 声明分类模型用于后续获取分类信息
 	var category models.Category
-	// 记录旧状态，用于判断是否需要删除原始文件
-	var oldType string
 	// 获取数据库连接实例
 	db := config.GetDB()
 
@@ -56,8 +57,10 @@ func ModifyDocument(c *gin.Context) {
 		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
 		return
 	}
-	// 记录修改前的文档类型，用于后续文件处理判断
-	oldType = document.Type
+	// 记录修改前的文档，替换文件或封面时旧文件保留为历史版本
+	original := document
+	// 记录修改前参与书籍向量化的字段，变化后需要重新向量化
+	oldVectorText := fmt.Sprintf("%s %d %s", document.Name, document.CategoryID, document.Introduction)
 
 	// 动态更新文档字段（仅更新客户端提供的字段）
 	if request.Author != nil {
@@ -80,62 +83,178 @@ func ModifyDocument(c *gin.Context) {
 		document.CreateYear = *request.CreateYear
 	}
 	if request.ISBN != nil {
-		document.BookISBN = *request.ISBN
+		// 传空串表示清除 ISBN
+		isbn := ""
+		if strings.TrimSpace(*request.ISBN) != "" {
+			var ok bool
+			if isbn, ok = utils.NormalizeISBN(*request.ISBN); !ok {
+				response.Fail(c, http.StatusBadRequest, nil, constant.InvalidISBN)
+				return
+			}
+		}
+		document.BookISBN = isbn
+	}
+	if request.Publisher != nil {
+		document.Publisher = *request.Publisher
 	}
 	if request.Name != nil {
-		document.Name = *request.Name
+		name, ok := filterSensitiveText(c, *request.Name)
+		if !ok {
+			return
+		}
+		document.Name = name
 	}
 	if request.Type != nil {
 		document.Type = *request.Type
 	}
 	if request.Introduction != nil {
-		document.Introduction = *request.Introduction
+		introduction, ok := filterSensitiveText(c, *request.Introduction)
+		if !ok {
+			return
+		}
+		document.Introduction = introduction
+	}
+	if request.Difficulty != nil {
+		// 传空串表示清除难度
+		if *request.Difficulty != "" && !isValidDifficulty(*request.Difficulty) {
+			response.Fail(c, http.StatusBadRequest, nil, constant.InvalidDifficulty)
+			return
+		}
+		document.Difficulty = *request.Difficulty
 	}
 
-	// 处理封面图片更新
-	if request.Cover != nil {
-		// 先删除旧封面，如果document.cover为空串则不做任何操作
-		err := utils.DeleteFile(document.Cover)
+	// 采纳上传后 AI 生成的元数据建议（客户端显式传入的字段优先，不会被建议覆盖）
+	var suggestion *models.DocumentMetadataSuggestion
+	var acceptedFields []string
+	if request.AcceptSuggestions != "" {
+		fields, err := parseSuggestionFields(request.AcceptSuggestions)
 		if err != nil {
-			response.Fail(c, http.StatusInternalServerError, nil, constant.OldCoverDeleteFailed)
+			response.Fail(c, http.StatusBadRequest, nil, err.Error())
 			return
 		}
-		// 上传新封面图片到指定分类目录
-		document.Cover, err = utils.UploadCoverImage(request.Cover, category.Name)
+		suggestion, err = dao.GetMetadataSuggestionByDocumentID(document.ID)
 		if err != nil {
-			response.Fail(c, http.StatusInternalServerError, nil, err.Error())
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+			return
+		}
+		if suggestion == nil {
+			response.Fail(c, http.StatusNotFound, nil, constant.MetadataSuggestionNotExist)
 			return
 		}
-	}
 
-	// 处理文档文件或视频链接更新
-	if request.File != nil || request.VideoURL != nil {
-		// 只有原来的类型不是video才删除，如果原来是video，cos没有相应的资源
-		if oldType != constant.VideoType {
-			// 删除旧文件
-			err := utils.DeleteFile(document.URL)
-			if err != nil {
-				response.Fail(c, http.StatusInternalServerError, nil, constant.OldDocumentDeleteFailed)
+		if fields[constant.SuggestionFieldIntroduction] && request.Introduction == nil && suggestion.Introduction != "" {
+			document.Introduction = suggestion.Introduction
+			acceptedFields = append(acceptedFields, constant.SuggestionFieldIntroduction)
+		}
+		if fields[constant.SuggestionFieldDifficulty] && request.Difficulty == nil && suggestion.Difficulty != "" {
+			document.Difficulty = suggestion.Difficulty
+			acceptedFields = append(acceptedFields, constant.SuggestionFieldDifficulty)
+		}
+		if fields[constant.SuggestionFieldCategory] && request.CategoryID == nil && suggestion.SuggestedCategoryID != nil {
+			suggested, err := dao.GetCategoryByID(*suggestion.SuggestedCategoryID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
 				return
 			}
+			// 建议的分类已被删除时忽略该字段
+			if err == nil {
+				category = suggested
+				document.CategoryID = category.ID
+				acceptedFields = append(acceptedFields, constant.SuggestionFieldCategory)
+			}
+		}
+		if fields[constant.SuggestionFieldTags] && !hasTagsField {
+			var suggestedTags []string
+			_ = json.Unmarshal([]byte(suggestion.Tags), &suggestedTags)
+			if len(suggestedTags) > 0 {
+				// 建议标签与现有标签合并，不会清除上传者已填写的标签
+				currentTags, err := dao.GetDocumentTagByDocumentID(document.ID)
+				if err != nil {
+					response.Fail(c, http.StatusInternalServerError, nil, err.Error())
+					return
+				}
+				seen := make(map[string]bool)
+				for _, tag := range currentTags {
+					seen[tag.TagName] = true
+					tags = append(tags, tag.TagName)
+				}
+				for _, tag := range suggestedTags {
+					if !seen[tag] {
+						seen[tag] = true
+						tags = append(tags, tag)
+					}
+				}
+				hasTagsField = true
+				acceptedFields = append(acceptedFields, constant.SuggestionFieldTags)
+			}
 		}
+	}
 
-		// 更新文档URL：如果是视频URL则直接赋值，否则上传新文件
+	// 上传前按文件内容校验新文件和新封面，不合格的文件不会写入存储
+	var mimeType string
+	if request.File != nil && request.VideoURL == nil {
+		checked, err := utils.ValidateUploadFile(request.File, document.Type)
+		if err != nil {
+			failUploadCheck(c, err)
+			return
+		}
+		mimeType = checked.MIME
+	}
+	if request.Cover != nil {
+		if _, err := utils.ValidateCoverImage(request.Cover); err != nil {
+			failUploadCheck(c, err)
+			return
+		}
+	}
+
+	// 处理封面图片更新
+	if request.Cover != nil {
+		// 上传新封面图片到指定分类目录（旧封面保留在历史版本中）
+		document.Cover, err = utils.UploadCoverImage(request.Cover, category.Name)
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, nil, err.Error())
+			return
+		}
+	}
+
+	// 处理文档文件或视频链接更新
+	fileChanged := request.File != nil || request.VideoURL != nil
+	newVersion := models.DocumentVersion{ChangeNote: request.ChangeNote}
+	if fileChanged {
+		// 更新文档URL：如果是视频URL则直接赋值，否则上传新文件（旧文件保留在历史版本中）
 		if request.VideoURL != nil {
 			document.URL = *request.VideoURL
 		} else if request.File != nil {
+			newVersion.FileSize = request.File.Size
+			newVersion.FileHash, err = utils.HashUploadedFile(request.File)
+			if err != nil {
+				response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentOpenFailed)
+				return
+			}
 			document.URL, err = utils.UploadMainFile(request.File, category.Name)
 			if err != nil {
 				response.Fail(c, http.StatusInternalServerError, nil, err.Error())
 				return
 			}
 		}
+		// 文件元数据改为新文件的，页数、字数、语言等在重新学习时提取
+		resetDocumentFileMetadata(&document, newVersion.FileSize, newVersion.FileHash, mimeType)
 		// 重新上传文件后需要重新审核
 		document.Status = constant.DocumentStatusPending
 	}
 
 	// 使用事务确保数据一致性：更新文档信息和标签映射
 	err = db.Transaction(func(tx *gorm.DB) error {
+		// 替换了文件或封面时记录新版本
+		if fileChanged || request.Cover != nil {
+			if claims, exists := c.Get(constant.UserClaims); exists {
+				newVersion.UploaderID = claims.(*utils.MyClaims).UserID
+			}
+			if err := createDocumentVersionWithTx(tx, original, &document, newVersion); err != nil {
+				return err
+			}
+		}
+
 		// 在事务中更新文档信息
 		if err := dao.UpdateDocumentWithTx(tx, document); err != nil {
 			return err
@@ -155,6 +274,19 @@ func ModifyDocument(c *gin.Context) {
 			}
 			// 如果 tags 为空数组，只删除不创建，实现清空标签的效果
 		}
+
+		// 记录已采纳的建议字段（与之前采纳过的字段合并）
+		if suggestion != nil && len(acceptedFields) > 0 {
+			for _, field := range strings.Split(suggestion.AcceptedFields, ",") {
+				if field != "" && !slices.Contains(acceptedFields, field) {
+					acceptedFields = append(acceptedFields, field)
+				}
+			}
+			suggestion.Status = constant.MetadataSuggestionAccepted
+			if err := dao.MarkMetadataSuggestionAcceptedWithTx(tx, suggestion, strings.Join(acceptedFields, ",")); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 
@@ -176,6 +308,28 @@ func ModifyDocument(c *gin.Context) {
 		return
 	}
 
+	// 文件变化后重新向量化正文并重新预审
+	if fileChanged {
+		go reindexDocument(document)
+	}
+	// 开放的文档重新进入审核后不能再被推荐
+	if original.Status == constant.DocumentStatusOpen && document.Status != constant.DocumentStatusOpen {
+		markRecommendationsStale()
+	}
+
+	// 仍然没有封面时根据新文件生成封面
+	if document.Cover == "" {
+		if category.ID == 0 {
+			category, _ = dao.GetCategoryByID(document.CategoryID)
+		}
+		go generateDocumentCover(document, category.Name)
+	}
+
+	// 书籍的名称、分类或简介变化后重新向量化（用于推荐）
+	if document.Type == "book" && fmt.Sprintf("%s %d %s", document.Name, document.CategoryID, document.Introduction) != oldVectorText {
+		go refreshBookVector(document)
+	}
+
 	// 返回成功响应
 	response.Success(c, nil, constant.DocumentUpdateSuccess)
 }
@@ -204,24 +358,26 @@ func GetDocumentByID(c *gin.Context) {
 		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
 		return
 	}
-	// 更新阅读量
+	// 更新阅读量（原子更新，不覆盖下载次数等同时被修改的字段）
 	document.ReadCounts++
-	if err := dao.UpdateDocument(document); err != nil {
+	if err := dao.IncrementDocumentViewCount(document.ID); err != nil {
 		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentUpdateFail)
 	}
 
 	// 记录浏览历史 (异步执行)
 	// 从JWT解析用户信息
+	var userClaims *utils.MyClaims
 	if claims, exists := c.Get(constant.UserClaims); exists {
-		userClaims := claims.(*utils.MyClaims)
+		userClaims = claims.(*utils.MyClaims)
 		go func(uid uint64, sourceID uint64) {
 			// 传入 "document" 类型
 			_ = dao.AddViewHistory(uid, sourceID, "document")
+			attributeRecommendClick(uid, constant.DocumentType, sourceID)
 		}(userClaims.UserID, documentID) // 回调函数实现异步
 	}
 
 	// 构建文档详情响应数据结构
-	docDetailResponse, err := response.BuildDocumentDetailResponse(document)
+	docDetailResponse, err := response.BuildDocumentDetailResponseForViewer(document, userClaims)
 	if err != nil {
 		// 如果构建响应数据失败，返回数据库错误
 		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
@@ -245,6 +401,10 @@ func SearchDocument(c *gin.Context) {
 		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
 		return
 	}
+	if request.MinPages != nil && request.MaxPages != nil && *request.MinPages > *request.MaxPages {
+		response.Fail(c, http.StatusBadRequest, nil, constant.InvalidPageRange)
+		return
+	}
 
 	// 如果请求中包含分类ID参数，验证分类是否存在
 	if request.CategoryID != nil {
@@ -305,7 +465,7 @@ func GetDocumentList(c *gin.Context) {
 	}
 
 	// 3. 调用DAO获取文档列表
-	documents, err := dao.GetDocumentList(isSuggest, req.CategoryID)
+	documents, err := dao.GetDocumentList(isSuggest, req.CategoryID, req.Sort)
 	if err != nil {
 		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
 		return
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// generateDocumentCover 为没有封面的文档生成封面（有 ISBN 的书籍优先使用图书目录服务的封面，
+// 否则 PDF 渲染首页，其他类型生成文字封面），在后台执行
+func generateDocumentCover(document models.Document, category string) {
+	cover := downloadBookCover(document, category)
+	if cover == "" {
+		var err error
+		cover, err = utils.GenerateDocumentCover(document, category)
+		if err != nil {
+			log.Printf("[Cover] 文档 %d 生成封面失败: %v", document.ID, err)
+			return
+		}
+	}
+	updated, err := dao.SetGeneratedDocumentCover(document.ID, cover)
+	if err != nil || !updated {
+		// 生成期间上传者已设置了封面，删除生成的封面
+		_ = utils.DeleteFileWithThumbnails(cover)
+		if err != nil {
+			log.Printf("[Cover] 文档 %d 保存封面失败: %v", document.ID, err)
+		}
+		return
+	}
+	log.Printf("[Cover] 文档 %d 已生成封面 %s", document.ID, cover)
+}
+
+// AdminGenerateCovers 为没有封面的历史文档生成封面，并为缺少缩略图的封面和头像补全缩略图，在后台执行
+// POST /api/admin/covers/generate
+func AdminGenerateCovers(c *gin.Context) {
+	documents, err := dao.GetDocumentsWithoutCover()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	covers, err := dao.GetCoverPaths()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	users, err := dao.GetUsersList()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	go func() {
+		categoryNames := make(map[uint64]string)
+		for _, document := range documents {
+			name, ok := categoryNames[document.CategoryID]
+			if !ok {
+				category, err := dao.GetCategoryByID(document.CategoryID)
+				if err != nil {
+					log.Printf("[Cover] 文档 %d 的分类不存在，跳过", document.ID)
+					continue
+				}
+				name = category.Name
+				categoryNames[document.CategoryID] = name
+			}
+			generateDocumentCover(document, name)
+		}
+
+		thumbnails := 0
+		for _, cover := range covers {
+			if generateMissingThumbnails(cover, constant.CoverVariantWidths) {
+				thumbnails++
+			}
+		}
+		for _, user := range users {
+			if user.Avatar != "" && generateMissingThumbnails(user.Avatar, constant.AvatarVariantWidths) {
+				thumbnails++
+			}
+		}
+		log.Printf("[Cover] 补全封面完成：处理没有封面的文档 %d 个，补全缩略图 %d 张图片", len(documents), thumbnails)
+	}()
+
+	response.SuccessWithData(c, gin.H{"documents": len(documents), "covers": len(covers), "users": len(users)}, constant.GenerateCoversStarted)
+}
+
+// generateMissingThumbnails 图片还没有缩略图时生成，返回是否生成了缩略图
+func generateMissingThumbnails(key string, widths map[string]int) bool {
+	exists, err := utils.ThumbnailsExist(key)
+	if err != nil || exists {
+		return false
+	}
+	if err := utils.GenerateStoredThumbnails(key, widths); err != nil {
+		log.Printf("[Cover] 生成 %s 的缩略图失败: %v", key, err)
+		return false
+	}
+	return true
+}
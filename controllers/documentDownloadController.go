@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetDocumentDownload 获取文档限时有效的下载地址并计入下载次数。
+// 开放的文档登录用户均可下载，审核中、已关闭、已撤回的文档只有上传者和管理员可以下载。
+// 未开启水印时返回对象存储的签名地址；开启水印时 PDF 返回带下载者水印的文件地址，两种地址都支持 Range 请求，可在浏览器中直接预览
+// GET /api/document/:id/download
+func GetDocumentDownload(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.DocumentNotExist)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if document.Type == constant.VideoType {
+		response.Fail(c, http.StatusBadRequest, nil, constant.DocumentDownloadNotSupported)
+		return
+	}
+	if !canAccessDocument(document, userClaims) {
+		response.Fail(c, http.StatusForbidden, nil, constant.DocumentDownloadDenied)
+		return
+	}
+
+	expire := utils.DownloadURLExpire()
+	watermarked := utils.WatermarkEnabled() && utils.IsPDFDocument(document)
+	var fileURL string
+	if watermarked {
+		user, err := dao.GetUserByID(userClaims.UserID)
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+			return
+		}
+		// 先生成带水印的文件，访问地址被请求时直接使用缓存
+		if _, err := utils.PrepareWatermarkedPDF(document, user.ID, user.Username); err != nil {
+			log.Printf("[Download] 文档 %d 添加水印失败: %v", document.ID, err)
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentWatermarkFailed)
+			return
+		}
+		fileURL = utils.SignDocumentFileURL(document.ID, user.ID, expire)
+	} else {
+		fileURL, err = utils.GetSignedFileURL(document.URL, expire)
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentDownloadFailed)
+			return
+		}
+	}
+
+	countDocumentDownload(document, userClaims.UserID, watermarked, c.ClientIP())
+
+	responseData := gin.H{
+		"url":         fileURL,
+		"expiresAt":   time.Now().Add(expire).Format("2006-01-02 15:04:05"),
+		"watermarked": watermarked,
+	}
+	response.SuccessWithData(c, responseData, constant.GetDocumentDownloadSuccess)
+}
+
+// ServeDocumentFile 提供带下载者水印的 PDF，地址由 GetDocumentDownload 签发，不需要登录，支持 Range 请求
+// GET /api/document/:id/file?user=&expires=&signature=
+func ServeDocumentFile(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	userID, ok := utils.VerifyDocumentFileSignature(documentID, c.Query("user"), c.Query("expires"), c.Query("signature"))
+	if !ok {
+		response.Fail(c, http.StatusForbidden, nil, constant.StorageSignatureInvalid)
+		return
+	}
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.DocumentNotExist)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	// 下载者被封禁、文档被关闭或撤回后，已签发的地址随即失效（上传者和管理员仍可访问，与签发时的检查一致）
+	user, err := dao.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusForbidden, nil, constant.DocumentDownloadDenied)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if user.Status != "active" || !canAccessDocument(document, &utils.MyClaims{UserID: user.ID, Role: user.Role}) {
+		response.Fail(c, http.StatusForbidden, nil, constant.DocumentDownloadDenied)
+		return
+	}
+
+	filePath, err := utils.PrepareWatermarkedPDF(document, user.ID, user.Username)
+	if err != nil {
+		log.Printf("[Download] 文档 %d 添加水印失败: %v", document.ID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentWatermarkFailed)
+		return
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentWatermarkFailed)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentWatermarkFailed)
+		return
+	}
+
+	c.Header("Content-Type", constant.MIMEPDF)
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": document.Name + ".pdf"}))
+	c.Header("Cache-Control", "private, no-store")
+	http.ServeContent(c.Writer, c.Request, "", info.ModTime(), file)
+}
+
+// countDocumentDownload 记录下载并增加下载次数，同一用户在 DownloadDedupeMinutes 分钟内重复获取下载地址只计一次
+func countDocumentDownload(document models.Document, userID uint64, watermarked bool, ip string) {
+	key := fmt.Sprintf("%s%d:%d", constant.DownloadDedupeKeyPrefix, document.ID, userID)
+	first, err := config.GetRedisClient().SetNX(config.Ctx, key, 1, constant.DownloadDedupeMinutes*time.Minute).Result()
+	if err != nil {
+		// Redis 不可用时不去重
+		log.Printf("[Download] 下载计数去重失败: %v", err)
+		first = true
+	}
+	if !first {
+		return
+	}
+	err = dao.RecordDocumentDownload(&models.DocumentDownload{
+		DocumentID:  document.ID,
+		UserID:      userID,
+		Version:     document.Version,
+		Watermarked: watermarked,
+		IP:          ip,
+	})
+	if err != nil {
+		log.Printf("[Download] 记录文档 %d 的下载失败: %v", document.ID, err)
+	}
+}
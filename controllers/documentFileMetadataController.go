@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// resetDocumentFileMetadata 替换文件后记录新文件的大小、哈希和类型，并清空需要重新提取的页数、字数、语言和 PDF 信息
+func resetDocumentFileMetadata(document *models.Document, fileSize int64, fileHash string, mimeType string) {
+	utils.DocumentFileMetadata{FileSize: fileSize, ContentHash: fileHash, MimeType: mimeType}.ApplyTo(document)
+}
+
+// updateDocumentFileMetadata 从已下载到本地的文档文件提取技术元数据并保存，text 为已提取的正文（视频不处理）
+func updateDocumentFileMetadata(documentID uint64, filePath string, text string) {
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil || document.Type == constant.VideoType {
+		return
+	}
+	metadata, err := utils.ExtractDocumentFileMetadata(filePath, text)
+	if err != nil {
+		log.Printf("[FileMetadata] 文档 %d 提取文件元数据失败: %v", documentID, err)
+		return
+	}
+	metadata.ApplyTo(&document)
+	if err := dao.UpdateDocumentFileMetadata(documentID, document); err != nil {
+		log.Printf("[FileMetadata] 文档 %d 保存文件元数据失败: %v", documentID, err)
+	}
+}
+
+// AdminRefreshDocumentFileMetadata 为还没有文件元数据的历史文档提取页数、字数、语言等信息，在后台执行
+// POST /api/admin/documents/file-metadata/refresh
+func AdminRefreshDocumentFileMetadata(c *gin.Context) {
+	documents, err := dao.GetDocumentsWithoutFileMetadata()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	go func() {
+		for _, document := range documents {
+			tmpPath, err := utils.DownloadFromCOSToTemp(document.URL)
+			if err != nil {
+				log.Printf("[FileMetadata] 下载文档 %d 失败: %v", document.ID, err)
+				continue
+			}
+			// 非 PDF 或扫描件提取不到正文时只记录文件本身的信息
+			text, _ := utils.ExtractTextFromPDF(tmpPath)
+			updateDocumentFileMetadata(document.ID, tmpPath, text)
+			os.Remove(tmpPath)
+		}
+		log.Printf("[FileMetadata] 补全 %d 个文档的文件元数据完成", len(documents))
+	}()
+
+	response.SuccessWithData(c, gin.H{"documents": len(documents)}, constant.RefreshFileMetadataStarted)
+}
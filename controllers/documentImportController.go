@@ -0,0 +1,470 @@
+package controllers
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+var importYearPattern = regexp.MustCompile(`^\d{4}$`)
+
+// importContext 一次批量导入中各行共用的状态
+type importContext struct {
+	job        *utils.DocumentImportJob
+	archive    *zip.Reader
+	categories map[string]*models.Category // 分类路径 -> 分类，找不到时为 nil
+	hashes     map[string]int              // 文件 SHA-256 -> 已导入（试运行时为已通过校验）的清单行号，检查清单内的重复文件
+}
+
+// AdminImportDocuments 管理员批量导入文档：上传 ZIP 压缩包和 CSV/JSON 清单（列：file、name、author、isbn、category、tags、type、year、introduction），
+// 在后台逐行校验并创建文档，每个文档与单个上传一样进入审核并学习、生成封面、书籍向量化。
+// dryRun 为 true 时只校验清单和文件，不创建文档。返回任务 ID，通过 GET /api/admin/documents/import/:jobId 查询进度
+// POST /api/admin/documents/import
+func AdminImportDocuments(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.ImportDocumentsDTO
+	if err := c.ShouldBind(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if req.Archive.Size > constant.MaxImportArchiveSize {
+		response.Fail(c, http.StatusRequestEntityTooLarge, nil, constant.ImportArchiveTooLarge)
+		return
+	}
+
+	// 压缩包保存到临时文件，后台任务完成后删除
+	archiveFile, err := os.CreateTemp("", "import-*.zip")
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentOpenFailed)
+		return
+	}
+	archivePath := archiveFile.Name()
+	archiveFile.Close()
+	if err := c.SaveUploadedFile(req.Archive, archivePath); err != nil {
+		os.Remove(archivePath)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentOpenFailed)
+		return
+	}
+	rows, ok := readImportManifest(c, archivePath, req.Manifest)
+	if !ok {
+		os.Remove(archivePath)
+		return
+	}
+
+	job := &utils.DocumentImportJob{
+		ID:        utils.NewUploadID(),
+		Status:    constant.ImportJobRunning,
+		DryRun:    req.DryRun,
+		CreatedBy: userClaims.UserID,
+		Total:     len(rows),
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := utils.SaveDocumentImportJob(job); err != nil {
+		os.Remove(archivePath)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	go runDocumentImport(job, archivePath, rows)
+
+	message := constant.DocumentImportStarted
+	if req.DryRun {
+		message = constant.DocumentImportDryRunStarted
+	}
+	response.SuccessWithData(c, response.BuildDocumentImportJobResponse(job), message)
+}
+
+// AdminGetDocumentImport 查询批量导入任务的进度和结果
+// GET /api/admin/documents/import/:jobId
+func AdminGetDocumentImport(c *gin.Context) {
+	job, ok := getDocumentImportJobParam(c)
+	if !ok {
+		return
+	}
+	response.SuccessWithData(c, response.BuildDocumentImportJobResponse(job), constant.GetDocumentImportSuccess)
+}
+
+// AdminGetDocumentImportReport 下载批量导入的错误报告（CSV：行号、文件、名称、错误原因）
+// GET /api/admin/documents/import/:jobId/report
+func AdminGetDocumentImportReport(c *gin.Context) {
+	job, ok := getDocumentImportJobParam(c)
+	if !ok {
+		return
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fmt.Sprintf(constant.ImportReportFileName, job.ID)))
+	c.Status(http.StatusOK)
+	if err := utils.WriteDocumentImportReport(c.Writer, job); err != nil {
+		log.Printf("[Import] 写出任务 %s 的错误报告失败: %v", job.ID, err)
+	}
+}
+
+// getDocumentImportJobParam 获取路径中的批量导入任务（失败时已写入响应）
+func getDocumentImportJobParam(c *gin.Context) (*utils.DocumentImportJob, bool) {
+	job, err := utils.GetDocumentImportJob(c.Param("jobId"))
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return nil, false
+	}
+	if job == nil {
+		response.Fail(c, http.StatusNotFound, nil, constant.DocumentImportNotExist)
+		return nil, false
+	}
+	return job, true
+}
+
+// RecoverDocumentImportJobs 启动时将上次运行中断的批量导入任务标记为失败，以免任务一直显示为执行中
+func RecoverDocumentImportJobs() {
+	count, err := utils.FailInterruptedImportJobs()
+	if err != nil {
+		log.Printf("[Import] 标记中断的导入任务失败: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("[Import] 已将 %d 个中断的导入任务标记为失败", count)
+	}
+}
+
+// readImportManifest 读取并解析清单：优先使用单独上传的清单，否则使用压缩包根目录的 manifest.csv / manifest.json（失败时已写入响应）
+func readImportManifest(c *gin.Context, archivePath string, manifest *multipart.FileHeader) ([]utils.DocumentImportRow, bool) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ImportArchiveInvalid)
+		return nil, false
+	}
+	defer archive.Close()
+
+	var name string
+	var data []byte
+	if manifest != nil {
+		if manifest.Size > constant.MaxImportManifestSize {
+			response.Fail(c, http.StatusBadRequest, nil, constant.ImportManifestInvalid)
+			return nil, false
+		}
+		reader, err := manifest.Open()
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, nil, constant.ImportManifestInvalid)
+			return nil, false
+		}
+		defer reader.Close()
+		if data, err = io.ReadAll(reader); err != nil {
+			response.Fail(c, http.StatusBadRequest, nil, constant.ImportManifestInvalid)
+			return nil, false
+		}
+		name = manifest.Filename
+	} else {
+		var found bool
+		name, data, found, err = utils.FindImportManifest(&archive.Reader)
+		if err != nil {
+			response.Fail(c, http.StatusBadRequest, nil, constant.ImportArchiveInvalid)
+			return nil, false
+		}
+		if !found {
+			response.Fail(c, http.StatusBadRequest, nil, constant.ImportManifestMissing)
+			return nil, false
+		}
+	}
+
+	rows, err := utils.ParseImportManifest(name, data)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, err.Error())
+		return nil, false
+	}
+	if len(rows) > constant.MaxImportRows {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ImportTooManyRows)
+		return nil, false
+	}
+	return rows, true
+}
+
+// runDocumentImport 后台逐行导入，每处理一行保存一次进度。
+// 创建的文档交给 import.pipeline_workers 个 goroutine 学习、生成封面和向量化，全部完成后任务才结束
+func runDocumentImport(job *utils.DocumentImportJob, archivePath string, rows []utils.DocumentImportRow) {
+	defer os.Remove(archivePath)
+
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		job.Status = constant.ImportJobFailed
+		job.Message = constant.ImportArchiveInvalid
+		job.FinishedAt = time.Now().Unix()
+		saveDocumentImportJob(job)
+		return
+	}
+	defer archive.Close()
+
+	workers := viper.GetInt("import.pipeline_workers")
+	if workers <= 0 {
+		workers = constant.DefaultImportPipelineWorkers
+	}
+	type importedDocument struct {
+		document     models.Document
+		categoryName string
+	}
+	pipeline := make(chan importedDocument)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for imported := range pipeline {
+				runImportedDocumentPipeline(imported.document, imported.categoryName)
+			}
+		}()
+	}
+
+	ctx := &importContext{
+		job:        job,
+		archive:    &archive.Reader,
+		categories: make(map[string]*models.Category),
+		hashes:     make(map[string]int),
+	}
+	for _, row := range rows {
+		document, category, err := importDocumentRow(ctx, row)
+		job.Processed++
+		if err != nil {
+			job.AddError(row, err.Error())
+		} else {
+			job.Succeeded++
+			if !job.DryRun {
+				job.DocumentIDs = append(job.DocumentIDs, document.ID)
+			}
+		}
+		saveDocumentImportJob(job)
+		if err == nil && !job.DryRun {
+			pipeline <- importedDocument{document: document, categoryName: category.Name}
+		}
+	}
+	close(pipeline)
+	wg.Wait()
+
+	job.Status = constant.ImportJobCompleted
+	job.FinishedAt = time.Now().Unix()
+	saveDocumentImportJob(job)
+	log.Printf("[Import] 任务 %s 完成：共 %d 行，成功 %d 行，失败 %d 行", job.ID, job.Total, job.Succeeded, job.Failed)
+}
+
+func saveDocumentImportJob(job *utils.DocumentImportJob) {
+	if err := utils.SaveDocumentImportJob(job); err != nil {
+		log.Printf("[Import] 保存任务 %s 的进度失败: %v", job.ID, err)
+	}
+}
+
+// importDocumentRow 校验清单中的一行并创建文档（试运行时只校验），返回的错误信息写入错误报告
+func importDocumentRow(ctx *importContext, row utils.DocumentImportRow) (models.Document, *models.Category, error) {
+	document, category, err := validateImportRow(ctx, row)
+	if err != nil {
+		return models.Document{}, nil, err
+	}
+
+	entry := utils.FindImportArchiveEntry(ctx.archive, row.File)
+	if entry == nil {
+		return models.Document{}, nil, errors.New(constant.ImportRowFileNotFound)
+	}
+	tmpPath, fileSize, fileHash, err := utils.ExtractImportArchiveEntry(entry, constant.MaxFileSize)
+	if err != nil {
+		if errors.Is(err, utils.ErrImportFileTooLarge) {
+			return models.Document{}, nil, err
+		}
+		return models.Document{}, nil, errors.New(constant.ImportArchiveInvalid)
+	}
+	defer os.Remove(tmpPath)
+
+	checked, err := utils.ValidateLocalFile(tmpPath, document.Type)
+	if err != nil {
+		if utils.IsUploadRejected(err) {
+			return models.Document{}, nil, err
+		}
+		if errors.Is(err, utils.ErrScanUnavailable) {
+			return models.Document{}, nil, errors.New(constant.UploadScanUnavailable)
+		}
+		return models.Document{}, nil, errors.New(constant.DocumentOpenFailed)
+	}
+
+	// 同一文件不重复导入：清单内重复或与已有文档重复
+	if previous, ok := ctx.hashes[fileHash]; ok {
+		return models.Document{}, nil, fmt.Errorf(constant.ImportRowDuplicateInManifest, previous)
+	}
+	existing, err := dao.GetDocumentByContentHash(fileHash)
+	if err == nil {
+		return models.Document{}, nil, fmt.Errorf(constant.ImportRowDuplicate, existing.ID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Document{}, nil, errors.New(constant.DatabaseError)
+	}
+
+	if ctx.job.DryRun {
+		ctx.hashes[fileHash] = row.Row
+		return document, category, nil
+	}
+
+	fileURL, err := utils.UploadMainFileFromPath(tmpPath, checked.Extension, checked.MIME, category.Name)
+	if err != nil {
+		return models.Document{}, nil, err
+	}
+	document.URL = fileURL
+	resetDocumentFileMetadata(&document, fileSize, fileHash, checked.MIME)
+	document, err = createDocumentWithFirstVersion(document, row.Tags, fileSize, fileHash)
+	if err != nil {
+		if deleteErr := utils.DeleteFile(fileURL); deleteErr != nil {
+			log.Printf("[Import] 清理文件 %s 失败: %v", fileURL, deleteErr)
+		}
+		return models.Document{}, nil, errors.New(constant.DocumentCreateFail)
+	}
+	// 创建成功后才记录，上传或创建失败的行不影响清单中后面相同文件的行
+	ctx.hashes[fileHash] = row.Row
+	return document, category, nil
+}
+
+// validateImportRow 校验清单中一行的字段并构建文档（不含文件），名称和简介经过敏感词过滤
+func validateImportRow(ctx *importContext, row utils.DocumentImportRow) (models.Document, *models.Category, error) {
+	if row.File == "" {
+		return models.Document{}, nil, errors.New(constant.ImportRowFileRequired)
+	}
+	// 书籍提供了 ISBN 时名称可以留空，根据 ISBN 补全
+	if row.Name == "" && (row.ISBN == "" || row.Type != constant.BookType) {
+		return models.Document{}, nil, errors.New(constant.ImportRowNameRequired)
+	}
+	if utf8.RuneCountInString(row.Name) > 200 {
+		return models.Document{}, nil, fmt.Errorf(constant.ImportRowFieldTooLong, "名称", 200)
+	}
+	if utf8.RuneCountInString(row.Author) > 100 {
+		return models.Document{}, nil, fmt.Errorf(constant.ImportRowFieldTooLong, "作者", 100)
+	}
+	for _, tag := range row.Tags {
+		if utf8.RuneCountInString(tag) > 50 {
+			return models.Document{}, nil, fmt.Errorf(constant.ImportRowFieldTooLong, "标签", 50)
+		}
+	}
+	if row.Type != constant.BookType && row.Type != constant.FileType {
+		return models.Document{}, nil, errors.New(constant.ImportRowTypeInvalid)
+	}
+	if row.Year != "" && !importYearPattern.MatchString(row.Year) {
+		return models.Document{}, nil, errors.New(constant.ImportRowYearInvalid)
+	}
+	isbn := ""
+	if row.ISBN != "" {
+		normalized, ok := utils.NormalizeISBN(row.ISBN)
+		if !ok {
+			return models.Document{}, nil, errors.New(constant.InvalidISBN)
+		}
+		isbn = normalized
+	}
+
+	category, err := resolveImportCategory(ctx, row.Category)
+	if err != nil {
+		return models.Document{}, nil, err
+	}
+
+	name, err := maskSensitiveText(row.Name)
+	if err != nil {
+		return models.Document{}, nil, err
+	}
+	introduction, err := maskSensitiveText(row.Introduction)
+	if err != nil {
+		return models.Document{}, nil, err
+	}
+
+	document := models.Document{
+		Type:         row.Type,
+		Name:         name,
+		BookISBN:     isbn,
+		Author:       row.Author,
+		UploaderID:   ctx.job.CreatedBy,
+		CategoryID:   category.ID,
+		Introduction: introduction,
+		CreateYear:   row.Year,
+		Status:       constant.DocumentStatusPending,
+	}
+	// 书籍用 ISBN 查询到的书籍信息补全清单中留空的字段，封面在生成封面时从目录服务下载
+	if document.Type == constant.BookType && isbn != "" {
+		metadata, _ := lookupBookMetadata(isbn)
+		applyBookMetadata(&document, metadata)
+	}
+	if document.Name == "" {
+		return models.Document{}, nil, errors.New(constant.ImportRowNameRequired)
+	}
+	if document.Author == "" {
+		document.Author = constant.DefaultAuthor
+	}
+	return document, category, nil
+}
+
+// resolveImportCategory 按路径查找分类（如 计算机/操作系统，从顶级分类开始逐级查找）。
+// 只有一级且不是顶级分类时，按名称查找唯一的分类
+func resolveImportCategory(ctx *importContext, categoryPath string) (*models.Category, error) {
+	if category, ok := ctx.categories[categoryPath]; ok {
+		if category == nil {
+			return nil, errors.New(constant.ImportRowCategoryNotFound)
+		}
+		return category, nil
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(categoryPath, constant.ImportCategoryPathSeparator) {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	var category *models.Category
+	var parentID *uint64
+	for i, segment := range segments {
+		categories, err := dao.GetCategoriesByNameAndParent(segment, parentID)
+		if err != nil {
+			return nil, errors.New(constant.DatabaseError)
+		}
+		if len(categories) == 0 && i == 0 && len(segments) == 1 {
+			categories, err = dao.GetCategoryByName(segment)
+			if err != nil {
+				return nil, errors.New(constant.DatabaseError)
+			}
+		}
+		if len(categories) != 1 {
+			category = nil
+			break
+		}
+		category = &categories[0]
+		parentID = &category.ID
+	}
+
+	ctx.categories[categoryPath] = category
+	if category == nil {
+		return nil, errors.New(constant.ImportRowCategoryNotFound)
+	}
+	return category, nil
+}
+
+// runImportedDocumentPipeline 导入的文档与单个上传的文档相同的后续处理：学习（提取正文、元数据建议、内容预审、向量化）、生成封面、书籍向量化。
+// 在当前 goroutine 中执行，用于限制同时处理的文档数
+func runImportedDocumentPipeline(document models.Document, categoryName string) {
+	learnDocument(int64(document.ID), utils.GetFileURL(document.URL))
+	generateDocumentCover(document, categoryName)
+	if document.Type == constant.BookType {
+		indexBookVector(document, categoryName)
+	}
+}
@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	metadataSourceMaxRunes    = 8000 // 生成元数据建议时发送给模型的正文最大字符数
+	metadataTagCandidateLimit = 300  // 提供给模型挑选的已有标签数量上限
+	metadataNewTagLimit       = 3    // 建议新增标签的数量上限
+)
+
+// metadataSuggestionOutput 模型输出的元数据建议
+type metadataSuggestionOutput struct {
+	Introduction string   `json:"introduction"`
+	Tags         []string `json:"tags"`
+	NewTags      []string `json:"newTags"`
+	CategoryID   uint64   `json:"categoryId"`
+	Difficulty   string   `json:"difficulty"`
+}
+
+// isValidDifficulty 判断是否为合法的文档难度
+func isValidDifficulty(difficulty string) bool {
+	switch difficulty {
+	case constant.DifficultyBeginner, constant.DifficultyIntermediate, constant.DifficultyAdvanced:
+		return true
+	}
+	return false
+}
+
+// parseSuggestionFields 解析修改文档时要采纳的建议字段（逗号分隔，all 表示全部采纳）
+func parseSuggestionFields(raw string) (map[string]bool, error) {
+	fields := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		switch field {
+		case "":
+			continue
+		case constant.SuggestionFieldAll:
+			fields[constant.SuggestionFieldIntroduction] = true
+			fields[constant.SuggestionFieldTags] = true
+			fields[constant.SuggestionFieldCategory] = true
+			fields[constant.SuggestionFieldDifficulty] = true
+		case constant.SuggestionFieldIntroduction, constant.SuggestionFieldTags,
+			constant.SuggestionFieldCategory, constant.SuggestionFieldDifficulty:
+			fields[field] = true
+		default:
+			return nil, fmt.Errorf("%s: %s", constant.InvalidSuggestionField, field)
+		}
+	}
+	return fields, nil
+}
+
+// suggestDocumentMetadata 根据提取到的正文让模型生成简介、标签、分类和难度建议并保存，供上传者或审核员在修改文档时采纳
+// 标签只保留与已有标签匹配的部分，其余作为新标签建议；分类和难度不合法时丢弃
+func suggestDocumentMetadata(documentID uint64, text string) error {
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		return err
+	}
+	categories, err := dao.GetAllCategories()
+	if err != nil {
+		return err
+	}
+	tagNames, err := dao.GetPopularTagNames(metadataTagCandidateLimit)
+	if err != nil {
+		return err
+	}
+
+	categoryLines := make([]string, 0, len(categories))
+	categoryIDs := make(map[uint64]bool, len(categories))
+	for _, category := range categories {
+		categoryLines = append(categoryLines, fmt.Sprintf("%d:%s", category.ID, category.Name))
+		categoryIDs[category.ID] = true
+	}
+	tagsByLower := make(map[string]string, len(tagNames))
+	for _, name := range tagNames {
+		tagsByLower[strings.ToLower(name)] = name
+	}
+
+	systemPrompt, promptVersion := renderPrompt(constant.PromptKeyDocumentMetadata, map[string]string{
+		"Categories": strings.Join(categoryLines, "\n"),
+		"Tags":       strings.Join(tagNames, "、"),
+	})
+	userMsg := fmt.Sprintf("资料名称：%s\n\n正文开头：\n%s", document.Name, utils.TruncateRunesForSummary(text, metadataSourceMaxRunes))
+
+	output, err := utils.Chat([]utils.Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userMsg},
+	})
+	if err != nil {
+		return err
+	}
+	var result metadataSuggestionOutput
+	if err := utils.ParseModelJSON(output, &result, false); err != nil {
+		return err
+	}
+
+	// 标签与已有标签做大小写无关的匹配，匹配不上的并入新标签建议
+	matched := make([]string, 0, len(result.Tags))
+	newTags := make([]string, 0, metadataNewTagLimit)
+	seen := make(map[string]bool)
+	for _, tag := range append(result.Tags, result.NewTags...) {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if name, ok := tagsByLower[key]; ok {
+			matched = append(matched, name)
+		} else if len(newTags) < metadataNewTagLimit {
+			newTags = append(newTags, tag)
+		}
+	}
+	tagsJSON, _ := json.Marshal(matched)
+	newTagsJSON, _ := json.Marshal(newTags)
+
+	suggestion := models.DocumentMetadataSuggestion{
+		DocumentID:    documentID,
+		Introduction:  strings.TrimSpace(result.Introduction),
+		Tags:          string(tagsJSON),
+		NewTags:       string(newTagsJSON),
+		Status:        constant.MetadataSuggestionPending,
+		Model:         utils.GetChatModelName(),
+		PromptVersion: promptVersion,
+	}
+	if categoryIDs[result.CategoryID] {
+		categoryID := result.CategoryID
+		suggestion.SuggestedCategoryID = &categoryID
+	}
+	if isValidDifficulty(result.Difficulty) {
+		suggestion.Difficulty = result.Difficulty
+	}
+
+	return dao.SaveMetadataSuggestion(&suggestion)
+}
+
+// refreshBookVector 书籍元数据修改后重新向量化（用于推荐）
+func refreshBookVector(document models.Document) {
+	category, err := dao.GetCategoryByID(document.CategoryID)
+	if err != nil {
+		log.Printf("书籍 %d 重新向量化失败: %v", document.ID, err)
+		return
+	}
+	text := fmt.Sprintf("%s %s %s", document.Name, category.Name, document.Introduction)
+	vectors, err := utils.GetEmbeddings([]string{text})
+	if err != nil || len(vectors) == 0 {
+		log.Printf("书籍 %d 重新向量化失败: %v", document.ID, err)
+		return
+	}
+	if err := utils.UpsertBookVector(int64(document.ID), text, vectors[0]); err != nil {
+		log.Printf("书籍 %d 重新向量化失败: %v", document.ID, err)
+		return
+	}
+	log.Printf("书籍 %d 重新向量化完成", document.ID)
+}
+
+// GetDocumentMetadataSuggestion 获取文档上传后 AI 生成的元数据建议（仅上传者和管理员可查看）
+// GET /api/document/:id/metadata-suggestion
+func GetDocumentMetadataSuggestion(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.DocumentNotExist)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if document.UploaderID != userClaims.UserID && userClaims.Role != "admin" {
+		response.Fail(c, http.StatusForbidden, nil, constant.NonSelf)
+		return
+	}
+
+	suggestion, err := dao.GetMetadataSuggestionByDocumentID(documentID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if suggestion == nil {
+		response.Fail(c, http.StatusNotFound, nil, constant.MetadataSuggestionNotExist)
+		return
+	}
+
+	categoryName := ""
+	if suggestion.SuggestedCategoryID != nil {
+		if category, err := dao.GetCategoryByID(*suggestion.SuggestedCategoryID); err == nil {
+			categoryName = category.Name
+		}
+	}
+
+	response.SuccessWithData(c, response.BuildDocumentMetadataSuggestionResponse(*suggestion, categoryName), constant.GetMetadataSuggestionSuccess)
+}
@@ -0,0 +1,324 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// getReviewDocument 根据路径参数获取当前用户可以查看评价的文档，失败时已写入响应
+func getReviewDocument(c *gin.Context, userClaims *utils.MyClaims) (models.Document, bool) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.Document{}, false
+	}
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.DocumentNotExist)
+			return models.Document{}, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.Document{}, false
+	}
+	if !canAccessDocument(document, userClaims) {
+		response.Fail(c, http.StatusForbidden, nil, constant.ReviewAccessDenied)
+		return models.Document{}, false
+	}
+	return document, true
+}
+
+// getReview 根据路径参数获取评价，失败时已写入响应
+func getReview(c *gin.Context) (models.DocumentReview, bool) {
+	reviewID, err := strconv.ParseUint(c.Param("reviewId"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.DocumentReview{}, false
+	}
+	review, err := dao.GetDocumentReviewByID(reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.ReviewNotExist)
+			return models.DocumentReview{}, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.DocumentReview{}, false
+	}
+	return review, true
+}
+
+// SaveDocumentReview 评价文档（1-5 星，评价内容可选），每个用户对每个文档只有一条评价，再次提交时修改原评价。
+// 只能评价开放的文档，上传者不能评价自己的文档；首次评价时通知上传者
+// PUT /api/document/:id/review
+func SaveDocumentReview(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.SaveReviewDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	document, ok := getReviewDocument(c, userClaims)
+	if !ok {
+		return
+	}
+	if document.Status != constant.DocumentStatusOpen {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ReviewDocumentNotOpen)
+		return
+	}
+	if document.UploaderID == userClaims.UserID {
+		response.Fail(c, http.StatusForbidden, nil, constant.ReviewOwnDocument)
+		return
+	}
+	content, ok := filterSensitiveText(c, strings.TrimSpace(req.Content))
+	if !ok {
+		return
+	}
+
+	review := models.DocumentReview{
+		UserID:     userClaims.UserID,
+		DocumentID: document.ID,
+		Stars:      req.Stars,
+		Content:    content,
+	}
+	isNew, err := dao.SaveDocumentReview(&review)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.SaveReviewFailed)
+		return
+	}
+	if isNew {
+		notifyDocumentReviewed(document, review)
+	}
+
+	// 返回重新计算后的文档评分（不能给自己的评价投票，helpful 总是 false）
+	if updated, err := dao.GetDocumentByID(document.ID); err == nil {
+		document = updated
+	}
+	responseData := gin.H{
+		"review":        response.BuildDocumentReviewResponse(review, false),
+		"ratingAverage": document.RatingAverage,
+		"ratingCount":   document.RatingCount,
+	}
+	response.SuccessWithData(c, responseData, constant.SaveReviewSuccess)
+}
+
+// notifyDocumentReviewed 通知上传者文档收到了新评价，通知失败不影响评价本身
+func notifyDocumentReviewed(document models.Document, review models.DocumentReview) {
+	reviewer, err := dao.GetUserByID(review.UserID)
+	if err != nil {
+		log.Println("创建通知失败:", err)
+		return
+	}
+	notification := models.Notification{
+		ReceiverID: document.UploaderID,
+		Type:       constant.ReviewNotificationType,
+		Content:    fmt.Sprintf(constant.ReviewNotification, document.Name, reviewer.Username, review.Stars),
+		IsRead:     false,
+		SourceID:   document.ID,
+		SourceType: constant.DocumentType,
+	}
+	if err := dao.CreateNotification(&notification); err != nil {
+		log.Println("创建通知失败:", err)
+	}
+
+	// 推送给在线的上传者，离线时可通过 GetNotification 拉取
+	wsData := gin.H{
+		"reminderId":   notification.ID,
+		"remindertype": notification.Type,
+		"content":      notification.Content,
+		"sendTime":     notification.CreatedAt,
+		"sourceId":     notification.SourceID,
+		"sourceType":   notification.SourceType,
+	}
+	err = utils.WSManager.SendToUser(document.UploaderID, utils.WSMessage{
+		Type:       "reminder",
+		ReceiverID: document.UploaderID,
+		Data:       wsData,
+	})
+	if err != nil {
+		log.Printf("WS推送给接收者 %d 失败(可能离线): %v", document.UploaderID, err)
+	}
+}
+
+// GetDocumentReviews 分页获取文档的评价，同时返回评分汇总、各星级评价数和当前用户自己的评价
+// GET /api/document/:id/reviews?page=1&pageSize=10&sort=helpful
+func GetDocumentReviews(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.GetReviewsDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = constant.DefaultReviewPageSize
+	}
+	if req.Sort == "" {
+		req.Sort = constant.ReviewSortHelpful
+	}
+	document, ok := getReviewDocument(c, userClaims)
+	if !ok {
+		return
+	}
+
+	reviews, total, err := dao.GetDocumentReviews(document.ID, req.Sort, req.Page, req.PageSize)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	starCounts, err := dao.GetDocumentStarCounts(document.ID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	var myReview *response.DocumentReviewResponse
+	own, err := dao.GetDocumentReview(userClaims.UserID, document.ID)
+	if err == nil {
+		ownResponse := response.BuildDocumentReviewResponse(own, false)
+		myReview = &ownResponse
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	reviewIDs := make([]uint64, len(reviews))
+	for i, review := range reviews {
+		reviewIDs[i] = review.ID
+	}
+	voted, err := dao.GetHelpfulVotedReviewIDs(userClaims.UserID, reviewIDs)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	responseData := response.DocumentReviewListResponse{
+		RatingAverage: document.RatingAverage,
+		RatingCount:   document.RatingCount,
+		StarCounts:    starCounts,
+		MyReview:      myReview,
+		Total:         total,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+		List:          response.BuildDocumentReviewResponses(reviews, voted),
+	}
+	response.SuccessWithData(c, responseData, constant.GetReviewsSuccess)
+}
+
+// DeleteDocumentReview 删除评价，评价者本人和管理员可以删除
+// DELETE /api/reviews/:reviewId
+func DeleteDocumentReview(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	review, ok := getReview(c)
+	if !ok {
+		return
+	}
+	if review.UserID != userClaims.UserID && userClaims.Role != "admin" {
+		response.Fail(c, http.StatusForbidden, nil, constant.ReviewDeleteDenied)
+		return
+	}
+	if err := dao.DeleteDocumentReview(&review); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DeleteReviewFailed)
+		return
+	}
+
+	response.Success(c, nil, constant.DeleteReviewSuccess)
+}
+
+// VoteReviewHelpful 标记评价有帮助，不能给自己的评价投票
+// POST /api/reviews/:reviewId/helpful
+func VoteReviewHelpful(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	review, ok := getReview(c)
+	if !ok {
+		return
+	}
+	if review.UserID == userClaims.UserID {
+		response.Fail(c, http.StatusBadRequest, nil, constant.HelpfulOwnReview)
+		return
+	}
+	document, err := dao.GetDocumentByID(review.DocumentID)
+	if err != nil || !canAccessDocument(document, userClaims) {
+		response.Fail(c, http.StatusNotFound, nil, constant.ReviewNotExist)
+		return
+	}
+
+	if err := dao.VoteReviewHelpful(userClaims.UserID, review.ID); err != nil {
+		if err.Error() == constant.AlreadyVotedHelpful {
+			response.Fail(c, http.StatusBadRequest, nil, constant.AlreadyVotedHelpful)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.VoteHelpfulFailed)
+		return
+	}
+
+	response.SuccessWithData(c, gin.H{"helpfulCount": review.HelpfulCount + 1}, constant.VoteHelpfulSuccess)
+}
+
+// CancelReviewHelpful 取消有帮助
+// DELETE /api/reviews/:reviewId/helpful
+func CancelReviewHelpful(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	review, ok := getReview(c)
+	if !ok {
+		return
+	}
+	document, err := dao.GetDocumentByID(review.DocumentID)
+	if err != nil || !canAccessDocument(document, userClaims) {
+		response.Fail(c, http.StatusNotFound, nil, constant.ReviewNotExist)
+		return
+	}
+
+	if err := dao.UnvoteReviewHelpful(userClaims.UserID, review.ID); err != nil {
+		if err.Error() == constant.NotVotedHelpful {
+			response.Fail(c, http.StatusBadRequest, nil, constant.NotVotedHelpful)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.VoteHelpfulFailed)
+		return
+	}
+
+	response.SuccessWithData(c, gin.H{"helpfulCount": max(review.HelpfulCount-1, 0)}, constant.CancelHelpfulSuccess)
+}
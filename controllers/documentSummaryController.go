@@ -1,9 +1,13 @@
 package controllers
 
 import (
+	"context"
 	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/antidote-kt/SSE_Library-back/constant"
 	"github.com/antidote-kt/SSE_Library-back/dao"
@@ -15,7 +19,43 @@ import (
 
 const documentSummaryMaxRunes = 80000
 
-// StreamDocumentSummary 对 PDF 文档：下载 → 抽取正文（同 RAG 流程）→ 调用 StreamChat 流式生成中文摘要。
+// summaryPrompts 文档摘要所用的提示词及版本
+type summaryPrompts struct {
+	System        string // 最终摘要的系统提示词
+	Chunk         string // 长文档分段摘要的系统提示词
+	ChunkVersion  string
+	PromptVersion string // 记录到摘要上的版本标签，长文档同时包含分段提示词版本
+}
+
+// needsSummaryReduce 正文超过 documentSummaryMaxRunes 时需要先分段摘要
+func needsSummaryReduce(sourceText string) bool {
+	return utf8.RuneCountInString(sourceText) > documentSummaryMaxRunes
+}
+
+// resolveSummaryPrompts 取后台当前启用的对应风格的摘要提示词
+func resolveSummaryPrompts(style, sourceText string) summaryPrompts {
+	prompts := summaryPrompts{}
+	prompts.System, prompts.PromptVersion = renderPrompt(constant.SummaryStylePromptKeys[style], nil)
+	if needsSummaryReduce(sourceText) {
+		prompts.Chunk, prompts.ChunkVersion = renderPrompt(constant.PromptKeyDocumentChunk, nil)
+		prompts.PromptVersion += "," + prompts.ChunkVersion
+	}
+	return prompts
+}
+
+// buildSummaryUserMessage 构建最终摘要的用户消息：短文档直接使用正文，长文档先分段摘要再合并（map-reduce）
+func buildSummaryUserMessage(ctx context.Context, title, sourceText string, prompts summaryPrompts, onProgress func(utils.SummaryProgress)) (string, error) {
+	if !needsSummaryReduce(sourceText) {
+		return "以下为从《" + title + "》提取的正文，请按要求输出摘要：\n\n" + sourceText, nil
+	}
+	reduced, err := utils.SummarizeLongText(ctx, sourceText, documentSummaryMaxRunes, prompts.Chunk, prompts.ChunkVersion, onProgress)
+	if err != nil {
+		return "", err
+	}
+	return "以下为《" + title + "》各部分的分段摘要（按原文顺序），请整合为全文摘要：\n\n" + reduced, nil
+}
+
+// StreamDocumentSummary 对 PDF 文档：下载 → 抽取正文（同 RAG 流程）→ 调用 StreamChat 流式生成中文摘要，完成后保存到摘要表。
 // 需登录；公开文档任意登录用户可摘要，非公开仅上传者或管理员。
 func StreamDocumentSummary(c *gin.Context) {
 	idStr := c.Param("id")
@@ -26,13 +66,19 @@ func StreamDocumentSummary(c *gin.Context) {
 	}
 
 	var body struct {
-		IsThink *bool `json:"isThink"`
+		IsThink *bool  `json:"isThink"`
+		Style   string `json:"style"` // 摘要风格：abstract（默认）、key_points、outline、glossary
 	}
 	_ = c.ShouldBindJSON(&body)
 	isThink := false
 	if body.IsThink != nil {
 		isThink = *body.IsThink
 	}
+	style, ok := parseSummaryStyle(body.Style)
+	if !ok {
+		response.Fail(c, http.StatusBadRequest, nil, constant.AISummaryInvalidStyle)
+		return
+	}
 
 	claims, exists := c.Get(constant.UserClaims)
 	if !exists {
@@ -51,10 +97,7 @@ func StreamDocumentSummary(c *gin.Context) {
 		return
 	}
 
-	canAccess := document.Status == constant.DocumentStatusOpen ||
-		document.UploaderID == userClaims.UserID ||
-		userClaims.Role == "admin"
-	if !canAccess {
+	if !canAccessDocument(document, userClaims) {
 		response.Fail(c, http.StatusForbidden, nil, constant.DocumentSummaryAccessDenied)
 		return
 	}
@@ -64,7 +107,7 @@ func StreamDocumentSummary(c *gin.Context) {
 		return
 	}
 
-	bodyText, err := utils.ExtractDocumentPDFPlainText(document.URL, documentSummaryMaxRunes)
+	bodyText, err := utils.ExtractDocumentPDFPlainText(document.URL)
 	if err != nil {
 		if errors.Is(err, utils.ErrSummaryEmptyText) {
 			response.Fail(c, http.StatusBadRequest, nil, constant.DocumentSummaryNoText)
@@ -78,11 +121,32 @@ func StreamDocumentSummary(c *gin.Context) {
 		return
 	}
 
-	userMsg := "以下为从《" + document.Name + "》提取的 PDF 正文，请按要求输出摘要：\n\n" + bodyText
+	// 通过响应头告知前端本次摘要所用的提示词版本
+	prompts := resolveSummaryPrompts(style, bodyText)
+	c.Header("X-Prompt-Version", prompts.PromptVersion)
 
-	_, err = utils.StreamChat(c, []utils.Message{{Role: "user", Content: userMsg}}, isThink, constant.DocumentSummarySystemPrompt)
+	// 长文档先分段摘要，期间通过 progress 事件推送进度
+	userMsg, err := buildSummaryUserMessage(c.Request.Context(), document.Name, bodyText, prompts, func(p utils.SummaryProgress) {
+		c.SSEvent("progress", p)
+		c.Writer.Flush()
+	})
+	if err != nil {
+		log.Printf("[AISummary] 文档 %d 分段摘要失败: %v", document.ID, err)
+		c.SSEvent("error", constant.AISummaryGenerateFailed)
+		return
+	}
+
+	result, err := utils.StreamChat(c, []utils.Message{{Role: "user", Content: userMsg}}, isThink, prompts.System)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+
+	// 完整生成（客户端未中途断开）的摘要与非流式接口写入同一张摘要表
+	if result == nil || strings.TrimSpace(result.Content) == "" || c.Request.Context().Err() != nil {
+		return
+	}
+	if _, err := saveAISummary("document", document.ID, style, result.Content, utils.HashSourceText(bodyText), prompts.PromptVersion, userClaims.UserID); err != nil {
+		log.Printf("[AISummary] 保存流式摘要失败: %v", err)
+	}
 }
@@ -0,0 +1,327 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// createDocumentVersionWithTx 在事务中为文档记录新版本并更新文档的当前版本号。
+// 功能上线前上传的文档没有版本记录，先把修改前的文件补记为第一个版本，保证旧文件可以回滚
+func createDocumentVersionWithTx(tx *gorm.DB, original models.Document, document *models.Document, version models.DocumentVersion) error {
+	count, err := dao.CountDocumentVersionsWithTx(tx, document.ID)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		err := dao.CreateDocumentVersionWithTx(tx, &models.DocumentVersion{
+			DocumentID: original.ID,
+			Type:       original.Type,
+			URL:        original.URL,
+			Cover:      original.Cover,
+			UploaderID: original.UploaderID,
+			ChangeNote: constant.DocumentVersionInitialNote,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// 只替换封面时沿用当前版本的文件大小和哈希
+	if version.FileHash == "" && document.URL == original.URL {
+		if current, err := dao.GetDocumentVersionWithTx(tx, original.ID, original.Version); err == nil {
+			version.FileSize = current.FileSize
+			version.FileHash = current.FileHash
+		}
+	}
+	if version.UploaderID == 0 {
+		version.UploaderID = document.UploaderID
+	}
+	version.DocumentID = document.ID
+	version.Type = document.Type
+	version.URL = document.URL
+	version.Cover = document.Cover
+	if err := dao.CreateDocumentVersionWithTx(tx, &version); err != nil {
+		return err
+	}
+	document.Version = version.Version
+	return nil
+}
+
+// reindexDocument 文档文件变化后删除知识库中的旧分片并重新学习，同时重新预审（视频只审核名称和简介），应在 goroutine 中调用
+func reindexDocument(document models.Document) {
+	if err := utils.DeleteChunks(int64(document.ID)); err != nil {
+		log.Printf("[DocumentVersion] 删除文档 %d 的知识库分片失败: %v", document.ID, err)
+	}
+	if document.Type == constant.VideoType {
+		moderateDocument(document.ID, "")
+		return
+	}
+	LearnDocument(int(document.ID), utils.GetFileURL(document.URL))
+}
+
+// getManagedDocument 解析路径中的文档 ID 并校验当前用户是否为上传者或管理员（失败时已写入响应）
+func getManagedDocument(c *gin.Context) (models.Document, *utils.MyClaims, bool) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return models.Document{}, nil, false
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.Document{}, nil, false
+	}
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.DocumentNotExist)
+			return models.Document{}, nil, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.Document{}, nil, false
+	}
+	if document.UploaderID != userClaims.UserID && userClaims.Role != "admin" {
+		response.Fail(c, http.StatusForbidden, nil, constant.DocumentVersionAccessDenied)
+		return models.Document{}, nil, false
+	}
+	return document, userClaims, true
+}
+
+// getDocumentVersionParam 解析路径中的版本号并获取该版本（失败时已写入响应）
+func getDocumentVersionParam(c *gin.Context, documentID uint64) (models.DocumentVersion, bool) {
+	versionNumber, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.DocumentVersion{}, false
+	}
+	version, err := dao.GetDocumentVersion(documentID, versionNumber)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.DocumentVersionNotExist)
+			return models.DocumentVersion{}, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.DocumentVersion{}, false
+	}
+	return version, true
+}
+
+// GetDocumentVersions 获取文档的版本历史（上传者和管理员）
+// GET /api/document/:id/versions
+func GetDocumentVersions(c *gin.Context) {
+	document, _, ok := getManagedDocument(c)
+	if !ok {
+		return
+	}
+
+	versions, err := dao.GetDocumentVersions(document.ID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildDocumentVersionResponses(versions, document.Version), constant.GetDocumentVersionsSuccess)
+}
+
+// GetDocumentVersionDownload 获取文档指定版本的下载地址（上传者和管理员）
+// GET /api/document/:id/versions/:version/download
+func GetDocumentVersionDownload(c *gin.Context) {
+	document, _, ok := getManagedDocument(c)
+	if !ok {
+		return
+	}
+	version, ok := getDocumentVersionParam(c, document.ID)
+	if !ok {
+		return
+	}
+
+	// 旧版本文件同样只返回限时有效的签名地址
+	fileURL := version.URL
+	if version.Type != constant.VideoType {
+		signedURL, err := utils.GetSignedFileURL(version.URL, utils.DownloadURLExpire())
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentDownloadFailed)
+			return
+		}
+		fileURL = signedURL
+	}
+
+	responseData := gin.H{
+		"version": version.Version,
+		"url":     fileURL,
+		"cover":   utils.GetFileURL(version.Cover),
+	}
+	response.SuccessWithData(c, responseData, constant.GetDocumentVersionURLSuccess)
+}
+
+// RollbackDocumentVersion 将文档回滚到指定版本（上传者和管理员）。
+// 回滚会以目标版本的文件新增一个版本（历史保持线性），并重新进入审核、重新向量化
+// POST /api/document/:id/versions/:version/rollback
+func RollbackDocumentVersion(c *gin.Context) {
+	var req dto.RollbackDocumentVersionDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	document, userClaims, ok := getManagedDocument(c)
+	if !ok {
+		return
+	}
+	target, ok := getDocumentVersionParam(c, document.ID)
+	if !ok {
+		return
+	}
+	if target.Version == document.Version {
+		response.Fail(c, http.StatusBadRequest, nil, constant.DocumentVersionAlreadyCurrent)
+		return
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		note = fmt.Sprintf(constant.DocumentVersionRollbackNote, target.Version)
+	}
+
+	original := document
+	document.Type = target.Type
+	document.URL = target.URL
+	document.Cover = target.Cover
+	document.Status = constant.DocumentStatusPending
+	// 文件类型、页数等在重新学习时提取
+	resetDocumentFileMetadata(&document, target.FileSize, target.FileHash, "")
+	err := config.GetDB().Transaction(func(tx *gorm.DB) error {
+		err := createDocumentVersionWithTx(tx, original, &document, models.DocumentVersion{
+			FileSize:   target.FileSize,
+			FileHash:   target.FileHash,
+			UploaderID: userClaims.UserID,
+			ChangeNote: note,
+		})
+		if err != nil {
+			return err
+		}
+		return dao.UpdateDocumentWithTx(tx, document)
+	})
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentRollbackFailed)
+		return
+	}
+
+	go reindexDocument(document)
+	// 开放的文档重新进入审核后不能再被推荐
+	if original.Status == constant.DocumentStatusOpen {
+		markRecommendationsStale()
+	}
+
+	responseData := gin.H{
+		"version": document.Version,
+	}
+	response.SuccessWithData(c, responseData, constant.DocumentRollbackSuccess)
+}
+
+// AdminPruneDocumentVersions 管理员按保留策略清理文档旧版本：每个文档保留最新的 document_version.keep_latest 个版本，
+// 只清理创建超过 document_version.min_age_days 天的版本，文档当前使用的版本永不清理。
+// 未配置 keep_latest 时不清理任何版本；dryRun 为 true 时只统计不删除
+// POST /api/admin/document-versions/prune
+func AdminPruneDocumentVersions(c *gin.Context) {
+	var req dto.PruneDocumentVersionsDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	keepLatest := viper.GetInt("document_version.keep_latest")
+	if keepLatest <= 0 {
+		response.Fail(c, http.StatusBadRequest, nil, constant.DocumentVersionRetentionOff)
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -viper.GetInt("document_version.min_age_days"))
+
+	versions, err := dao.GetAllDocumentVersions()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	documentIDs := make([]uint64, 0)
+	for i, version := range versions {
+		if i == 0 || versions[i-1].DocumentID != version.DocumentID {
+			documentIDs = append(documentIDs, version.DocumentID)
+		}
+	}
+	documents, err := dao.GetDocumentsByIDsIgnoreStatus(documentIDs)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	currentVersions := make(map[uint64]int, len(documents))
+	for _, document := range documents {
+		currentVersions[document.ID] = document.Version
+	}
+
+	// 版本已按文档分组、版本号倒序，每组跳过最新的 keepLatest 个
+	var pruneIDs []uint64
+	var prunePaths []string
+	kept := 0
+	for i, version := range versions {
+		if i == 0 || versions[i-1].DocumentID != version.DocumentID {
+			kept = 0
+		}
+		if kept < keepLatest || version.Version == currentVersions[version.DocumentID] || version.CreatedAt.After(cutoff) {
+			kept++
+			continue
+		}
+		pruneIDs = append(pruneIDs, version.ID)
+		if version.Type != constant.VideoType {
+			prunePaths = append(prunePaths, version.URL)
+		}
+		prunePaths = append(prunePaths, version.Cover)
+	}
+
+	if req.DryRun {
+		response.SuccessWithData(c, gin.H{"versions": len(pruneIDs), "dryRun": true}, constant.PruneDocumentVersionsSuccess)
+		return
+	}
+
+	if err := dao.DeleteDocumentVersions(pruneIDs); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.PruneDocumentVersionsFailed)
+		return
+	}
+	// 回滚后多个版本可能共用同一文件，只删除已不被任何文档或版本使用的文件
+	deletedFiles := 0
+	seen := make(map[string]bool)
+	for _, path := range prunePaths {
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		referenced, err := dao.IsDocumentFileReferenced(path)
+		if err != nil || referenced {
+			continue
+		}
+		if err := utils.DeleteFileWithThumbnails(path); err != nil {
+			log.Printf("[DocumentVersion] 删除文件 %s 失败: %v", path, err)
+			continue
+		}
+		deletedFiles++
+	}
+	log.Printf("[DocumentVersion] 清理文档旧版本 %d 个，删除文件 %d 个", len(pruneIDs), deletedFiles)
+
+	response.SuccessWithData(c, gin.H{"versions": len(pruneIDs), "files": deletedFiles, "dryRun": false}, constant.PruneDocumentVersionsSuccess)
+}
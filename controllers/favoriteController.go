@@ -72,6 +72,9 @@ func CollectDocumentOrPost(c *gin.Context) {
 		response.Fail(c, http.StatusInternalServerError, nil, err.Error())
 		return
 	}
+	// 新增收藏后归因到推荐曝光，并在后台刷新该用户的推荐
+	go attributeRecommendConversion(userClaims.UserID, request.Type, request.SourceID)
+	refreshUserRecommendationsAsync(userClaims.UserID)
 
 	// 返回成功响应，携带用户收藏的相应资源列表
 	response.SuccessWithData(c, responseData, constant.FavoriteSuccessMsg)
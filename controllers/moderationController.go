@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// moderationContentLabels 审核提示词中使用的内容类型名称
+var moderationContentLabels = map[string]string{
+	constant.ModerationTypeDocument: "文档",
+	constant.ModerationTypePost:     "帖子",
+	constant.ModerationTypeComment:  "评论",
+	constant.ModerationTypeMessage:  "私信",
+}
+
+// moderateContent 对新写入的内容进行预审（本地规则 + AI）并保存审核记录，应在 goroutine 中调用
+// 高风险的帖子和评论自动隐藏，其余高风险或疑似违规的内容进入人工复核队列
+func moderateContent(contentType string, contentID, authorID uint64, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+
+	systemPrompt, _ := renderPrompt(constant.PromptKeyContentModeration, map[string]string{
+		"ContentType": moderationContentLabels[contentType],
+	})
+	result, err := utils.ModerateText(systemPrompt, text)
+	if err != nil {
+		// 模型不可用时仍按本地规则的结果处理
+		log.Printf("[Moderation] %s %d AI 审核失败，仅使用本地规则: %v", contentType, contentID, err)
+	}
+
+	hideThreshold, reviewThreshold := utils.ModerationThresholds()
+	canHide := contentType == constant.ModerationTypePost || contentType == constant.ModerationTypeComment
+	status := constant.ModerationStatusPass
+	switch {
+	case result.RiskScore >= hideThreshold && canHide:
+		status = constant.ModerationStatusHidden
+	case result.RiskScore >= reviewThreshold:
+		status = constant.ModerationStatusReview
+	}
+
+	if status == constant.ModerationStatusHidden {
+		if err := setContentHidden(contentType, contentID, true); err != nil {
+			log.Printf("[Moderation] 隐藏 %s %d 失败: %v", contentType, contentID, err)
+			status = constant.ModerationStatusReview
+		}
+	}
+
+	ruleHits, _ := json.Marshal(result.RuleHits)
+	record := models.ModerationRecord{
+		ContentType: contentType,
+		ContentID:   contentID,
+		AuthorID:    authorID,
+		RiskScore:   result.RiskScore,
+		Categories:  strings.Join(result.Categories, ","),
+		Reason:      result.Reason,
+		RuleHits:    string(ruleHits),
+		AIChecked:   result.AIChecked,
+		Status:      status,
+	}
+	if err := dao.SaveModerationRecord(&record); err != nil {
+		log.Printf("[Moderation] 保存 %s %d 的审核记录失败: %v", contentType, contentID, err)
+	}
+}
+
+// moderateDocument 对文档的名称、简介和提取到的正文进行预审（视频等无正文的文档只审核名称和简介），应在 goroutine 中调用
+func moderateDocument(documentID uint64, text string) {
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		log.Printf("[Moderation] 获取文档 %d 失败: %v", documentID, err)
+		return
+	}
+	moderateContent(constant.ModerationTypeDocument, document.ID, document.UploaderID,
+		strings.TrimSpace(document.Name+"\n"+document.Introduction+"\n"+text))
+}
+
+// setContentHidden 隐藏或恢复显示帖子、评论，其他类型内容不支持隐藏
+func setContentHidden(contentType string, contentID uint64, hidden bool) error {
+	switch contentType {
+	case constant.ModerationTypePost:
+		if err := dao.SetPostHidden(contentID, hidden); err != nil {
+			return err
+		}
+		// 帖子隐藏或恢复显示后，所有用户的帖子推荐需要重新计算
+		markRecommendationsStale()
+		return nil
+	case constant.ModerationTypeComment:
+		return dao.SetCommentHidden(contentID, hidden)
+	}
+	return nil
+}
+
+// applyModerationDecision 执行人工复核决定：通过时恢复显示（待审核文档直接开放），驳回时隐藏帖子和评论、关闭文档、删除私信
+func applyModerationDecision(record models.ModerationRecord, decision string) error {
+	approve := decision == constant.ModerationDecisionApprove
+	switch record.ContentType {
+	case constant.ModerationTypePost, constant.ModerationTypeComment:
+		return setContentHidden(record.ContentType, record.ContentID, !approve)
+	case constant.ModerationTypeDocument:
+		document, err := dao.GetDocumentByID(record.ContentID)
+		if err != nil {
+			return err
+		}
+		if !approve {
+			return setDocumentStatus(document, constant.DocumentStatusClosed)
+		}
+		if document.Status == constant.DocumentStatusPending {
+			return setDocumentStatus(document, constant.DocumentStatusOpen)
+		}
+		return nil
+	case constant.ModerationTypeMessage:
+		if !approve {
+			return dao.DeleteMessage(record.ContentID)
+		}
+	}
+	return nil
+}
+
+// AdminGetModerationQueue 管理员获取内容审核队列，默认返回待人工复核（含已自动隐藏）的记录
+// GET /api/admin/moderation?status=review,hidden&contentType=post
+func AdminGetModerationQueue(c *gin.Context) {
+	var req dto.GetModerationQueueDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	statuses := []string{constant.ModerationStatusReview, constant.ModerationStatusHidden}
+	if req.Status != "" {
+		statuses = strings.Split(req.Status, ",")
+	}
+
+	records, err := dao.GetModerationRecords(statuses, req.ContentType)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildModerationRecordResponses(records), constant.GetModerationQueueSuccess)
+}
+
+// AdminReviewModeration 管理员人工复核审核记录
+// PUT /api/admin/moderation/:recordId
+func AdminReviewModeration(c *gin.Context) {
+	recordID, err := strconv.ParseUint(c.Param("recordId"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	var req dto.ReviewModerationDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	record, err := dao.GetModerationRecordByID(recordID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.ModerationRecordNotExist)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	if err := applyModerationDecision(record, req.Decision); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.ModerationReviewFailed)
+		return
+	}
+
+	now := time.Now()
+	record.Status = constant.ModerationStatusApproved
+	if req.Decision == constant.ModerationDecisionReject {
+		record.Status = constant.ModerationStatusRejected
+	}
+	record.ReviewerID = &userClaims.UserID
+	record.ReviewNote = req.Note
+	record.ReviewedAt = &now
+	if err := dao.UpdateModerationRecord(&record); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.ModerationReviewFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildModerationRecordResponse(record), constant.ModerationReviewSuccess)
+}
@@ -42,11 +42,21 @@ func CreatePost(c *gin.Context) {
 		return
 	}
 
+	// 4. 敏感词过滤
+	title, ok := filterSensitiveText(c, req.Title)
+	if !ok {
+		return
+	}
+	content, ok := filterSensitiveText(c, req.Content)
+	if !ok {
+		return
+	}
+
 	// 5. 构建 Post 模型
 	post := models.Post{
 		SenderID: req.SenderID,
-		Title:    req.Title,
-		Content:  req.Content,
+		Title:    title,
+		Content:  content,
 		// SendTime 由 GORM 的 autoCreateTime 自动处理
 	}
 
@@ -56,6 +66,9 @@ func CreatePost(c *gin.Context) {
 		return
 	}
 
+	// 异步进行内容预审，高风险帖子会被自动隐藏
+	go moderateContent(constant.ModerationTypePost, post.ID, post.SenderID, post.Title+"\n"+post.Content)
+
 	// 7. 返回成功响应
 	// 构造返回数据
 	responseData := gin.H{
@@ -102,12 +115,23 @@ func GetPostDetail(c *gin.Context) {
 		return
 	}
 
-	// 3. 记录浏览历史 (异步)
+	var userClaims *utils.MyClaims
 	if claims, exists := c.Get(constant.UserClaims); exists {
-		userClaims := claims.(*utils.MyClaims)
+		userClaims = claims.(*utils.MyClaims)
+	}
+
+	// 被内容审核隐藏的帖子只有发帖人和管理员可以查看
+	if !canViewPost(post, userClaims) {
+		response.Fail(c, http.StatusNotFound, nil, constant.PostNotExist)
+		return
+	}
+
+	// 3. 记录浏览历史 (异步)
+	if userClaims != nil {
 		go func(uid uint64, pid uint64) {
 			// 传入 "post" 类型
 			_ = dao.AddViewHistory(uid, pid, "post")
+			attributeRecommendClick(uid, constant.PostType, pid)
 		}(userClaims.UserID, postID) // 回调函数实现异步
 	}
 
@@ -305,7 +329,7 @@ func GetUserPostList(c *gin.Context) {
 	collectPosts, err := dao.GetFavoritePostsByUserID(userID)
 
 	// 6. 获取用户发布的帖子列表
-	myPosts, err := dao.GetPostsByUserID(userID)
+	myPosts, err := dao.GetPostsByUserID(userID, userClaims.UserID)
 	if err != nil {
 		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
 		return
@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// renderPrompt 渲染某个标识当前生效的提示词，返回渲染结果和版本标签（如 ai_chat_system@v3）
+// 后台未配置、查询失败或模板渲染失败时回退到内置提示词（ai_chat_system@builtin）
+func renderPrompt(key string, vars map[string]string) (string, string) {
+	active, err := dao.GetActivePromptTemplate(key)
+	if err != nil {
+		log.Printf("[Prompt] 查询提示词 %s 失败，使用内置提示词: %v", key, err)
+	}
+	if active != nil {
+		rendered, err := utils.RenderPromptTemplate(active.Content, vars)
+		if err == nil {
+			return rendered, fmt.Sprintf("%s@v%d", key, active.Version)
+		}
+		log.Printf("[Prompt] 渲染提示词 %s v%d 失败，使用内置提示词: %v", key, active.Version, err)
+	}
+
+	rendered, err := utils.RenderPromptTemplate(constant.DefaultPromptTemplates[key], vars)
+	if err != nil {
+		log.Printf("[Prompt] 渲染内置提示词 %s 失败: %v", key, err)
+	}
+	return rendered, key + "@" + constant.PromptBuiltinVersion
+}
+
+// parsePromptKey 从路径参数中取出提示词标识并校验是否为已知标识
+func parsePromptKey(c *gin.Context) (string, bool) {
+	key := c.Param("key")
+	if _, ok := constant.DefaultPromptTemplates[key]; !ok {
+		response.Fail(c, http.StatusNotFound, nil, constant.PromptKeyNotExist)
+		return "", false
+	}
+	return key, true
+}
+
+// AdminGetPromptTemplates 管理员获取全部提示词标识及其当前生效内容
+// GET /api/admin/prompts
+func AdminGetPromptTemplates(c *gin.Context) {
+	keys := make([]string, 0, len(constant.DefaultPromptTemplates))
+	for key := range constant.DefaultPromptTemplates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := make([]response.PromptTemplateSummaryResponse, 0, len(keys))
+	for _, key := range keys {
+		active, err := dao.GetActivePromptTemplate(key)
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+			return
+		}
+		results = append(results, response.BuildPromptTemplateSummaryResponse(key, active))
+	}
+
+	response.SuccessWithData(c, results, constant.GetPromptTemplatesSuccess)
+}
+
+// AdminGetPromptTemplateVersions 管理员获取某个提示词标识的全部历史版本
+// GET /api/admin/prompts/:key/versions
+func AdminGetPromptTemplateVersions(c *gin.Context) {
+	key, ok := parsePromptKey(c)
+	if !ok {
+		return
+	}
+
+	templates, err := dao.GetPromptTemplateVersions(key)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildPromptTemplateVersionResponses(templates), constant.GetPromptTemplatesSuccess)
+}
+
+// AdminCreatePromptTemplateVersion 管理员为提示词标识新增版本（修改提示词即新增版本，旧版本保留用于回滚）
+// POST /api/admin/prompts/:key/versions
+func AdminCreatePromptTemplateVersion(c *gin.Context) {
+	key, ok := parsePromptKey(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreatePromptTemplateDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	// 保存前校验模板语法，避免线上渲染失败
+	if _, err := utils.ParsePromptTemplate(req.Content); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.PromptTemplateSyntaxError+err.Error())
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	tmpl := models.PromptTemplate{
+		TemplateKey: key,
+		Content:     req.Content,
+		Description: req.Description,
+		CreatorID:   userClaims.UserID,
+	}
+	if err := dao.CreatePromptTemplateVersion(&tmpl, req.Activate); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.CreatePromptTemplateFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildPromptTemplateVersionResponse(tmpl), constant.CreatePromptTemplateSuccess)
+}
+
+// AdminPreviewPromptTemplate 管理员使用示例变量预览提示词渲染结果
+// POST /api/admin/prompts/:key/preview
+func AdminPreviewPromptTemplate(c *gin.Context) {
+	key, ok := parsePromptKey(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PreviewPromptTemplateDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	content := req.Content
+	if content == "" {
+		if req.Version != nil {
+			tmpl, err := dao.GetPromptTemplateByVersion(key, *req.Version)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					response.Fail(c, http.StatusNotFound, nil, constant.PromptVersionNotExist)
+					return
+				}
+				response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+				return
+			}
+			content = tmpl.Content
+		} else {
+			active, err := dao.GetActivePromptTemplate(key)
+			if err != nil {
+				response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+				return
+			}
+			content = constant.DefaultPromptTemplates[key]
+			if active != nil {
+				content = active.Content
+			}
+		}
+	}
+
+	rendered, err := utils.RenderPromptTemplate(content, req.Variables)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.PromptTemplateSyntaxError+err.Error())
+		return
+	}
+
+	response.SuccessWithData(c, response.PromptPreviewResponse{
+		TemplateKey: key,
+		Variables:   utils.ExtractPromptVariables(content),
+		Rendered:    rendered,
+	}, constant.PreviewPromptTemplateSuccess)
+}
+
+// AdminRollbackPromptTemplate 管理员将提示词回滚到指定版本，version 为 0 时回退到内置提示词
+// PUT /api/admin/prompts/:key/active
+func AdminRollbackPromptTemplate(c *gin.Context) {
+	key, ok := parsePromptKey(c)
+	if !ok {
+		return
+	}
+
+	var req dto.RollbackPromptTemplateDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	if *req.Version == 0 {
+		if err := dao.DeactivatePromptTemplates(key); err != nil {
+			response.Fail(c, http.StatusInternalServerError, nil, constant.RollbackPromptFailed)
+			return
+		}
+		response.SuccessWithData(c, response.BuildPromptTemplateSummaryResponse(key, nil), constant.RollbackPromptSuccess)
+		return
+	}
+
+	tmpl, err := dao.ActivatePromptTemplateVersion(key, *req.Version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.PromptVersionNotExist)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.RollbackPromptFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildPromptTemplateSummaryResponse(key, &tmpl), constant.RollbackPromptSuccess)
+}
+
+// AdminDeletePromptTemplateVersion 管理员删除提示词的某个历史版本（正在启用的版本不允许删除）
+// DELETE /api/admin/prompts/:key/versions/:version
+func AdminDeletePromptTemplateVersion(c *gin.Context) {
+	key, ok := parsePromptKey(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	tmpl, err := dao.GetPromptTemplateByVersion(key, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.PromptVersionNotExist)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if tmpl.IsActive {
+		response.Fail(c, http.StatusBadRequest, nil, constant.PromptActiveVersionNoDelete)
+		return
+	}
+
+	if err := dao.DeletePromptTemplate(&tmpl); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DeletePromptTemplateFailed)
+		return
+	}
+
+	response.Success(c, nil, constant.DeletePromptTemplateSuccess)
+}
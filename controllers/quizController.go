@@ -0,0 +1,459 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	quizSourceMaxRunes          = 30000 // 出题素材的最大字符数，超出部分的页不参与出题
+	defaultQuizChoiceCount      = 5
+	defaultQuizShortAnswerCount = 2
+	defaultQuizFlashcardCount   = 5
+)
+
+// getAccessibleDocument 获取当前用户有权使用的文档（公开文档，或本人上传/管理员可访问非公开文档），失败时已写入响应
+func getAccessibleDocument(c *gin.Context, documentID uint64, userClaims *utils.MyClaims) (models.Document, bool) {
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.DocumentNotExist)
+			return models.Document{}, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.Document{}, false
+	}
+	if !canAccessDocument(document, userClaims) {
+		response.Fail(c, http.StatusForbidden, nil, constant.DocumentSummaryAccessDenied)
+		return models.Document{}, false
+	}
+	return document, true
+}
+
+// getOwnQuiz 根据路径参数获取当前用户保存的测验，失败时已写入响应
+func getOwnQuiz(c *gin.Context, userID uint64) (models.Quiz, bool) {
+	quizID, err := strconv.ParseUint(c.Param("quizId"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.Quiz{}, false
+	}
+	quiz, err := dao.GetQuizByID(quizID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.QuizNotExist)
+			return models.Quiz{}, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.Quiz{}, false
+	}
+	if quiz.UserID != userID {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.NonSelf)
+		return models.Quiz{}, false
+	}
+	return quiz, true
+}
+
+// buildQuizSource 按页码范围拼接出题素材（每页以【第N页】标注），超过 quizSourceMaxRunes 后的页不再加入
+// endPage 为 0 表示不限制结束页；返回素材及实际覆盖的首末页码
+func buildQuizSource(pages []utils.PDFPageText, startPage, endPage int) (string, int, int) {
+	var builder strings.Builder
+	first, last, total := 0, 0, 0
+	for _, page := range pages {
+		if page.Page < startPage || (endPage > 0 && page.Page > endPage) {
+			continue
+		}
+		block := fmt.Sprintf("【第%d页】\n%s\n\n", page.Page, page.Text)
+		blockRunes := utf8.RuneCountInString(block)
+		if first != 0 && total+blockRunes > quizSourceMaxRunes {
+			break
+		}
+		if first == 0 {
+			first = page.Page
+			// 单页就超长时截断该页
+			block = utils.TruncateRunesForSummary(block, quizSourceMaxRunes)
+		}
+		builder.WriteString(block)
+		total += blockRunes
+		last = page.Page
+	}
+	return strings.TrimSpace(builder.String()), first, last
+}
+
+// GenerateDocumentQuiz 根据文档正文生成选择题、简答题和记忆卡片（附答案和出处页码），结果需调用保存接口保存
+// POST /api/document/:id/quiz/generate
+func GenerateDocumentQuiz(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	var req dto.GenerateQuizDTO
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if req.ChoiceCount == 0 && req.ShortAnswerCount == 0 && req.FlashcardCount == 0 {
+		req.ChoiceCount = defaultQuizChoiceCount
+		req.ShortAnswerCount = defaultQuizShortAnswerCount
+		req.FlashcardCount = defaultQuizFlashcardCount
+	}
+	if req.EndPage > 0 && req.StartPage > req.EndPage {
+		response.Fail(c, http.StatusBadRequest, nil, constant.QuizPageRangeInvalid)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	document, ok := getAccessibleDocument(c, documentID, userClaims)
+	if !ok {
+		return
+	}
+	if !utils.DocumentURLPathLooksLikePDF(document.URL) {
+		response.Fail(c, http.StatusBadRequest, nil, constant.DocumentSummaryNotPDF)
+		return
+	}
+
+	pages, err := utils.ExtractDocumentPDFPages(utils.GetFileURL(document.URL))
+	if err != nil {
+		if errors.Is(err, utils.ErrSummaryEmptyText) {
+			response.Fail(c, http.StatusBadRequest, nil, constant.DocumentSummaryNoText)
+			return
+		}
+		if errors.Is(err, utils.ErrSummaryNotPDF) {
+			response.Fail(c, http.StatusBadRequest, nil, constant.DocumentSummaryNotPDF)
+			return
+		}
+		log.Printf("[Quiz] 提取文档 %d 正文失败: %v", document.ID, err)
+		response.Fail(c, http.StatusBadGateway, nil, constant.DocumentSourceFetchFailed)
+		return
+	}
+
+	sourceText, firstPage, lastPage := buildQuizSource(pages, req.StartPage, req.EndPage)
+	if sourceText == "" {
+		response.Fail(c, http.StatusBadRequest, nil, constant.QuizPageRangeNoText)
+		return
+	}
+
+	systemPrompt, promptVersion := renderPrompt(constant.PromptKeyQuizGenerate, map[string]string{
+		"ChoiceCount":      strconv.Itoa(req.ChoiceCount),
+		"ShortAnswerCount": strconv.Itoa(req.ShortAnswerCount),
+		"FlashcardCount":   strconv.Itoa(req.FlashcardCount),
+		"StartPage":        strconv.Itoa(firstPage),
+		"EndPage":          strconv.Itoa(lastPage),
+	})
+	userMsg := "以下为《" + document.Name + "》的正文，请按要求出题：\n\n" + sourceText
+
+	content, err := utils.GenerateQuiz(systemPrompt, userMsg, utils.QuizSpec{
+		ChoiceCount:      req.ChoiceCount,
+		ShortAnswerCount: req.ShortAnswerCount,
+		FlashcardCount:   req.FlashcardCount,
+		StartPage:        firstPage,
+		EndPage:          lastPage,
+	})
+	if err != nil {
+		if errors.Is(err, utils.ErrQuizMalformed) {
+			response.Fail(c, http.StatusBadGateway, nil, constant.QuizGenerateFailed)
+			return
+		}
+		log.Printf("[Quiz] 文档 %d 生成测验失败: %v", document.ID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.QuizGenerateFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.GeneratedQuizResponse{
+		DocumentID:    document.ID,
+		StartPage:     firstPage,
+		EndPage:       lastPage,
+		PromptVersion: promptVersion,
+		Questions:     content.Questions,
+		Flashcards:    content.Flashcards,
+	}, constant.QuizGenerateSuccess)
+}
+
+// SaveDocumentQuiz 保存生成的测验，保存前按同一结构重新校验
+// POST /api/document/:id/quizzes
+func SaveDocumentQuiz(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	var req dto.SaveQuizDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if req.StartPage > req.EndPage {
+		response.Fail(c, http.StatusBadRequest, nil, constant.QuizPageRangeInvalid)
+		return
+	}
+	if len(req.Questions) == 0 && len(req.Flashcards) == 0 {
+		response.Fail(c, http.StatusBadRequest, nil, constant.QuizCountRequired)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	if _, ok := getAccessibleDocument(c, documentID, userClaims); !ok {
+		return
+	}
+
+	content := utils.QuizContent{
+		Questions:  make([]utils.QuizQuestion, len(req.Questions)),
+		Flashcards: make([]utils.Flashcard, len(req.Flashcards)),
+	}
+	spec := utils.QuizSpec{
+		FlashcardCount: len(req.Flashcards),
+		StartPage:      req.StartPage,
+		EndPage:        req.EndPage,
+	}
+	for i, q := range req.Questions {
+		content.Questions[i] = utils.QuizQuestion(q)
+		if q.Type == utils.QuizQuestionChoice {
+			spec.ChoiceCount++
+		} else {
+			spec.ShortAnswerCount++
+		}
+	}
+	for i, card := range req.Flashcards {
+		content.Flashcards[i] = utils.Flashcard(card)
+	}
+	if err := utils.ValidateQuizContent(content, spec); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.QuizContentInvalid+err.Error())
+		return
+	}
+
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.QuizSaveFailed)
+		return
+	}
+	quiz := models.Quiz{
+		DocumentID:     documentID,
+		UserID:         userClaims.UserID,
+		Title:          req.Title,
+		StartPage:      req.StartPage,
+		EndPage:        req.EndPage,
+		Content:        string(contentJSON),
+		QuestionCount:  len(content.Questions),
+		FlashcardCount: len(content.Flashcards),
+	}
+	if err := dao.CreateQuiz(&quiz); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.QuizSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildQuizBriefResponse(quiz), constant.QuizSaveSuccess)
+}
+
+// GetDocumentQuizzes 获取当前用户为某个文档保存的测验列表
+// GET /api/document/:id/quizzes
+func GetDocumentQuizzes(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	quizzes, err := dao.GetQuizzesByUserAndDocument(userClaims.UserID, documentID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildQuizBriefResponses(quizzes), constant.GetQuizzesSuccess)
+}
+
+// GetQuizForTaking 获取测验用于答题（不含答案和解析）
+// GET /api/quizzes/:quizId
+func GetQuizForTaking(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	quiz, ok := getOwnQuiz(c, userClaims.UserID)
+	if !ok {
+		return
+	}
+
+	resp, err := response.BuildQuizTakeResponse(quiz)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, resp, constant.GetQuizzesSuccess)
+}
+
+// DeleteQuiz 删除自己保存的测验（答题记录一并删除）
+// DELETE /api/quizzes/:quizId
+func DeleteQuiz(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	quiz, ok := getOwnQuiz(c, userClaims.UserID)
+	if !ok {
+		return
+	}
+
+	if err := dao.DeleteQuiz(&quiz); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.QuizDeleteFailed)
+		return
+	}
+
+	response.Success(c, nil, constant.QuizDeleteSuccess)
+}
+
+// SubmitQuizAttempt 提交答题：选择题自动判分，简答题按用户对照参考答案的自评计分，返回每道题的答案和解析
+// POST /api/quizzes/:quizId/attempts
+func SubmitQuizAttempt(c *gin.Context) {
+	var req dto.SubmitQuizAttemptDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	quiz, ok := getOwnQuiz(c, userClaims.UserID)
+	if !ok {
+		return
+	}
+
+	var content utils.QuizContent
+	if err := json.Unmarshal([]byte(quiz.Content), &content); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	answers := make(map[int]dto.QuizAnswerDTO, len(req.Answers))
+	for _, answer := range req.Answers {
+		if answer.QuestionIndex >= len(content.Questions) {
+			response.Fail(c, http.StatusBadRequest, nil, constant.QuizAnswerIndexInvalid)
+			return
+		}
+		answers[answer.QuestionIndex] = answer
+	}
+
+	// 未作答的题目计为答错
+	results := make([]response.QuizAnswerResultResponse, len(content.Questions))
+	correctCount := 0
+	for i, q := range content.Questions {
+		answer := answers[i]
+		correct := false
+		if q.Type == utils.QuizQuestionChoice {
+			index := utils.QuizChoiceAnswerIndex(answer.Answer)
+			correct = index >= 0 && index == utils.QuizChoiceAnswerIndex(q.Answer)
+		} else {
+			correct = answer.SelfCorrect
+		}
+		if correct {
+			correctCount++
+		}
+		results[i] = response.QuizAnswerResultResponse{
+			QuestionIndex: i,
+			Answer:        answer.Answer,
+			Correct:       correct,
+			CorrectAnswer: q.Answer,
+			Explanation:   q.Explanation,
+		}
+	}
+
+	score := 0.0
+	if len(content.Questions) > 0 {
+		score = math.Round(float64(correctCount)/float64(len(content.Questions))*1000) / 10
+	}
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.QuizAttemptSaveFailed)
+		return
+	}
+	attempt := models.QuizAttempt{
+		QuizID:       quiz.ID,
+		UserID:       userClaims.UserID,
+		DocumentID:   quiz.DocumentID,
+		Answers:      string(resultsJSON),
+		CorrectCount: correctCount,
+		TotalCount:   len(content.Questions),
+		Score:        score,
+	}
+	if err := dao.CreateQuizAttempt(&attempt); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.QuizAttemptSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildQuizAttemptResponse(attempt, quiz.Title, results), constant.QuizAttemptSubmitted)
+}
+
+// GetDocumentQuizAttempts 获取当前用户在某个文档下的答题成绩历史
+// GET /api/document/:id/quiz-attempts
+func GetDocumentQuizAttempts(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	attempts, err := dao.GetQuizAttemptsByUserAndDocument(userClaims.UserID, documentID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildQuizAttemptHistoryResponses(attempts), constant.GetQuizAttemptsSuccess)
+}
@@ -0,0 +1,455 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// getReadableDocument 根据路径参数获取当前用户可以阅读的文档，失败时已写入响应
+func getReadableDocument(c *gin.Context, userClaims *utils.MyClaims) (models.Document, bool) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.Document{}, false
+	}
+	document, err := dao.GetDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.DocumentNotExist)
+			return models.Document{}, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.Document{}, false
+	}
+	if !canAccessDocument(document, userClaims) {
+		response.Fail(c, http.StatusForbidden, nil, constant.ReadingAccessDenied)
+		return models.Document{}, false
+	}
+	return document, true
+}
+
+// validReadingPage 页码不能超过文档页数（页数未知时不校验），失败时已写入响应
+func validReadingPage(c *gin.Context, document models.Document, page int) bool {
+	if document.PageCount > 0 && page > document.PageCount {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ReadingPageInvalid)
+		return false
+	}
+	return true
+}
+
+// GetReadingState 获取当前用户在文档中的阅读进度、书签和划线，阅读器打开文档时调用
+// GET /api/document/:id/reading
+func GetReadingState(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	document, ok := getReadableDocument(c, userClaims)
+	if !ok {
+		return
+	}
+
+	var progress *models.ReadingProgress
+	found, err := dao.GetReadingProgress(userClaims.UserID, document.ID)
+	if err == nil {
+		progress = &found
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	bookmarks, err := dao.GetReadingBookmarks(userClaims.UserID, document.ID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	highlights, err := dao.GetReadingHighlights(userClaims.UserID, document.ID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildReadingStateResponse(progress, bookmarks, highlights), constant.GetReadingStateSuccess)
+}
+
+// SyncReadingProgress 阅读器同步阅读进度。多端阅读时按阅读器记录进度的时间判断新旧，较早的进度不会覆盖较新的进度；
+// 只传页码时按文档页数计算进度百分比
+// PUT /api/document/:id/reading/progress
+func SyncReadingProgress(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.SyncReadingProgressDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	document, ok := getReadableDocument(c, userClaims)
+	if !ok {
+		return
+	}
+	if !validReadingPage(c, document, req.Page) {
+		return
+	}
+
+	// 阅读器时间晚于服务器时间时以服务器时间为准，避免一台设备的时钟错误导致其他设备的进度无法保存
+	readAt := time.Now()
+	if req.ReadAt > 0 && time.UnixMilli(req.ReadAt).Before(readAt) {
+		readAt = time.UnixMilli(req.ReadAt)
+	}
+	// read_at 只保存到秒，按秒比较新旧，避免写入时的进位使刚保存的进度被判断为过时
+	readAt = readAt.Truncate(time.Second)
+	percent := req.Percent
+	if percent == 0 && req.Page > 0 && document.PageCount > 0 {
+		percent = math.Round(float64(req.Page)/float64(document.PageCount)*10000) / 100
+	}
+
+	progress := models.ReadingProgress{
+		UserID:     userClaims.UserID,
+		DocumentID: document.ID,
+		Page:       req.Page,
+		Position:   req.Position,
+		Percent:    percent,
+		ReadAt:     readAt,
+	}
+	outdated, err := dao.SyncReadingProgress(&progress)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.SyncReadingProgressFailed)
+		return
+	}
+	if outdated {
+		// 已有其他设备同步的更新的进度，返回该进度供阅读器跳转
+		response.SuccessWithData(c, response.BuildReadingProgressResponse(progress), constant.ReadingProgressOutdated)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildReadingProgressResponse(progress), constant.SyncReadingProgressSuccess)
+}
+
+// GetContinueReading 获取当前用户未读完的文档及阅读进度（按最近阅读时间倒序）
+// GET /api/reading/continue?page=1&pageSize=10
+func GetContinueReading(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.GetContinueReadingDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = constant.HomepageContinueLimit
+	}
+
+	items, total, err := dao.GetContinueReading(userClaims.UserID, req.Page, req.PageSize)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	responseData := response.ContinueReadingListResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     response.BuildContinueReadingResponses(items),
+	}
+	response.SuccessWithData(c, responseData, constant.GetContinueReadingSuccess)
+}
+
+// CreateReadingBookmark 在文档中添加书签，未命名的书签按页码命名
+// POST /api/document/:id/bookmarks
+func CreateReadingBookmark(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.CreateBookmarkDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	document, ok := getReadableDocument(c, userClaims)
+	if !ok {
+		return
+	}
+	if !validReadingPage(c, document, req.Page) {
+		return
+	}
+
+	count, err := dao.CountReadingBookmarks(userClaims.UserID, document.ID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if count >= constant.MaxReadingBookmarks {
+		response.Fail(c, http.StatusBadRequest, nil, constant.BookmarkLimitExceeded)
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = constant.DefaultBookmarkNoPage
+		if req.Page > 0 {
+			name = fmt.Sprintf(constant.DefaultBookmarkName, req.Page)
+		}
+	}
+	bookmark := models.ReadingBookmark{
+		UserID:     userClaims.UserID,
+		DocumentID: document.ID,
+		Name:       name,
+		Page:       req.Page,
+		Position:   req.Position,
+	}
+	if err := dao.CreateReadingBookmark(&bookmark); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.BookmarkSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildReadingBookmarkResponse(bookmark), constant.BookmarkSaveSuccess)
+}
+
+// getOwnBookmark 根据路径参数获取当前用户的书签，失败时已写入响应
+func getOwnBookmark(c *gin.Context, userID uint64) (models.ReadingBookmark, bool) {
+	bookmarkID, err := strconv.ParseUint(c.Param("bookmarkId"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.ReadingBookmark{}, false
+	}
+	bookmark, err := dao.GetReadingBookmarkByID(bookmarkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.BookmarkNotExist)
+			return models.ReadingBookmark{}, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.ReadingBookmark{}, false
+	}
+	if bookmark.UserID != userID {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.NonSelf)
+		return models.ReadingBookmark{}, false
+	}
+	return bookmark, true
+}
+
+// UpdateReadingBookmark 重命名书签
+// PUT /api/bookmarks/:bookmarkId
+func UpdateReadingBookmark(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.UpdateBookmarkDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	bookmark, ok := getOwnBookmark(c, userClaims.UserID)
+	if !ok {
+		return
+	}
+
+	bookmark.Name = strings.TrimSpace(req.Name)
+	if err := dao.UpdateReadingBookmark(&bookmark); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.BookmarkSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildReadingBookmarkResponse(bookmark), constant.BookmarkSaveSuccess)
+}
+
+// DeleteReadingBookmark 删除书签
+// DELETE /api/bookmarks/:bookmarkId
+func DeleteReadingBookmark(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	bookmark, ok := getOwnBookmark(c, userClaims.UserID)
+	if !ok {
+		return
+	}
+	if err := dao.DeleteReadingBookmark(&bookmark); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.BookmarkDeleteFailed)
+		return
+	}
+
+	response.Success(c, nil, constant.BookmarkDeleteSuccess)
+}
+
+// CreateReadingHighlight 在文档中添加划线和笔记，按页码和页内字符偏移定位
+// POST /api/document/:id/highlights
+func CreateReadingHighlight(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.CreateHighlightDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if req.Color == "" {
+		req.Color = constant.DefaultHighlightColor
+	}
+	if !slices.Contains(constant.HighlightColors, req.Color) {
+		response.Fail(c, http.StatusBadRequest, nil, constant.HighlightColorInvalid)
+		return
+	}
+	document, ok := getReadableDocument(c, userClaims)
+	if !ok {
+		return
+	}
+	if !validReadingPage(c, document, req.Page) {
+		return
+	}
+
+	count, err := dao.CountReadingHighlights(userClaims.UserID, document.ID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	if count >= constant.MaxReadingHighlights {
+		response.Fail(c, http.StatusBadRequest, nil, constant.HighlightLimitExceeded)
+		return
+	}
+
+	highlight := models.ReadingHighlight{
+		UserID:      userClaims.UserID,
+		DocumentID:  document.ID,
+		Page:        req.Page,
+		StartOffset: req.StartOffset,
+		EndOffset:   req.EndOffset,
+		Text:        req.Text,
+		Note:        req.Note,
+		Color:       req.Color,
+	}
+	if err := dao.CreateReadingHighlight(&highlight); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.HighlightSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildReadingHighlightResponse(highlight), constant.HighlightSaveSuccess)
+}
+
+// getOwnHighlight 根据路径参数获取当前用户的划线，失败时已写入响应
+func getOwnHighlight(c *gin.Context, userID uint64) (models.ReadingHighlight, bool) {
+	highlightID, err := strconv.ParseUint(c.Param("highlightId"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.ReadingHighlight{}, false
+	}
+	highlight, err := dao.GetReadingHighlightByID(highlightID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.HighlightNotExist)
+			return models.ReadingHighlight{}, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.ReadingHighlight{}, false
+	}
+	if highlight.UserID != userID {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.NonSelf)
+		return models.ReadingHighlight{}, false
+	}
+	return highlight, true
+}
+
+// UpdateReadingHighlight 修改划线的笔记和颜色
+// PUT /api/highlights/:highlightId
+func UpdateReadingHighlight(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.UpdateHighlightDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if req.Color != nil && !slices.Contains(constant.HighlightColors, *req.Color) {
+		response.Fail(c, http.StatusBadRequest, nil, constant.HighlightColorInvalid)
+		return
+	}
+	highlight, ok := getOwnHighlight(c, userClaims.UserID)
+	if !ok {
+		return
+	}
+
+	if req.Note != nil {
+		highlight.Note = *req.Note
+	}
+	if req.Color != nil {
+		highlight.Color = *req.Color
+	}
+	if err := dao.UpdateReadingHighlight(&highlight); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.HighlightSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildReadingHighlightResponse(highlight), constant.HighlightSaveSuccess)
+}
+
+// DeleteReadingHighlight 删除划线及其笔记
+// DELETE /api/highlights/:highlightId
+func DeleteReadingHighlight(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	highlight, ok := getOwnHighlight(c, userClaims.UserID)
+	if !ok {
+		return
+	}
+	if err := dao.DeleteReadingHighlight(&highlight); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.HighlightDeleteFailed)
+		return
+	}
+
+	response.Success(c, nil, constant.HighlightDeleteSuccess)
+}
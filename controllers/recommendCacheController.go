@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/spf13/viper"
+)
+
+// getCachedRecommendations 优先返回 Redis 中预计算的推荐结果（stale-while-revalidate）：
+// 缓存已过期时先返回旧结果并在后台刷新；没有缓存（如新用户）时当场计算并写入缓存
+func getCachedRecommendations[T any](userID uint64, kind string, compute func() ([]T, error)) ([]T, error) {
+	entry, err := utils.GetRecommendCache(userID, kind)
+	if err != nil {
+		log.Printf("[Recommend] 读取用户 %d 的 %s 推荐缓存失败: %v", userID, kind, err)
+	}
+	if entry != nil {
+		var items []T
+		if err := json.Unmarshal(entry.Items, &items); err == nil {
+			if utils.IsRecommendCacheStale(entry) {
+				go func() {
+					if err := refreshUserRecommendation(userID, kind); err != nil {
+						log.Printf("[Recommend] 后台刷新用户 %d 的 %s 推荐失败: %v", userID, kind, err)
+					}
+				}()
+			}
+			return items, nil
+		}
+	}
+
+	items, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.SetRecommendCache(userID, kind, items); err != nil {
+		log.Printf("[Recommend] 写入用户 %d 的 %s 推荐缓存失败: %v", userID, kind, err)
+	}
+	return items, nil
+}
+
+// computeRecommendations 计算用户某类推荐的完整结果
+func computeRecommendations(userID uint64, kind string) (any, error) {
+	for strategy, strategyKind := range constant.RecommendStrategyKinds {
+		if kind == strategyKind {
+			return computeBookRecommendations(userID, strategy)
+		}
+	}
+	return recommendForUser(userID, kind, constant.RecommendCacheSize)
+}
+
+// userRecommendKinds 需要为用户预计算的推荐类型：各类型的混合推荐，以及用户当前书籍推荐策略（参与实验时为所在变体）对应的类型
+func userRecommendKinds(userID uint64) []string {
+	return append(slices.Clone(constant.RecommendKinds), constant.RecommendStrategyKinds[bookRecommendStrategy(userID)])
+}
+
+// refreshUserRecommendation 重新计算用户某类推荐并写入缓存，同一用户同一类型正在刷新时直接跳过
+func refreshUserRecommendation(userID uint64, kind string) error {
+	lockKey := constant.RecommendRefreshLockPrefix + kind + ":" + strconv.FormatUint(userID, 10)
+	if !utils.TryLockRecommend(lockKey, constant.RecommendRefreshLockSeconds*time.Second) {
+		return nil
+	}
+	defer utils.UnlockRecommend(lockKey)
+
+	items, err := computeRecommendations(userID, kind)
+	if err != nil {
+		return err
+	}
+	return utils.SetRecommendCache(userID, kind, items)
+}
+
+// refreshUserRecommendationsAsync 用户有重要行为（如新增收藏）后在后台刷新其全部推荐
+func refreshUserRecommendationsAsync(userID uint64) {
+	go func() {
+		for _, kind := range userRecommendKinds(userID) {
+			if err := refreshUserRecommendation(userID, kind); err != nil {
+				log.Printf("[Recommend] 刷新用户 %d 的 %s 推荐失败: %v", userID, kind, err)
+			}
+		}
+	}()
+}
+
+// markRecommendationsStale 可推荐的资源发生变化（新资源审核通过、文档被关闭或撤回、帖子被隐藏）时将所有用户的推荐标记为过期，各用户下次访问时在后台刷新
+func markRecommendationsStale() {
+	if err := utils.MarkAllRecommendationsStale(); err != nil {
+		log.Printf("[Recommend] 标记推荐缓存过期失败: %v", err)
+	}
+}
+
+// runRecommendationJob 为最近活跃的用户批量预计算各类推荐，多实例部署时同一周期内只有一个实例执行
+func runRecommendationJob(interval time.Duration) {
+	if !utils.TryLockRecommend(constant.RecommendJobLockKey, interval) {
+		return
+	}
+
+	activeDays := viper.GetInt("recommend.active_days")
+	if activeDays <= 0 {
+		activeDays = constant.DefaultRecommendActiveDays
+	}
+	userIDs, err := dao.GetActiveInteractionUserIDs(time.Now().AddDate(0, 0, -activeDays))
+	if err != nil {
+		log.Printf("[Recommend] 获取活跃用户失败: %v", err)
+		return
+	}
+
+	start := time.Now()
+	failed := 0
+	for _, userID := range userIDs {
+		for _, kind := range userRecommendKinds(userID) {
+			if err := refreshUserRecommendation(userID, kind); err != nil {
+				failed++
+				log.Printf("[Recommend] 预计算用户 %d 的 %s 推荐失败: %v", userID, kind, err)
+			}
+		}
+	}
+	log.Printf("[Recommend] 推荐预计算完成，用户 %d 个，失败 %d 次，耗时 %s", len(userIDs), failed, time.Since(start).Round(time.Second))
+}
+
+// StartRecommendationJob 启动推荐预计算定时任务：启动时执行一次，之后按配置的间隔（recommend.refresh_interval_minutes）执行
+func StartRecommendationJob() {
+	minutes := viper.GetInt("recommend.refresh_interval_minutes")
+	if minutes <= 0 {
+		minutes = constant.DefaultRecommendRefreshIntervalMinutes
+	}
+	interval := time.Duration(minutes) * time.Minute
+
+	go func() {
+		runRecommendationJob(interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			runRecommendationJob(interval)
+		}
+	}()
+}
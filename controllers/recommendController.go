@@ -3,36 +3,86 @@ package controllers
 import (
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/antidote-kt/SSE_Library-back/constant"
 	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
 	"github.com/antidote-kt/SSE_Library-back/models"
 	"github.com/antidote-kt/SSE_Library-back/response"
 	"github.com/antidote-kt/SSE_Library-back/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 )
 
-// GetBookRecommendations 获取书籍推荐
+// GetBookRecommendations 获取书籍推荐，优先返回预计算的缓存结果
 func GetBookRecommendations(c *gin.Context) {
-	// 1. 获取当前用户 ID
 	claims, exists := c.Get(constant.UserClaims)
 	if !exists {
 		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
 		return
 	}
 	userClaims := claims.(*utils.MyClaims)
-	userID := userClaims.UserID
 
-	// 2. 获取用户最近浏览的书籍记录 (最多取出10本book)
-	histories, _, err := dao.GetUserViewHistory(userID, "document", 1, 20)
+	// 参与推荐实验的用户使用所在变体的策略，并记录曝光
+	strategy := constant.RecommendStrategyLLMRerank
+	experiment, variant := assignBookRecommendVariant(userClaims.UserID)
+	if experiment != nil {
+		strategy = variant.Strategy
+	}
+
+	respList, err := getCachedRecommendations(userClaims.UserID, constant.RecommendStrategyKinds[strategy], func() ([]response.DocumentDetailResponse, error) {
+		return computeBookRecommendations(userClaims.UserID, strategy)
+	})
 	if err != nil {
-		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		log.Printf("[Recommend] 用户 %d 的书籍推荐生成失败: %v", userClaims.UserID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.GetRecommendationsFailed)
 		return
 	}
+	if experiment != nil {
+		go logRecommendImpressions(experiment.ID, variant.Name, userClaims.UserID, respList)
+	}
+
+	response.SuccessWithData(c, respList, constant.GetRecommendationsSuccess)
+}
+
+// computeBookRecommendations 按推荐策略计算书籍推荐：popularity 取阅读量最高的书籍，hybrid 使用混合推荐，
+// vector_only 和 llm_rerank 根据用户最近浏览的书籍计算兴趣向量并从 Milvus 检索相似书籍，llm_rerank 再由大模型重排
+func computeBookRecommendations(userID uint64, strategy string) ([]response.DocumentDetailResponse, error) {
+	switch strategy {
+	case constant.RecommendStrategyPopularity:
+		topBooks, err := dao.GetTopReadBooks(10)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int64, 0, len(topBooks))
+		for _, b := range topBooks {
+			ids = append(ids, int64(b.ID))
+		}
+		return buildOrderedDocumentDetails(ids), nil
+	case constant.RecommendStrategyHybrid:
+		items, err := recommendForUser(userID, constant.RecommendTypeBook, 10)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int64, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, int64(item.Document.DocumentID))
+		}
+		return buildOrderedDocumentDetails(ids), nil
+	}
+
+	// 1. 获取用户最近浏览的书籍记录 (最多取出10本book)
+	histories, _, err := dao.GetUserViewHistory(userID, "document", 1, 20)
+	if err != nil {
+		return nil, err
+	}
 
 	var recentBooks []string
 	var recentBookTexts []string
@@ -59,18 +109,16 @@ func GetBookRecommendations(c *gin.Context) {
 		// 冷启动：获取阅读量最高的10本书
 		topBooks, err := dao.GetTopReadBooks(10)
 		if err != nil || len(topBooks) == 0 {
-			response.SuccessWithData(c, []response.DocumentDetailResponse{}, "获取推荐成功")
-			return
+			return []response.DocumentDetailResponse{}, nil
 		}
 		for _, b := range topBooks {
 			recommendIds = append(recommendIds, int64(b.ID))
 		}
 	} else {
-		// 3. 计算用户兴趣向量 (求均值)
+		// 2. 计算用户兴趣向量 (求均值)
 		vectors, err := utils.GetEmbeddings(recentBookTexts)
 		if err != nil || len(vectors) == 0 {
-			response.Fail(c, http.StatusInternalServerError, nil, "兴趣向量计算失败")
-			return
+			return nil, fmt.Errorf("兴趣向量计算失败: %v", err)
 		}
 
 		dim := len(vectors[0])
@@ -84,7 +132,7 @@ func GetBookRecommendations(c *gin.Context) {
 			avgVector[i] /= float32(len(vectors))
 		}
 
-		// 4. 从 Milvus 检索最相似的 10 本书
+		// 3. 从 Milvus 检索最相似的 10 本书
 		recommendIds, err = utils.SearchBooks(avgVector, 10)
 		if err != nil || len(recommendIds) == 0 {
 			// 退级容错：取最热书籍
@@ -97,15 +145,18 @@ func GetBookRecommendations(c *gin.Context) {
 
 	// Milvus中找不到相似推荐书籍，直接返回空数据（无需再ai重排序）
 	if len(recommendIds) == 0 {
-		response.SuccessWithData(c, []response.DocumentDetailResponse{}, "当前没有匹配用户浏览偏好的书籍")
-		return
+		return []response.DocumentDetailResponse{}, nil
 	}
 
-	// 5. 准备大模型重排的 Prompt
+	// 仅向量检索的策略直接按相似度顺序返回
+	if strategy == constant.RecommendStrategyVectorOnly {
+		return buildOrderedDocumentDetails(recommendIds), nil
+	}
+
+	// 4. 准备大模型重排的 Prompt
 	candidates, err := dao.GetDocumentsByIDs(recommendIds) // 注意这个函数只会提取通过审核的书籍
 	if err != nil {
-		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
-		return
+		return nil, err
 	}
 
 	var candidateStrs []string
@@ -114,16 +165,10 @@ func GetBookRecommendations(c *gin.Context) {
 	}
 	log.Printf("推荐候选书籍如下：%s ,即将由ai重排推荐", candidateStrs)
 
-	prompt := "你是一个专业的图书推荐助手。\n"
-	if len(recentBooks) > 0 {
-		prompt += "用户最近关注：\n" + strings.Join(recentBooks, "\n") + "\n\n"
-	} else {
-		prompt += "该用户是新用户，暂无浏览记录。\n\n"
-	}
-
-	prompt += "请从以下备选库中，挑选出最适合该用户的几本书并进行重排：\n"
-	prompt += strings.Join(candidateStrs, "\n") + "\n\n"
-	prompt += "请直接回复推荐的书籍ID，使用逗号分隔，不要输出其他废话。"
+	prompt, _ := renderPrompt(constant.PromptKeyBookRecommendRerank, map[string]string{
+		"RecentBooks": strings.Join(recentBooks, "\n"),
+		"Candidates":  strings.Join(candidateStrs, "\n"),
+	})
 
 	// 调用大模型
 	messages := []utils.Message{
@@ -138,7 +183,7 @@ func GetBookRecommendations(c *gin.Context) {
 		}
 	}
 
-	// 6. 解析 AI 返回的 IDs
+	// 5. 解析 AI 返回的 IDs
 	re := regexp.MustCompile(`\d+`)
 	matches := re.FindAllString(aiResponse, -1)
 
@@ -157,16 +202,32 @@ func GetBookRecommendations(c *gin.Context) {
 		finalIds = recommendIds
 	}
 
-	// 获取最终书籍详情并构建响应
-	finalDocs, _ := dao.GetDocumentsByIDs(finalIds)
-	// 按 AI 返回顺序排序
+	// 获取最终书籍详情并按 AI 返回顺序构建响应
+	respList := buildOrderedDocumentDetails(finalIds)
+
+	// 如果最后仍然为空（例如id无效），则直接将初筛书籍构建返回
+	if len(respList) == 0 {
+		for _, doc := range candidates {
+			detailResp, err := response.BuildDocumentDetailResponse(doc)
+			if err == nil {
+				respList = append(respList, detailResp)
+			}
+		}
+	}
+
+	return respList, nil
+}
+
+// buildOrderedDocumentDetails 按给定 ID 顺序构建公开文档的详情（未公开或不存在的文档跳过）
+func buildOrderedDocumentDetails(ids []int64) []response.DocumentDetailResponse {
+	documents, _ := dao.GetDocumentsByIDs(ids)
 	docMap := make(map[uint64]models.Document)
-	for _, doc := range finalDocs {
+	for _, doc := range documents {
 		docMap[doc.ID] = doc
 	}
 
-	var respList []response.DocumentDetailResponse
-	for _, id := range finalIds {
+	respList := make([]response.DocumentDetailResponse, 0, len(ids))
+	for _, id := range ids {
 		if doc, ok := docMap[uint64(id)]; ok {
 			detailResp, err := response.BuildDocumentDetailResponse(doc)
 			if err == nil {
@@ -174,16 +235,493 @@ func GetBookRecommendations(c *gin.Context) {
 			}
 		}
 	}
+	return respList
+}
 
-	// 如果最后仍然为空（例如id无效），则直接将初筛书籍构建返回
-	if len(respList) == 0 {
-		for _, doc := range candidates {
-			detailResp, err := response.BuildDocumentDetailResponse(doc)
-			if err == nil {
-				respList = append(respList, detailResp)
+// recommendCandidate 推荐候选内容及其各项得分
+type recommendCandidate struct {
+	itemID     uint64
+	document   models.Document
+	post       models.Post
+	content    float64 // 内容相似度：与用户兴趣画像中分类、标签的匹配程度
+	cf         float64 // 物品协同过滤得分
+	popularity float64 // 热度
+	score      float64 // 混合得分
+	contentTip string  // 内容相似度对应的推荐理由
+	cfSeed     uint64  // 对协同过滤得分贡献最大的用户交互内容
+	cfSeedBest float64
+}
+
+// interactionVerbs 推荐理由中各交互行为的描述
+var interactionVerbs = map[string]string{
+	constant.InteractionFavorite: "收藏了",
+	constant.InteractionLike:     "点赞了",
+	constant.InteractionComment:  "评论了",
+	constant.InteractionView:     "浏览过",
+}
+
+// recommendWeights 获取内容相似度、协同过滤、热度在混合得分中的权重（可在配置文件 recommend 下覆盖）
+func recommendWeights() (content, cf, popularity float64) {
+	content, cf, popularity = constant.DefaultRecommendContentWeight, constant.DefaultRecommendCFWeight, constant.DefaultRecommendPopularityWeight
+	if viper.IsSet("recommend.content_weight") {
+		content = viper.GetFloat64("recommend.content_weight")
+	}
+	if viper.IsSet("recommend.cf_weight") {
+		cf = viper.GetFloat64("recommend.cf_weight")
+	}
+	if viper.IsSet("recommend.popularity_weight") {
+		popularity = viper.GetFloat64("recommend.popularity_weight")
+	}
+	return content, cf, popularity
+}
+
+// recommendForUser 为用户生成指定类型（book、file、video、post）的混合推荐：
+// 内容相似度（分类、标签画像）+ 物品协同过滤（浏览、收藏、点赞、评论的共现）+ 热度，排除用户已浏览、互动过或自己发布的内容
+func recommendForUser(userID uint64, itemType string, limit int) ([]response.RecommendationResponse, error) {
+	itemKind := constant.DocumentType
+	if itemType == constant.RecommendTypePost {
+		itemKind = constant.PostType
+	}
+
+	interactions, err := dao.GetUserInteractions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 已消费的内容和种子（用户最近交互过的内容，按行为权重累加）
+	consumed := make(map[uint64]bool)
+	seedWeights := map[string]map[uint64]float64{constant.DocumentType: {}, constant.PostType: {}}
+	seedActions := make(map[uint64]string) // 用户对同类种子最有代表性的行为
+	for _, interaction := range interactions {
+		if interaction.ItemType == itemKind {
+			consumed[interaction.ItemID] = true
+		}
+		weight, ok := constant.InteractionWeights[interaction.Action]
+		seeds := seedWeights[interaction.ItemType]
+		if !ok || seeds == nil {
+			continue
+		}
+		if _, exists := seeds[interaction.ItemID]; !exists && len(seeds) >= constant.RecommendSeedLimit {
+			continue
+		}
+		seeds[interaction.ItemID] += weight
+		if interaction.ItemType == itemKind && weight > constant.InteractionWeights[seedActions[interaction.ItemID]] {
+			seedActions[interaction.ItemID] = interaction.Action
+		}
+	}
+
+	// 2. 兴趣画像：交互过的文档（以及帖子关联文档）的分类和标签
+	categoryWeights, tagWeights, err := buildInterestProfile(seedWeights[constant.DocumentType], seedWeights[constant.PostType])
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 召回候选：画像相关及热门内容 + 协同过滤
+	candidates := make(map[uint64]*recommendCandidate)
+	if itemKind == constant.PostType {
+		posts, err := dao.GetRecommendCandidatePosts(topWeightedKeys(categoryWeights, 10), constant.RecommendCandidateLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, post := range posts {
+			candidates[post.ID] = &recommendCandidate{itemID: post.ID, post: post}
+		}
+	} else {
+		documents, err := dao.GetRecommendCandidateDocuments(itemType, topWeightedKeys(categoryWeights, 10),
+			topWeightedKeys(tagWeights, 20), constant.RecommendCandidateLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, document := range documents {
+			candidates[document.ID] = &recommendCandidate{itemID: document.ID, document: document}
+		}
+	}
+
+	cfScores, cfBestSeeds, err := itemBasedCFScores(userID, itemKind, seedWeights[itemKind], consumed)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadCFCandidates(itemType, itemKind, cfScores, candidates); err != nil {
+		return nil, err
+	}
+
+	// 4. 计算各项得分，排除已消费内容
+	var seedNames map[uint64]string
+	if len(cfScores) > 0 {
+		if seedNames, err = recommendSeedNames(itemKind, seedWeights[itemKind]); err != nil {
+			return nil, err
+		}
+	}
+	var postCategories map[uint64][]uint64
+	var documentTags map[uint64][]string
+	ids := make([]uint64, 0, len(candidates))
+	for id := range candidates {
+		ids = append(ids, id)
+	}
+	if itemKind == constant.PostType {
+		postCategories, err = dao.GetPostDocumentCategoryIDs(ids)
+	} else {
+		documentTags, err = dao.GetDocumentTagNamesByDocumentIDs(ids)
+	}
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[uint64]string)
+
+	list := make([]*recommendCandidate, 0, len(candidates))
+	for id, candidate := range candidates {
+		if consumed[id] {
+			continue
+		}
+		candidate.cf = cfScores[id]
+		candidate.cfSeed = cfBestSeeds[id]
+		if itemKind == constant.PostType {
+			candidate.popularity = math.Log1p(float64(2*candidate.post.LikeCount + 3*candidate.post.CollectCount + 2*candidate.post.CommentCount))
+			candidate.content, candidate.contentTip = scoreContent(postCategories[id], nil, categoryWeights, tagWeights, categoryNames)
+		} else {
+			candidate.popularity = math.Log1p(float64(candidate.document.ReadCounts + 3*candidate.document.Collections))
+			candidate.content, candidate.contentTip = scoreContent([]uint64{candidate.document.CategoryID}, documentTags[id],
+				categoryWeights, tagWeights, categoryNames)
+		}
+		list = append(list, candidate)
+	}
+
+	// 5. 各项得分归一化后加权混合
+	contentWeight, cfWeight, popularityWeight := recommendWeights()
+	var maxContent, maxCF, maxPopularity float64
+	for _, candidate := range list {
+		maxContent = max(maxContent, candidate.content)
+		maxCF = max(maxCF, candidate.cf)
+		maxPopularity = max(maxPopularity, candidate.popularity)
+	}
+	for _, candidate := range list {
+		candidate.content = normalizeScore(candidate.content, maxContent)
+		candidate.cf = normalizeScore(candidate.cf, maxCF)
+		candidate.popularity = normalizeScore(candidate.popularity, maxPopularity)
+		candidate.score = contentWeight*candidate.content + cfWeight*candidate.cf + popularityWeight*candidate.popularity
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].score != list[j].score {
+			return list[i].score > list[j].score
+		}
+		return list[i].itemID > list[j].itemID
+	})
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	results := make([]response.RecommendationResponse, 0, len(list))
+	for _, candidate := range list {
+		reason := recommendReason(candidate, itemKind, contentWeight, cfWeight, popularityWeight, seedNames, seedActions)
+		score := math.Round(candidate.score*1000) / 1000
+		if itemKind == constant.PostType {
+			results = append(results, response.BuildPostRecommendationResponse(candidate.post, score, reason))
+			continue
+		}
+		result, err := response.BuildDocumentRecommendationResponse(candidate.document, score, reason)
+		if err != nil {
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// buildInterestProfile 根据用户交互过的文档和帖子构建分类、标签兴趣画像（帖子取其关联文档的分类）
+func buildInterestProfile(documentSeeds, postSeeds map[uint64]float64) (map[uint64]float64, map[string]float64, error) {
+	categoryWeights := make(map[uint64]float64)
+	tagWeights := make(map[string]float64)
+
+	documentIDs := make([]uint64, 0, len(documentSeeds))
+	for id := range documentSeeds {
+		documentIDs = append(documentIDs, id)
+	}
+	documents, err := dao.GetDocumentsByIDsIgnoreStatus(documentIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	documentTags, err := dao.GetDocumentTagNamesByDocumentIDs(documentIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, document := range documents {
+		weight := documentSeeds[document.ID]
+		categoryWeights[document.CategoryID] += weight
+		for _, tag := range documentTags[document.ID] {
+			tagWeights[tag] += weight
+		}
+	}
+
+	postIDs := make([]uint64, 0, len(postSeeds))
+	for id := range postSeeds {
+		postIDs = append(postIDs, id)
+	}
+	postCategories, err := dao.GetPostDocumentCategoryIDs(postIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for postID, categoryIDs := range postCategories {
+		for _, categoryID := range categoryIDs {
+			categoryWeights[categoryID] += postSeeds[postID]
+		}
+	}
+	return categoryWeights, tagWeights, nil
+}
+
+// itemBasedCFScores 物品协同过滤：找出与用户交互过相同内容的其他用户，统计这些用户交互过的其他内容与种子的共现，
+// 相似度为共现用户数 / sqrt(两者各自的交互用户数之积)，候选得分为各种子权重与相似度乘积之和，同时记录贡献最大的种子
+func itemBasedCFScores(userID uint64, itemKind string, seeds map[uint64]float64, consumed map[uint64]bool) (map[uint64]float64, map[uint64]uint64, error) {
+	scores := make(map[uint64]float64)
+	bestSeeds := make(map[uint64]uint64)
+	if len(seeds) == 0 {
+		return scores, bestSeeds, nil
+	}
+
+	seedIDs := make([]uint64, 0, len(seeds))
+	for id := range seeds {
+		seedIDs = append(seedIDs, id)
+	}
+	neighborIDs, err := dao.GetCoInteractedUserIDs(itemKind, seedIDs, userID, constant.RecommendNeighborLimit)
+	if err != nil || len(neighborIDs) == 0 {
+		return scores, bestSeeds, err
+	}
+	neighborInteractions, err := dao.GetInteractionsByUserIDs(neighborIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userItems := make(map[uint64]map[uint64]bool)
+	for _, interaction := range neighborInteractions {
+		if interaction.ItemType != itemKind {
+			continue
+		}
+		if userItems[interaction.UserID] == nil {
+			userItems[interaction.UserID] = make(map[uint64]bool)
+		}
+		userItems[interaction.UserID][interaction.ItemID] = true
+	}
+
+	coCounts := make(map[uint64]map[uint64]float64) // 候选 -> 种子 -> 共现用户数
+	for _, items := range userItems {
+		for seedID := range items {
+			if _, ok := seeds[seedID]; !ok {
+				continue
+			}
+			for itemID := range items {
+				if consumed[itemID] {
+					continue
+				}
+				if coCounts[itemID] == nil {
+					coCounts[itemID] = make(map[uint64]float64)
+				}
+				coCounts[itemID][seedID]++
 			}
 		}
 	}
+	if len(coCounts) == 0 {
+		return scores, bestSeeds, nil
+	}
+
+	countIDs := slices.Clone(seedIDs)
+	for itemID := range coCounts {
+		countIDs = append(countIDs, itemID)
+	}
+	userCounts, err := dao.GetItemInteractedUserCounts(itemKind, countIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for itemID, seedCounts := range coCounts {
+		var best float64
+		for seedID, count := range seedCounts {
+			norm := math.Sqrt(float64(userCounts[seedID]) * float64(userCounts[itemID]))
+			if norm == 0 {
+				continue
+			}
+			contribution := seeds[seedID] * count / norm
+			scores[itemID] += contribution
+			if contribution > best {
+				best = contribution
+				bestSeeds[itemID] = seedID
+			}
+		}
+	}
+	return scores, bestSeeds, nil
+}
+
+// loadCFCandidates 将协同过滤得分最高的内容加入候选（只保留公开的、指定类型的文档或未隐藏的帖子）
+func loadCFCandidates(itemType, itemKind string, cfScores map[uint64]float64, candidates map[uint64]*recommendCandidate) error {
+	ids := make([]uint64, 0, len(cfScores))
+	for id := range cfScores {
+		if candidates[id] == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return cfScores[ids[i]] > cfScores[ids[j]] })
+	if len(ids) > constant.RecommendCandidateLimit {
+		ids = ids[:constant.RecommendCandidateLimit]
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if itemKind == constant.PostType {
+		posts, err := dao.GetVisiblePostsByIDs(ids)
+		if err != nil {
+			return err
+		}
+		for _, post := range posts {
+			candidates[post.ID] = &recommendCandidate{itemID: post.ID, post: post}
+		}
+		return nil
+	}
+
+	documentIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		documentIDs = append(documentIDs, int64(id))
+	}
+	documents, err := dao.GetDocumentsByIDs(documentIDs)
+	if err != nil {
+		return err
+	}
+	for _, document := range documents {
+		if document.Type == itemType {
+			candidates[document.ID] = &recommendCandidate{itemID: document.ID, document: document}
+		}
+	}
+	return nil
+}
+
+// recommendSeedNames 获取种子内容的名称，用于生成“因为你收藏了《X》”一类的推荐理由
+func recommendSeedNames(itemKind string, seeds map[uint64]float64) (map[uint64]string, error) {
+	ids := make([]uint64, 0, len(seeds))
+	for id := range seeds {
+		ids = append(ids, id)
+	}
+	names := make(map[uint64]string, len(ids))
+	if itemKind == constant.PostType {
+		posts, err := dao.GetVisiblePostsByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, post := range posts {
+			names[post.ID] = "「" + post.Title + "」"
+		}
+		return names, nil
+	}
+	documents, err := dao.GetDocumentsByIDsIgnoreStatus(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, document := range documents {
+		names[document.ID] = "《" + document.Name + "》"
+	}
+	return names, nil
+}
+
+// scoreContent 计算候选与兴趣画像的匹配得分，并给出贡献最大的分类或标签对应的推荐理由
+func scoreContent(categoryIDs []uint64, tags []string, categoryWeights map[uint64]float64, tagWeights map[string]float64,
+	categoryNames map[uint64]string) (float64, string) {
+	var score, best float64
+	tip := ""
+	for _, categoryID := range categoryIDs {
+		weight := categoryWeights[categoryID]
+		score += weight
+		if weight > best {
+			if _, ok := categoryNames[categoryID]; !ok {
+				category, _ := dao.GetCategoryByID(categoryID)
+				categoryNames[categoryID] = category.Name
+			}
+			if name := categoryNames[categoryID]; name != "" {
+				best = weight
+				tip = fmt.Sprintf("与你常看的「%s」分类相关", name)
+			}
+		}
+	}
+	for _, tag := range tags {
+		weight := tagWeights[tag]
+		score += weight
+		if weight > best {
+			best = weight
+			tip = fmt.Sprintf("与你关注的「%s」标签相关", tag)
+		}
+	}
+	return score, tip
+}
+
+// recommendReason 按加权后贡献最大的一项得分生成推荐理由
+func recommendReason(candidate *recommendCandidate, itemKind string, contentWeight, cfWeight, popularityWeight float64,
+	seedNames map[uint64]string, seedActions map[uint64]string) string {
+	cf := cfWeight * candidate.cf
+	content := contentWeight * candidate.content
+	if cf > 0 && cf >= content {
+		if name, ok := seedNames[candidate.cfSeed]; ok {
+			if verb, ok := interactionVerbs[seedActions[candidate.cfSeed]]; ok {
+				return fmt.Sprintf("因为你%s%s", verb, name)
+			}
+			return fmt.Sprintf("喜欢%s的用户也在看", name)
+		}
+	}
+	if content > 0 && candidate.contentTip != "" {
+		return candidate.contentTip
+	}
+	if itemKind == constant.PostType {
+		return "社区热门帖子"
+	}
+	return "热门资源"
+}
+
+// topWeightedKeys 按权重倒序取前 n 个键
+func topWeightedKeys[K comparable](weights map[K]float64, n int) []K {
+	keys := make([]K, 0, len(weights))
+	for key := range weights {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return weights[keys[i]] > weights[keys[j]] })
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+// normalizeScore 将得分按最大值归一化到 0~1
+func normalizeScore(score, maxScore float64) float64 {
+	if maxScore <= 0 {
+		return 0
+	}
+	return score / maxScore
+}
+
+// GetRecommendations 获取个性化推荐（书籍、文档、视频、帖子），每条结果附带推荐理由，优先返回预计算的缓存结果
+// GET /api/recommendations?type=book&limit=10
+func GetRecommendations(c *gin.Context) {
+	var req dto.GetRecommendationsDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = constant.DefaultRecommendLimit
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	results, err := getCachedRecommendations(userClaims.UserID, req.Type, func() ([]response.RecommendationResponse, error) {
+		return recommendForUser(userClaims.UserID, req.Type, constant.RecommendCacheSize)
+	})
+	if err != nil {
+		log.Printf("[Recommend] 用户 %d 的 %s 推荐生成失败: %v", userClaims.UserID, req.Type, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.GetRecommendationsFailed)
+		return
+	}
+	if len(results) > req.Limit {
+		results = results[:req.Limit]
+	}
 
-	response.SuccessWithData(c, respList, "获取推荐成功")
+	response.SuccessWithData(c, results, constant.GetRecommendationsSuccess)
 }
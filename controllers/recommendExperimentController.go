@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// assignBookRecommendVariant 获取用户在当前书籍推荐实验中分到的变体，没有进行中的实验时返回 nil
+func assignBookRecommendVariant(userID uint64) (*models.RecommendExperiment, utils.RecommendVariant) {
+	experiment, err := dao.GetRunningRecommendExperiment()
+	if err != nil {
+		log.Printf("[RecommendExperiment] 获取进行中的实验失败: %v", err)
+		return nil, utils.RecommendVariant{}
+	}
+	if experiment == nil {
+		return nil, utils.RecommendVariant{}
+	}
+	variants, err := utils.ParseRecommendVariants(experiment.Variants)
+	if err != nil {
+		log.Printf("[RecommendExperiment] 实验 %d 的变体解析失败: %v", experiment.ID, err)
+		return nil, utils.RecommendVariant{}
+	}
+	variant, ok := utils.AssignRecommendVariant(experiment.Name, userID, variants)
+	if !ok {
+		return nil, utils.RecommendVariant{}
+	}
+	return experiment, variant
+}
+
+// bookRecommendStrategy 获取用户当前使用的书籍推荐策略：参与实验时为所在变体的策略，否则为大模型重排
+func bookRecommendStrategy(userID uint64) string {
+	if experiment, variant := assignBookRecommendVariant(userID); experiment != nil {
+		return variant.Strategy
+	}
+	return constant.RecommendStrategyLLMRerank
+}
+
+// logRecommendImpressions 记录实验中推荐结果的曝光，应在 goroutine 中调用
+func logRecommendImpressions(experimentID uint64, variant string, userID uint64, documents []response.DocumentDetailResponse) {
+	impressions := make([]models.RecommendImpression, 0, len(documents))
+	for i, document := range documents {
+		impressions = append(impressions, models.RecommendImpression{
+			ExperimentID: experimentID,
+			Variant:      variant,
+			UserID:       userID,
+			ItemType:     constant.DocumentType,
+			ItemID:       document.InfoBrief.DocumentID,
+			Position:     i + 1,
+		})
+	}
+	if err := dao.CreateRecommendImpressions(impressions); err != nil {
+		log.Printf("[RecommendExperiment] 记录用户 %d 的推荐曝光失败: %v", userID, err)
+	}
+}
+
+// attributeRecommendClick 用户浏览内容后，将归因窗口内最近一次推荐曝光记为点击
+func attributeRecommendClick(userID uint64, itemType string, itemID uint64) {
+	if err := dao.MarkRecommendImpressionClicked(userID, itemType, itemID); err != nil {
+		log.Printf("[RecommendExperiment] 记录用户 %d 对 %s %d 的点击失败: %v", userID, itemType, itemID, err)
+	}
+}
+
+// attributeRecommendConversion 用户收藏内容后，将归因窗口内最近一次推荐曝光记为转化
+func attributeRecommendConversion(userID uint64, itemType string, itemID uint64) {
+	if err := dao.MarkRecommendImpressionFavorited(userID, itemType, itemID); err != nil {
+		log.Printf("[RecommendExperiment] 记录用户 %d 对 %s %d 的收藏失败: %v", userID, itemType, itemID, err)
+	}
+}
+
+// buildRecommendVariants 校验并转换变体列表，变体名称不能重复
+func buildRecommendVariants(variants []dto.RecommendVariantDTO) ([]utils.RecommendVariant, bool) {
+	results := make([]utils.RecommendVariant, 0, len(variants))
+	seen := make(map[string]bool)
+	for _, variant := range variants {
+		name := strings.TrimSpace(variant.Name)
+		if name == "" || seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		results = append(results, utils.RecommendVariant{Name: name, Strategy: variant.Strategy, Weight: variant.Weight})
+	}
+	return results, true
+}
+
+// parseRecommendExperimentID 解析路径中的实验 ID 并获取实验（失败时已写入响应）
+func parseRecommendExperimentID(c *gin.Context) (models.RecommendExperiment, bool) {
+	experimentID, err := strconv.ParseUint(c.Param("experimentId"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return models.RecommendExperiment{}, false
+	}
+	experiment, err := dao.GetRecommendExperimentByID(experimentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.RecommendExperimentNotExist)
+			return models.RecommendExperiment{}, false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return models.RecommendExperiment{}, false
+	}
+	return experiment, true
+}
+
+// saveRecommendExperimentFields 校验请求并写入实验的名称、说明和变体（失败时已写入响应）
+func saveRecommendExperimentFields(c *gin.Context, experiment *models.RecommendExperiment, req dto.SaveRecommendExperimentDTO) bool {
+	variants, ok := buildRecommendVariants(req.Variants)
+	if !ok {
+		response.Fail(c, http.StatusBadRequest, nil, constant.RecommendVariantNameDuplicate)
+		return false
+	}
+	name := strings.TrimSpace(req.Name)
+	exists, err := dao.RecommendExperimentNameExists(name, experiment.ID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return false
+	}
+	if exists {
+		response.Fail(c, http.StatusBadRequest, nil, constant.RecommendExperimentNameExists)
+		return false
+	}
+
+	variantsJSON, _ := json.Marshal(variants)
+	experiment.Name = name
+	experiment.Description = req.Description
+	experiment.Variants = string(variantsJSON)
+	return true
+}
+
+// AdminGetRecommendExperiments 管理员获取全部推荐实验
+// GET /api/admin/recommend-experiments
+func AdminGetRecommendExperiments(c *gin.Context) {
+	experiments, err := dao.GetRecommendExperiments()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	response.SuccessWithData(c, response.BuildRecommendExperimentResponses(experiments), constant.GetRecommendExperimentsSuccess)
+}
+
+// AdminCreateRecommendExperiment 管理员创建推荐实验（草稿状态，需手动开始）
+// POST /api/admin/recommend-experiments
+func AdminCreateRecommendExperiment(c *gin.Context) {
+	var req dto.SaveRecommendExperimentDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	experiment := models.RecommendExperiment{Status: constant.RecommendExperimentDraft}
+	if !saveRecommendExperimentFields(c, &experiment, req) {
+		return
+	}
+	if err := dao.CreateRecommendExperiment(&experiment); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.RecommendExperimentSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildRecommendExperimentResponse(experiment), constant.RecommendExperimentSaveSuccess)
+}
+
+// AdminUpdateRecommendExperiment 管理员修改推荐实验，只能修改尚未开始的实验（避免实验中途用户分组变化）
+// PUT /api/admin/recommend-experiments/:experimentId
+func AdminUpdateRecommendExperiment(c *gin.Context) {
+	var req dto.SaveRecommendExperimentDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	experiment, ok := parseRecommendExperimentID(c)
+	if !ok {
+		return
+	}
+	if experiment.Status != constant.RecommendExperimentDraft {
+		response.Fail(c, http.StatusBadRequest, nil, constant.RecommendExperimentNotEditable)
+		return
+	}
+	if !saveRecommendExperimentFields(c, &experiment, req) {
+		return
+	}
+	if err := dao.UpdateRecommendExperiment(&experiment); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.RecommendExperimentSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildRecommendExperimentResponse(experiment), constant.RecommendExperimentSaveSuccess)
+}
+
+// AdminUpdateRecommendExperimentStatus 管理员开始或结束推荐实验，同一时间只能有一个实验在进行
+// PUT /api/admin/recommend-experiments/:experimentId/status
+func AdminUpdateRecommendExperimentStatus(c *gin.Context) {
+	var req dto.UpdateRecommendExperimentStatusDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	experiment, ok := parseRecommendExperimentID(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	switch req.Status {
+	case constant.RecommendExperimentRunning:
+		if experiment.Status != constant.RecommendExperimentDraft {
+			response.Fail(c, http.StatusBadRequest, nil, constant.RecommendExperimentStatusInvalid)
+			return
+		}
+		running, err := dao.GetRunningRecommendExperiment()
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+			return
+		}
+		if running != nil {
+			response.Fail(c, http.StatusBadRequest, nil, constant.RecommendExperimentAlreadyRunning)
+			return
+		}
+		experiment.StartedAt = &now
+	case constant.RecommendExperimentStopped:
+		if experiment.Status != constant.RecommendExperimentRunning {
+			response.Fail(c, http.StatusBadRequest, nil, constant.RecommendExperimentStatusInvalid)
+			return
+		}
+		experiment.StoppedAt = &now
+	}
+	experiment.Status = req.Status
+
+	if err := dao.UpdateRecommendExperiment(&experiment); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.RecommendExperimentSaveFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildRecommendExperimentResponse(experiment), constant.RecommendExperimentSaveSuccess)
+}
+
+// AdminGetRecommendExperimentReport 管理员查看推荐实验报告：各变体的曝光、点击率和收藏转化率及 95% 置信区间
+// GET /api/admin/recommend-experiments/:experimentId/report
+func AdminGetRecommendExperimentReport(c *gin.Context) {
+	experiment, ok := parseRecommendExperimentID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := dao.GetRecommendVariantStats(experiment.ID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildRecommendExperimentReport(experiment, stats), constant.GetRecommendExperimentReportSuccess)
+}
@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// LoadSensitiveWordLexicon 从数据库加载敏感词库和各分类的处理方式，供敏感词过滤器初始化和热更新使用
+func LoadSensitiveWordLexicon() ([]utils.SensitiveWordEntry, map[string]string, error) {
+	words, err := dao.GetAllSensitiveWords()
+	if err != nil {
+		return nil, nil, err
+	}
+	categoryActions, err := getSensitiveCategoryActions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]utils.SensitiveWordEntry, 0, len(words))
+	for _, word := range words {
+		entries = append(entries, utils.SensitiveWordEntry{Word: word.Word, Category: word.Category})
+	}
+	return entries, categoryActions, nil
+}
+
+// getSensitiveCategoryActions 获取各敏感词分类的处理方式
+func getSensitiveCategoryActions() (map[string]string, error) {
+	categories, err := dao.GetSensitiveWordCategories()
+	if err != nil {
+		return nil, err
+	}
+	actions := make(map[string]string, len(categories))
+	for _, category := range categories {
+		actions[category.Name] = category.Action
+	}
+	return actions, nil
+}
+
+// notifySensitiveWordsChanged 词库变更后通知所有实例重新加载，通知失败时至少重新加载本实例
+func notifySensitiveWordsChanged() {
+	if err := utils.PublishSensitiveWordReload(); err != nil {
+		log.Printf("[SensitiveWord] 发布词库变更通知失败，仅重新加载本实例: %v", err)
+		if err := utils.ReloadSensitiveWords(LoadSensitiveWordLexicon); err != nil {
+			log.Printf("[SensitiveWord] 重新加载词库失败: %v", err)
+		}
+	}
+}
+
+// sensitiveMatchedWords 列出命中的敏感词（去重）
+func sensitiveMatchedWords(result utils.SensitiveCheckResult) string {
+	words := make([]string, 0, len(result.Matches))
+	seen := make(map[string]bool)
+	for _, match := range result.Matches {
+		if match.Action != constant.SensitiveActionFlag && !seen[match.Word] {
+			seen[match.Word] = true
+			words = append(words, match.Word)
+		}
+	}
+	return strings.Join(words, "、")
+}
+
+// filterSensitiveText 对用户提交的文本进行敏感词过滤：命中 reject 类时拒绝提交（已写入响应），
+// 命中 mask 类时返回打码后的文本，flag 类原样放行，由内容审核流程进入人工复核
+func filterSensitiveText(c *gin.Context, text string) (string, bool) {
+	result := utils.CheckSensitiveText(text)
+	switch result.Action {
+	case constant.SensitiveActionReject:
+		response.Fail(c, http.StatusBadRequest, nil, constant.SensitiveWordRejected+sensitiveMatchedWords(result))
+		return "", false
+	case constant.SensitiveActionMask:
+		return result.Masked, true
+	}
+	return text, true
+}
+
+// maskSensitiveText 不直接写入响应的敏感词过滤（用于批量导入、目录服务返回的文本等）：
+// 命中 reject 类时返回错误，命中 mask 类时返回打码后的文本
+func maskSensitiveText(text string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	result := utils.CheckSensitiveText(text)
+	switch result.Action {
+	case constant.SensitiveActionReject:
+		return "", errors.New(constant.SensitiveWordRejected + sensitiveMatchedWords(result))
+	case constant.SensitiveActionMask:
+		return result.Masked, nil
+	}
+	return text, nil
+}
+
+// rejectSensitiveText 用于用户名等不适合打码的文本：命中 mask 或 reject 类敏感词时拒绝提交（已写入响应）
+func rejectSensitiveText(c *gin.Context, text string) bool {
+	result := utils.CheckSensitiveText(text)
+	if result.Action == constant.SensitiveActionReject || result.Action == constant.SensitiveActionMask {
+		response.Fail(c, http.StatusBadRequest, nil, constant.SensitiveWordRejected+sensitiveMatchedWords(result))
+		return true
+	}
+	return false
+}
+
+// AdminGetSensitiveWords 管理员查询敏感词
+// GET /api/admin/sensitive-words?category=abuse&keyword=xx
+func AdminGetSensitiveWords(c *gin.Context) {
+	var req dto.GetSensitiveWordsDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	words, err := dao.SearchSensitiveWords(req.Category, utils.NormalizeSensitiveWord(req.Keyword))
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	categoryActions, err := getSensitiveCategoryActions()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildSensitiveWordResponses(words, categoryActions), constant.GetSensitiveWordsSuccess)
+}
+
+// AdminCreateSensitiveWords 管理员批量添加敏感词（已存在的词改为新的分类），添加后各实例热更新词库
+// POST /api/admin/sensitive-words
+func AdminCreateSensitiveWords(c *gin.Context) {
+	var req dto.CreateSensitiveWordsDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	// 规范化后去重，去掉空白和标点后为空的词忽略
+	words := make([]string, 0, len(req.Words))
+	seen := make(map[string]bool)
+	for _, word := range req.Words {
+		word = utils.NormalizeSensitiveWord(word)
+		if word == "" || seen[word] || len([]rune(word)) > 100 {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	if len(words) == 0 {
+		response.Fail(c, http.StatusBadRequest, nil, constant.SensitiveWordEmpty)
+		return
+	}
+
+	created, err := dao.CreateSensitiveWords(words, strings.TrimSpace(req.Category), userClaims.UserID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.SensitiveWordSaveFailed)
+		return
+	}
+	notifySensitiveWordsChanged()
+
+	response.SuccessWithData(c, gin.H{"created": created, "updated": len(words) - created}, constant.SensitiveWordSaveSuccess)
+}
+
+// AdminDeleteSensitiveWord 管理员删除敏感词，删除后各实例热更新词库
+// DELETE /api/admin/sensitive-words/:wordId
+func AdminDeleteSensitiveWord(c *gin.Context) {
+	wordID, err := strconv.ParseUint(c.Param("wordId"), 10, 64)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	deleted, err := dao.DeleteSensitiveWord(wordID)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.SensitiveWordDeleteFailed)
+		return
+	}
+	if deleted == 0 {
+		response.Fail(c, http.StatusNotFound, nil, constant.SensitiveWordNotExist)
+		return
+	}
+	notifySensitiveWordsChanged()
+
+	response.Success(c, nil, constant.SensitiveWordDeleteSuccess)
+}
+
+// AdminGetSensitiveWordCategories 管理员获取敏感词分类及其处理方式
+// GET /api/admin/sensitive-word-categories
+func AdminGetSensitiveWordCategories(c *gin.Context) {
+	categories, err := dao.GetSensitiveWordCategories()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return
+	}
+	response.SuccessWithData(c, categories, constant.GetSensitiveWordsSuccess)
+}
+
+// AdminSaveSensitiveWordCategory 管理员创建或修改敏感词分类的处理方式，修改后各实例热更新词库
+// PUT /api/admin/sensitive-word-categories
+func AdminSaveSensitiveWordCategory(c *gin.Context) {
+	var req dto.SaveSensitiveWordCategoryDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	category := models.SensitiveWordCategory{
+		Name:        strings.TrimSpace(req.Name),
+		Action:      req.Action,
+		Description: req.Description,
+	}
+	if err := dao.SaveSensitiveWordCategory(&category); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.SensitiveWordSaveFailed)
+		return
+	}
+	notifySensitiveWordsChanged()
+
+	response.SuccessWithData(c, category, constant.SensitiveWordSaveSuccess)
+}
+
+// AdminTestSensitiveText 管理员测试一段文本命中的敏感词、处理方式和打码结果
+// POST /api/admin/sensitive-words/test
+func AdminTestSensitiveText(c *gin.Context) {
+	var req dto.TestSensitiveTextDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+
+	response.SuccessWithData(c, utils.CheckSensitiveText(req.Text), constant.TestSensitiveTextSuccess)
+}
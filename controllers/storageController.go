@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ServeLocalBlob 使用本地存储驱动时提供文件访问，支持 Range 请求；带签名参数时校验签名和有效期，文档主文件必须带签名
+// GET /storage/*key
+func ServeLocalBlob(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	file, err := utils.OpenLocalBlob(key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if errors.Is(err, utils.ErrBlobSignatureInvalid) {
+			response.Fail(c, http.StatusForbidden, nil, constant.StorageSignatureInvalid)
+			return
+		}
+		response.Fail(c, http.StatusNotFound, nil, constant.StorageFileNotExist)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.StorageFileNotExist)
+		return
+	}
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime(), file)
+}
+
+// ReceiveLocalBlob 使用本地存储驱动时接收直传的文件，必须使用 /api/uploads/presign 返回的签名上传地址
+// PUT /storage/*key
+func ReceiveLocalBlob(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, constant.MaxFileSize)
+	err := utils.PutLocalBlob(key, c.Query("expires"), c.Query("signature"), body, c.Request.ContentLength, c.ContentType())
+	if err != nil {
+		if errors.Is(err, utils.ErrBlobSignatureInvalid) {
+			response.Fail(c, http.StatusForbidden, nil, constant.StorageSignatureInvalid)
+			return
+		}
+		if errors.Is(err, utils.ErrBlobNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.StorageFileNotExist)
+			return
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Fail(c, http.StatusRequestEntityTooLarge, nil, constant.UploadedObjectTooLarge)
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentUploadFailed)
+		return
+	}
+	response.Success(c, nil, constant.UploadCompleteSuccess)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/antidote-kt/SSE_Library-back/config"
 	"github.com/antidote-kt/SSE_Library-back/constant"
@@ -24,9 +25,6 @@ import (
 // 3. 将文档信息保存到数据库（使用事务）
 // 4. 返回上传结果
 func UploadDocument(c *gin.Context) {
-	// 获取数据库连接
-	db := config.GetDB()
-
 	// 初始化上传请求结构体
 	var req dto.UploadDTO
 
@@ -37,6 +35,39 @@ func UploadDocument(c *gin.Context) {
 		return
 	}
 
+	// 校验 ISBN 并统一为 ISBN-13，书籍根据 ISBN 查询书籍信息，用于补全上传者留空的字段
+	var bookMetadata *utils.BookMetadata
+	if req.ISBN != nil && strings.TrimSpace(*req.ISBN) != "" {
+		isbn, ok := utils.NormalizeISBN(*req.ISBN)
+		if !ok {
+			response.Fail(c, http.StatusBadRequest, nil, constant.InvalidISBN)
+			return
+		}
+		req.ISBN = &isbn
+		if req.Type == constant.BookType {
+			// 查询不到或目录服务不可用时不影响上传
+			bookMetadata, _ = lookupBookMetadata(isbn)
+		}
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" && (bookMetadata == nil || bookMetadata.Title == "") {
+		response.Fail(c, http.StatusBadRequest, nil, constant.DocumentNameRequired)
+		return
+	}
+
+	// 对名称和简介进行敏感词过滤
+	var ok bool
+	if req.Name, ok = filterSensitiveText(c, req.Name); !ok {
+		return
+	}
+	if req.Introduction != nil {
+		introduction, ok := filterSensitiveText(c, *req.Introduction)
+		if !ok {
+			return
+		}
+		req.Introduction = &introduction
+	}
+
 	// 查询文档分类信息
 	category, err := dao.GetCategoryByID(req.CategoryID)
 	if err != nil {
@@ -51,9 +82,36 @@ func UploadDocument(c *gin.Context) {
 
 	// 用于存储文件URL的变量
 	var fileURL string
+	// 文件大小和哈希，记录到文档的第一个版本
+	var fileSize int64
+	var fileHash string
+
+	// 上传前按文件内容校验主文件和封面，不合格的文件不会写入存储
+	// 按文件内容检测出的类型，记录到文档的文件元数据
+	var mimeType string
+	if req.File != nil {
+		checked, err := utils.ValidateUploadFile(req.File, req.Type)
+		if err != nil {
+			failUploadCheck(c, err)
+			return
+		}
+		mimeType = checked.MIME
+	}
+	if req.Cover != nil {
+		if _, err := utils.ValidateCoverImage(req.Cover); err != nil {
+			failUploadCheck(c, err)
+			return
+		}
+	}
 
 	// 2. 上传主文件（如果有）
 	if req.File != nil {
+		fileSize = req.File.Size
+		fileHash, err = utils.HashUploadedFile(req.File)
+		if err != nil {
+			response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentOpenFailed)
+			return
+		}
 		// 使用工具函数上传主文件
 		fileURL, err = utils.UploadMainFile(req.File, category.Name)
 		if err != nil {
@@ -61,6 +119,13 @@ func UploadDocument(c *gin.Context) {
 			response.Fail(c, http.StatusInternalServerError, nil, err.Error())
 			return
 		}
+	} else if req.ObjectKey != nil {
+		// 分片上传或直传完成的文件已在存储中，校验归属和文件内容
+		var ok bool
+		fileURL, fileSize, fileHash, mimeType, ok = resolveUploadedObject(c, *req.ObjectKey, req.Type)
+		if !ok {
+			return
+		}
 	} else if req.VideoURL != nil {
 		fileURL = *req.VideoURL
 	}
@@ -115,9 +180,6 @@ func UploadDocument(c *gin.Context) {
 
 	if req.Author != nil {
 		document.Author = *req.Author
-	} else {
-		// 如果没有提供作者，默认使用常量中的默认作者
-		document.Author = constant.DefaultAuthor
 	}
 
 	if req.Introduction != nil {
@@ -128,16 +190,22 @@ func UploadDocument(c *gin.Context) {
 		document.CreateYear = *req.CreateYear
 	}
 
+	if req.Publisher != nil {
+		document.Publisher = *req.Publisher
+	}
+
+	// 上传者留空的字段用 ISBN 查询到的书籍信息补全，封面在下面生成封面时从目录服务下载
+	applyBookMetadata(&document, bookMetadata)
+	if document.Author == "" {
+		// 如果没有提供作者，默认使用常量中的默认作者
+		document.Author = constant.DefaultAuthor
+	}
+
+	// 文件大小、哈希和类型在上传时记录，页数、字数、语言等在学习文档时提取
+	resetDocumentFileMetadata(&document, fileSize, fileHash, mimeType)
+
 	// 使用数据库事务创建文档
-	err = db.Transaction(func(tx *gorm.DB) error {
-		// 使用事务创建文档记录
-		document, err = dao.CreateDocumentWithTx(tx, document, req.Tags)
-		if err != nil {
-			return err
-		}
-		// 如果没有返回错误，事务将自动提交
-		return nil
-	})
+	document, err = createDocumentWithFirstVersion(document, req.Tags, fileSize, fileHash)
 
 	// 检查事务执行结果
 	if err != nil {
@@ -146,23 +214,31 @@ func UploadDocument(c *gin.Context) {
 		return
 	}
 
+	// 上传的文件已用于创建文档，不能再次提交
+	if req.File == nil && req.ObjectKey != nil {
+		if err := utils.RemoveUploadedObject(fileURL); err != nil {
+			log.Printf("[Upload] 清理已提交的上传记录 %s 失败: %v", fileURL, err)
+		}
+	}
+
 	// 文档存入rag知识库学习
 	ComFileurl := utils.GetFileURL(fileURL)
 	log.Printf("文档网址: %s", ComFileurl)
 	LearnDocument(int(document.ID), ComFileurl)
 
+	// 视频没有可提取的正文，只对名称和简介进行内容预审
+	if document.Type == constant.VideoType {
+		go moderateDocument(document.ID, "")
+	}
+
+	// 没有上传封面时自动生成（有 ISBN 的书籍优先使用目录服务的封面，PDF 渲染首页，其他类型生成文字封面）
+	if document.Cover == "" {
+		go generateDocumentCover(document, category.Name)
+	}
+
 	// 书籍元数据向量化存入 Milvus (用于推荐)
 	if document.Type == "book" {
-		go func() {
-			text := fmt.Sprintf("%s %s %s", document.Name, category.Name, document.Introduction)
-			vectors, err := utils.GetEmbeddings([]string{text})
-			if err == nil && len(vectors) > 0 {
-				utils.InsertBookVector(int64(document.ID), text, vectors[0])
-				log.Printf("书籍 %d 向量化完成", document.ID)
-			} else {
-				log.Printf("书籍向量化失败: %v", err)
-			}
-		}()
+		go indexBookVector(document, category.Name)
 	}
 
 	responseData := gin.H{
@@ -173,6 +249,43 @@ func UploadDocument(c *gin.Context) {
 	response.Success(c, responseData, constant.DocumentCreateSuccess)
 }
 
+// createDocumentWithFirstVersion 在事务中创建文档及其标签，并记录文档的第一个版本
+func createDocumentWithFirstVersion(document models.Document, tags []string, fileSize int64, fileHash string) (models.Document, error) {
+	db := config.GetDB()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 使用事务创建文档记录
+		var err error
+		document, err = dao.CreateDocumentWithTx(tx, document, tags)
+		if err != nil {
+			return err
+		}
+		// 记录文档的第一个版本
+		return dao.CreateDocumentVersionWithTx(tx, &models.DocumentVersion{
+			DocumentID: document.ID,
+			Type:       document.Type,
+			URL:        document.URL,
+			Cover:      document.Cover,
+			FileSize:   fileSize,
+			FileHash:   fileHash,
+			UploaderID: document.UploaderID,
+			ChangeNote: constant.DocumentVersionInitialNote,
+		})
+	})
+	return document, err
+}
+
+// indexBookVector 书籍名称、分类和简介向量化存入 Milvus（用于推荐）
+func indexBookVector(document models.Document, categoryName string) {
+	text := fmt.Sprintf("%s %s %s", document.Name, categoryName, document.Introduction)
+	vectors, err := utils.GetEmbeddings([]string{text})
+	if err == nil && len(vectors) > 0 {
+		utils.InsertBookVector(int64(document.ID), text, vectors[0])
+		log.Printf("书籍 %d 向量化完成", document.ID)
+	} else {
+		log.Printf("书籍向量化失败: %v", err)
+	}
+}
+
 // validateFileSize 验证文件大小是否符合要求
 // 参数: size: 文件大小（字节）
 // 返回值: bool: 是否符合要求
@@ -202,3 +315,16 @@ func bindAndValidateRequest(c *gin.Context, req *dto.UploadDTO) error {
 
 	return nil
 }
+
+// failUploadCheck 上传文件未通过校验时写入响应：文件不合格返回 400，扫描服务不可用返回 503
+func failUploadCheck(c *gin.Context, err error) {
+	switch {
+	case utils.IsUploadRejected(err):
+		response.Fail(c, http.StatusBadRequest, nil, err.Error())
+	case errors.Is(err, utils.ErrScanUnavailable):
+		log.Printf("文件安全扫描失败: %v", err)
+		response.Fail(c, http.StatusServiceUnavailable, nil, constant.UploadScanUnavailable)
+	default:
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentOpenFailed)
+	}
+}
@@ -0,0 +1,349 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/dto"
+	"github.com/antidote-kt/SSE_Library-back/response"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// uploadCategoryName 获取上传文件所属分类的名称，用于生成存储路径（失败时已写入响应）
+func uploadCategoryName(c *gin.Context, categoryID uint64) (string, bool) {
+	category, err := dao.GetCategoryByID(categoryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.CategoryNotExist)
+			return "", false
+		}
+		response.Fail(c, http.StatusInternalServerError, nil, constant.DatabaseError)
+		return "", false
+	}
+	return category.Name, true
+}
+
+// getOwnUploadSession 获取路径中的上传会话，只能操作自己创建的会话（失败时已写入响应）
+func getOwnUploadSession(c *gin.Context) (*utils.UploadSession, bool) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return nil, false
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	session, err := utils.GetUploadSession(c.Param("uploadId"))
+	if err != nil {
+		log.Printf("[Upload] 读取上传会话 %s 失败: %v", c.Param("uploadId"), err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.GetUploadSessionFailed)
+		return nil, false
+	}
+	if session == nil || session.UserID != userClaims.UserID {
+		response.Fail(c, http.StatusNotFound, nil, constant.UploadSessionNotExist)
+		return nil, false
+	}
+	return session, true
+}
+
+// resolveUploadedObject 校验分片上传或直传完成的文件属于当前用户、未超过大小限制且内容符合 docType 的要求，
+// 校验通过后按检测出的扩展名和类型重新保存，返回新的存储路径、文件大小、SHA-256 和类型（失败时已写入响应）
+func resolveUploadedObject(c *gin.Context, objectKey string, docType string) (string, int64, string, string, bool) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return "", 0, "", "", false
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	object, err := utils.GetUploadedObject(objectKey)
+	if err != nil {
+		log.Printf("[Upload] 读取上传文件 %s 的记录失败: %v", objectKey, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadedObjectCheckFailed)
+		return "", 0, "", "", false
+	}
+	if object == nil || object.UserID != userClaims.UserID {
+		response.Fail(c, http.StatusNotFound, nil, constant.UploadedObjectNotExist)
+		return "", 0, "", "", false
+	}
+
+	info, err := utils.StatBlob(objectKey)
+	if err != nil {
+		if errors.Is(err, utils.ErrBlobNotFound) {
+			response.Fail(c, http.StatusNotFound, nil, constant.UploadedObjectNotExist)
+			return "", 0, "", "", false
+		}
+		log.Printf("[Upload] 读取上传文件 %s 的信息失败: %v", objectKey, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadedObjectCheckFailed)
+		return "", 0, "", "", false
+	}
+	// 直传时存储无法限制文件大小，提交时再检查，超限的文件直接删除
+	if !validateFileSize(info.Size) {
+		_ = utils.DeleteFile(objectKey)
+		_ = utils.RemoveUploadedObject(objectKey)
+		response.Fail(c, http.StatusBadRequest, nil, constant.UploadedObjectTooLarge)
+		return "", 0, "", "", false
+	}
+	// 文件内容不合格时同样删除，扫描服务暂不可用时保留以便重试
+	checked, err := utils.ValidateStoredFile(objectKey, docType)
+	if err != nil {
+		if utils.IsUploadRejected(err) {
+			_ = utils.DeleteFile(objectKey)
+			_ = utils.RemoveUploadedObject(objectKey)
+		}
+		failUploadCheck(c, err)
+		return "", 0, "", "", false
+	}
+
+	fileHash := object.SHA256
+	if fileHash == "" {
+		fileHash, err = utils.HashBlob(objectKey)
+		if err != nil {
+			log.Printf("[Upload] 计算上传文件 %s 的校验和失败: %v", objectKey, err)
+			response.Fail(c, http.StatusInternalServerError, nil, constant.UploadedObjectCheckFailed)
+			return "", 0, "", "", false
+		}
+	}
+
+	// 存储路径的扩展名和保存的类型都来自客户端，改为按内容检测出的结果
+	newKey, err := utils.RelocateUploadedObject(objectKey, utils.UploadedObject{UserID: object.UserID, SHA256: fileHash}, info.Size, checked)
+	if err != nil {
+		log.Printf("[Upload] 重命名上传文件 %s 失败: %v", objectKey, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadedObjectCheckFailed)
+		return "", 0, "", "", false
+	}
+	return newKey, info.Size, fileHash, checked.MIME, true
+}
+
+// InitUploadSession 创建分片上传会话，大文件分片上传，中断后可查询进度继续上传
+// POST /api/uploads
+func InitUploadSession(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.InitUploadSessionDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if !validateFileSize(req.FileSize) {
+		response.Fail(c, http.StatusBadRequest, nil, fmt.Sprintf("文件大小不能超过%vMB", constant.MaxFileSize/1024/1024))
+		return
+	}
+	partSize := req.PartSize
+	if partSize == 0 {
+		partSize = constant.DefaultUploadPartSize
+	}
+	if partSize < constant.MinUploadPartSize || partSize > constant.MaxUploadPartSize {
+		response.Fail(c, http.StatusBadRequest, nil, fmt.Sprintf("分片大小必须在%dMB到%dMB之间",
+			constant.MinUploadPartSize>>20, constant.MaxUploadPartSize>>20))
+		return
+	}
+	categoryName, ok := uploadCategoryName(c, req.CategoryID)
+	if !ok {
+		return
+	}
+
+	session := utils.UploadSession{
+		ID:          utils.NewUploadID(),
+		UserID:      userClaims.UserID,
+		FileName:    req.FileName,
+		FileSize:    req.FileSize,
+		PartSize:    partSize,
+		TotalParts:  int((req.FileSize + partSize - 1) / partSize),
+		ContentType: req.ContentType,
+		ObjectKey:   utils.NewMainFileKey(req.FileName, categoryName),
+		SHA256:      strings.ToLower(req.SHA256),
+		CreatedAt:   time.Now().Unix(),
+	}
+	if err := utils.SaveUploadSession(&session); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadSessionCreateFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildUploadSessionResponse(&session, nil), constant.UploadSessionCreateSuccess)
+}
+
+// GetUploadSession 查询分片上传进度（已上传的分片），用于断点续传
+// GET /api/uploads/:uploadId
+func GetUploadSession(c *gin.Context) {
+	session, ok := getOwnUploadSession(c)
+	if !ok {
+		return
+	}
+	parts, err := utils.GetUploadedParts(session.ID)
+	if err != nil {
+		log.Printf("[Upload] 读取会话 %s 的分片失败: %v", session.ID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.GetUploadSessionFailed)
+		return
+	}
+
+	response.SuccessWithData(c, response.BuildUploadSessionResponse(session, parts), constant.GetUploadSessionSuccess)
+}
+
+// UploadSessionPart 上传一个分片，请求体为分片的原始字节，请求头 X-Part-SHA256 为分片的 SHA-256
+// PUT /api/uploads/:uploadId/parts/:partNumber
+func UploadSessionPart(c *gin.Context) {
+	session, ok := getOwnUploadSession(c)
+	if !ok {
+		return
+	}
+	partNumber, err := strconv.Atoi(c.Param("partNumber"))
+	if err != nil || partNumber < 1 || partNumber > session.TotalParts {
+		response.Fail(c, http.StatusBadRequest, nil, constant.UploadPartNumberInvalid)
+		return
+	}
+
+	// 多读一个字节用于判断分片是否超出大小
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, session.PartSize+1))
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.UploadPartFailed)
+		return
+	}
+	if err := utils.UploadPart(session, partNumber, data, strings.ToLower(c.GetHeader("X-Part-SHA256"))); err != nil {
+		switch err.Error() {
+		case constant.UploadPartSizeMismatch:
+			response.Fail(c, http.StatusBadRequest, nil, constant.UploadPartSizeMismatch)
+			return
+		case constant.UploadPartChecksumMismatch:
+			response.Fail(c, http.StatusBadRequest, nil, constant.UploadPartChecksumMismatch)
+			return
+		}
+		log.Printf("[Upload] 会话 %s 上传分片 %d 失败: %v", session.ID, partNumber, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadPartFailed)
+		return
+	}
+
+	parts, err := utils.GetUploadedParts(session.ID)
+	if err != nil {
+		log.Printf("[Upload] 读取会话 %s 的分片失败: %v", session.ID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.GetUploadSessionFailed)
+		return
+	}
+	response.SuccessWithData(c, response.BuildUploadSessionResponse(session, parts), constant.UploadPartSuccess)
+}
+
+// CompleteUploadSession 全部分片上传后合并为完整文件，返回的 objectKey 用于提交文档（POST /api/user/document）
+// POST /api/uploads/:uploadId/complete
+func CompleteUploadSession(c *gin.Context) {
+	session, ok := getOwnUploadSession(c)
+	if !ok {
+		return
+	}
+	parts, err := utils.GetUploadedParts(session.ID)
+	if err != nil {
+		log.Printf("[Upload] 读取会话 %s 的分片失败: %v", session.ID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.GetUploadSessionFailed)
+		return
+	}
+	if len(parts) < session.TotalParts {
+		response.Fail(c, http.StatusBadRequest, gin.H{"session": response.BuildUploadSessionResponse(session, parts)}, constant.UploadPartsIncomplete)
+		return
+	}
+
+	fileHash, err := utils.CompleteUpload(session)
+	if err != nil {
+		if err.Error() == constant.UploadFileChecksumMismatch {
+			_ = utils.RemoveUploadSession(session)
+			response.Fail(c, http.StatusBadRequest, nil, constant.UploadFileChecksumMismatch)
+			return
+		}
+		log.Printf("[Upload] 会话 %s 合并分片失败: %v", session.ID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadCompleteFailed)
+		return
+	}
+	if err := utils.SaveUploadedObject(session.ObjectKey, utils.UploadedObject{UserID: session.UserID, SHA256: fileHash}); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadCompleteFailed)
+		return
+	}
+	if err := utils.RemoveUploadSession(session); err != nil {
+		log.Printf("[Upload] 清理会话 %s 失败: %v", session.ID, err)
+	}
+
+	responseData := gin.H{
+		"objectKey": session.ObjectKey,
+		"fileSize":  session.FileSize,
+		"sha256":    fileHash,
+	}
+	response.SuccessWithData(c, responseData, constant.UploadCompleteSuccess)
+}
+
+// AbortUploadSession 取消分片上传，删除已上传的分片
+// DELETE /api/uploads/:uploadId
+func AbortUploadSession(c *gin.Context) {
+	session, ok := getOwnUploadSession(c)
+	if !ok {
+		return
+	}
+	if err := utils.RemoveUploadSession(session); err != nil {
+		log.Printf("[Upload] 取消会话 %s 失败: %v", session.ID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadAbortFailed)
+		return
+	}
+	response.Success(c, nil, constant.UploadAbortSuccess)
+}
+
+// PresignUpload 获取直传到存储的签名地址，客户端用 PUT 方法上传后，以返回的 objectKey 提交文档
+// POST /api/uploads/presign
+func PresignUpload(c *gin.Context) {
+	claims, exists := c.Get(constant.UserClaims)
+	if !exists {
+		response.Fail(c, http.StatusUnauthorized, nil, constant.GetUserInfoFailed)
+		return
+	}
+	userClaims := claims.(*utils.MyClaims)
+
+	var req dto.PresignUploadDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, http.StatusBadRequest, nil, constant.ParamParseError)
+		return
+	}
+	if !validateFileSize(req.FileSize) {
+		response.Fail(c, http.StatusBadRequest, nil, fmt.Sprintf("文件大小不能超过%vMB", constant.MaxFileSize/1024/1024))
+		return
+	}
+	categoryName, ok := uploadCategoryName(c, req.CategoryID)
+	if !ok {
+		return
+	}
+
+	store, err := utils.GetBlobStore()
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadPresignFailed)
+		return
+	}
+	objectKey := utils.NewMainFileKey(req.FileName, categoryName)
+	expire := constant.UploadPresignExpireMinutes * time.Minute
+	uploadURL, err := store.SignedUploadURL(context.Background(), objectKey, expire)
+	if err != nil {
+		log.Printf("[Upload] 生成直传地址失败: %v", err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadPresignFailed)
+		return
+	}
+	if err := utils.SaveUploadedObject(objectKey, utils.UploadedObject{UserID: userClaims.UserID}); err != nil {
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadPresignFailed)
+		return
+	}
+
+	responseData := gin.H{
+		"objectKey":  objectKey,
+		"uploadUrl":  uploadURL,
+		"method":     http.MethodPut,
+		"expireTime": time.Now().Add(expire).Format("2006-01-02 15:04:05"),
+	}
+	response.SuccessWithData(c, responseData, constant.UploadPresignSuccess)
+}
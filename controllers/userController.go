@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"errors"
+	"log"
 	"net/http"
 
 	"github.com/antidote-kt/SSE_Library-back/constant"
@@ -41,10 +42,19 @@ func RegisterUser(c *gin.Context) {
 
 	// 如果代码能执行到这里，说明 err 恰好是 gorm.ErrRecordNotFound，意味着用户名可用
 	var avatarURL string
-	// 3. 上传用户头像（uploaderAvatar会检查是否为空）
+	// 3. 校验头像（只允许 JPEG、PNG、WebP 并做恶意文件扫描）后上传
+	if req.Avatar == nil || req.Avatar.Size == 0 {
+		response.Fail(c, http.StatusBadRequest, nil, constant.NonUserAvatar)
+		return
+	}
+	if _, err := utils.ValidateAvatarImage(req.Avatar); err != nil {
+		failUploadCheck(c, err)
+		return
+	}
 	avatarURL, err = utils.UploadAvatar(req.Avatar)
 	if err != nil {
-		response.Fail(c, http.StatusInternalServerError, nil, err.Error())
+		log.Printf("[Avatar] 上传头像失败: %v", err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.UploadAvatarFailed)
 		return
 	}
 
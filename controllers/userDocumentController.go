@@ -75,6 +75,7 @@ func WithdrawUpload(c *gin.Context) {
 	//}
 
 	// 更新文档状态为撤回状态
+	wasOpen := document.Status == constant.DocumentStatusOpen
 	document.Status = constant.DocumentStatusWithdrawn
 
 	// 查找对应分类（验证分类是否存在）
@@ -98,6 +99,10 @@ func WithdrawUpload(c *gin.Context) {
 		response.Fail(c, http.StatusInternalServerError, nil, constant.DocumentUpdateFail)
 		return
 	}
+	// 撤回开放的文档后，所有用户的推荐需要重新计算
+	if wasOpen {
+		markRecommendationsStale()
+	}
 
 	// 返回撤回上传成功消息
 	response.Success(c, nil, constant.WithdrawUploadSuccessMsg)
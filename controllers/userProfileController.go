@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 
@@ -35,10 +36,10 @@ func GetProfile(c *gin.Context) {
 		return
 	}
 
-	// 2. 获取用户基本信息、收藏列表、历史记录
+	// 2. 获取用户基本信息、收藏列表、继续阅读列表
 	var userInfo models.User
 	var collectionList []models.Document
-	var historyList []models.Document
+	var continueReading []dao.ContinueReadingItem
 
 	userInfo, err = dao.GetUserByID(userID)
 	if err != nil {
@@ -48,18 +49,20 @@ func GetProfile(c *gin.Context) {
 
 	collectionList, err = dao.GetFavoriteDocumentsByUserID(userID)
 	if err != nil {
-		response.Fail(c, http.StatusInternalServerError, nil, constant.GetDataFailed+err.Error())
+		log.Printf("[Profile] 获取用户 %d 的收藏失败: %v", userID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.GetDataFailed)
 		return
 	}
 
-	historyList, err = dao.GetViewHistoryDocumentsByUserID(userID)
+	continueReading, _, err = dao.GetContinueReading(userID, 1, constant.HomepageContinueLimit)
 	if err != nil {
-		response.Fail(c, http.StatusInternalServerError, nil, constant.GetDataFailed+err.Error())
+		log.Printf("[Profile] 获取用户 %d 的继续阅读失败: %v", userID, err)
+		response.Fail(c, http.StatusInternalServerError, nil, constant.GetDataFailed)
 		return
 	}
 
 	// 3. 调用response层组装返回数据
-	homepageResponse, err := response.BuildHomepageResponse(userInfo, collectionList, historyList)
+	homepageResponse, err := response.BuildHomepageResponse(userInfo, collectionList, continueReading)
 	if err != nil {
 		response.Fail(c, http.StatusInternalServerError, nil, constant.ConstructDataFailed)
 		return
@@ -98,6 +101,10 @@ func ModifyInfo(c *gin.Context) {
 	// 使用指针的好处：如果前端没传某个字段，这里的指针就是nil，我们就不更新它
 	updated := false
 	if req.UserName != nil && *req.UserName != user.Username {
+		// 用户名不适合打码，命中敏感词直接拒绝
+		if rejectSensitiveText(c, *req.UserName) {
+			return
+		}
 		// 检查新用户名是否已被其他用户占用
 		existingUser, _ := dao.GetUserByUsername(*req.UserName)
 		if existingUser.ID != 0 && existingUser.ID != user.ID {
@@ -118,8 +125,13 @@ func ModifyInfo(c *gin.Context) {
 		updated = true
 	}
 	if req.UserAvatar != nil && req.UserAvatar.Size != 0 {
+		// 先校验新头像（只允许 JPEG、PNG、WebP 并做恶意文件扫描），不合格时保留原头像
+		if _, err := utils.ValidateAvatarImage(req.UserAvatar); err != nil {
+			failUploadCheck(c, err)
+			return
+		}
 		// 检查用户是否上传了新头像，有的话删除原头像
-		err := utils.DeleteFile(user.Avatar)
+		err := utils.DeleteFileWithThumbnails(user.Avatar)
 		if err != nil {
 			response.Fail(c, http.StatusInternalServerError, nil, constant.AvatarDeleteFailed)
 			return
@@ -127,7 +139,8 @@ func ModifyInfo(c *gin.Context) {
 		// 然后上传新头像到腾讯云
 		avatarURL, err := utils.UploadAvatar(req.UserAvatar)
 		if err != nil {
-			response.Fail(c, http.StatusInternalServerError, nil, err.Error())
+			log.Printf("[Avatar] 用户 %d 上传头像失败: %v", user.ID, err)
+			response.Fail(c, http.StatusInternalServerError, nil, constant.UploadAvatarFailed)
 			return
 		}
 		//最后更新头像链接到数据模型
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/antidote-kt/SSE_Library-back/config"
 	"github.com/antidote-kt/SSE_Library-back/models"
@@ -75,3 +76,38 @@ func GetMessageCountBySessionId(sessionId uint) (int64, error) {
 	err := config.DB.Model(&models.AIMessage{}).Where("ai_sessions_id = ?", sessionId).Count(&count).Error
 	return count, err
 }
+
+// likeEscaper 转义 LIKE 模式中的通配符，使关键词按字面匹配（MySQL 默认转义字符为 \）
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike 转义关键词中的 %、_ 和 \，用于 LIKE 模糊搜索
+func escapeLike(keyword string) string {
+	return likeEscaper.Replace(keyword)
+}
+
+// SearchAIMessagesByUserAndKeyword 在用户自己的全部AI会话中按关键词搜索消息（分页）
+// 通过关联 ai_sessions 表保证只返回属于该用户、且会话未被软删除的消息，结果按时间倒序排列
+func SearchAIMessagesByUserAndKeyword(userID uint64, keyword string, page, pageSize int) ([]models.AIMessage, int64, error) {
+	db := config.GetDB()
+	var messages []models.AIMessage
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	query := db.Model(&models.AIMessage{}).
+		Joins("JOIN ai_sessions ON ai_messages.ai_sessions_id = ai_sessions.id").
+		Where("ai_sessions.user_id = ? AND ai_sessions.deleted_at IS NULL", userID).
+		Where("ai_messages.content LIKE ?", "%"+escapeLike(keyword)+"%")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Select("ai_messages.*").
+		Order("ai_messages.created_at DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&messages).Error
+
+	return messages, total, err
+}
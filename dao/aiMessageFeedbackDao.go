@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// AIFeedbackDailyStat 按天、模型、提示词版本聚合的反馈统计
+type AIFeedbackDailyStat struct {
+	Day           string `json:"day"`
+	Model         string `json:"model"`
+	PromptVersion string `json:"promptVersion"`
+	UpCount       int64  `json:"upCount"`
+	DownCount     int64  `json:"downCount"`
+}
+
+// AIFeedbackMessageStat 单条 AI 回答的反馈统计
+type AIFeedbackMessageStat struct {
+	AIMessageID uint64
+	UpCount     int64
+	DownCount   int64
+}
+
+// GetAIMessageByID 根据ID获取AI消息
+func GetAIMessageByID(id uint64) (models.AIMessage, error) {
+	db := config.GetDB()
+	var message models.AIMessage
+	err := db.First(&message, id).Error
+	return message, err
+}
+
+// GetPreviousUserAIMessage 获取某条AI回答之前最近的一条用户提问
+func GetPreviousUserAIMessage(message models.AIMessage) (*models.AIMessage, error) {
+	db := config.GetDB()
+	var question models.AIMessage
+	err := db.Where("ai_sessions_id = ? AND role = ? AND id < ?", message.AISessionsID, "user", message.ID).
+		Order("id DESC").
+		First(&question).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &question, nil
+}
+
+// SaveAIMessageFeedback 创建或更新用户对某条AI回答的评价（每个用户每条回答只保留一条）
+func SaveAIMessageFeedback(feedback *models.AIMessageFeedback) error {
+	db := config.GetDB()
+	var existing models.AIMessageFeedback
+	err := db.Where("ai_message_id = ? AND user_id = ?", feedback.AIMessageID, feedback.UserID).First(&existing).Error
+	if err == nil {
+		existing.Rating = feedback.Rating
+		existing.Reason = feedback.Reason
+		if err := db.Save(&existing).Error; err != nil {
+			return err
+		}
+		*feedback = existing
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return db.Create(feedback).Error
+}
+
+// GetAIFeedbackDailyStats 统计时间范围内的反馈，按天、模型、提示词版本分组
+func GetAIFeedbackDailyStats(start, end time.Time) ([]AIFeedbackDailyStat, error) {
+	db := config.GetDB()
+	var stats []AIFeedbackDailyStat
+	err := db.Table("ai_message_feedbacks AS f").
+		Select("DATE_FORMAT(f.created_at, '%Y-%m-%d') AS day, m.model AS model, m.prompt_version AS prompt_version, "+
+			"SUM(CASE WHEN f.rating = ? THEN 1 ELSE 0 END) AS up_count, "+
+			"SUM(CASE WHEN f.rating = ? THEN 1 ELSE 0 END) AS down_count",
+			constant.AIFeedbackUp, constant.AIFeedbackDown).
+		Joins("JOIN ai_messages AS m ON m.id = f.ai_message_id").
+		Where("f.deleted_at IS NULL AND f.created_at >= ? AND f.created_at < ?", start, end).
+		Group("day, m.model, m.prompt_version").
+		Order("day DESC").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// GetWorstRatedAIMessages 获取点踩最多（净评分最低）的 AI 回答
+func GetWorstRatedAIMessages(start, end time.Time, limit int) ([]AIFeedbackMessageStat, error) {
+	db := config.GetDB()
+	var stats []AIFeedbackMessageStat
+	err := db.Table("ai_message_feedbacks").
+		Select("ai_message_id, "+
+			"SUM(CASE WHEN rating = ? THEN 1 ELSE 0 END) AS up_count, "+
+			"SUM(CASE WHEN rating = ? THEN 1 ELSE 0 END) AS down_count",
+			constant.AIFeedbackUp, constant.AIFeedbackDown).
+		Where("deleted_at IS NULL AND created_at >= ? AND created_at < ?", start, end).
+		Group("ai_message_id").
+		Having("down_count > 0").
+		Order("down_count - up_count DESC, down_count DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// GetAIFeedbackReasons 获取某条AI回答收到的全部非空评价原因
+func GetAIFeedbackReasons(messageID uint64) ([]models.AIMessageFeedback, error) {
+	db := config.GetDB()
+	var feedbacks []models.AIMessageFeedback
+	err := db.Where("ai_message_id = ? AND reason <> ''", messageID).
+		Order("created_at DESC").
+		Find(&feedbacks).Error
+	return feedbacks, err
+}
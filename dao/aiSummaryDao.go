@@ -0,0 +1,55 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// CreateAISummary 保存一次生成的摘要（历史记录保留）
+func CreateAISummary(summary *models.AISummary) error {
+	db := config.GetDB()
+	return db.Create(summary).Error
+}
+
+// GetLatestAISummary 获取内容某个风格的最新摘要，不存在时返回 nil
+func GetLatestAISummary(contentType string, contentID uint64, style string) (*models.AISummary, error) {
+	db := config.GetDB()
+	var summary models.AISummary
+	err := db.Where("content_type = ? AND content_id = ? AND style = ?", contentType, contentID, style).
+		Order("id DESC").
+		First(&summary).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// GetLatestAISummariesByContent 获取内容每种风格的最新摘要
+func GetLatestAISummariesByContent(contentType string, contentID uint64) ([]models.AISummary, error) {
+	db := config.GetDB()
+	var summaries []models.AISummary
+	latestIDs := db.Model(&models.AISummary{}).
+		Select("MAX(id)").
+		Where("content_type = ? AND content_id = ?", contentType, contentID).
+		Group("style")
+	err := db.Where("id IN (?)", latestIDs).Order("style").Find(&summaries).Error
+	return summaries, err
+}
+
+// GetAISummaryHistory 获取内容的摘要历史（按生成时间倒序），style 为空时返回全部风格
+func GetAISummaryHistory(contentType string, contentID uint64, style string) ([]models.AISummary, error) {
+	db := config.GetDB()
+	var summaries []models.AISummary
+	query := db.Where("content_type = ? AND content_id = ?", contentType, contentID)
+	if style != "" {
+		query = query.Where("style = ?", style)
+	}
+	err := query.Order("id DESC").Find(&summaries).Error
+	return summaries, err
+}
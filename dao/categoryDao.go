@@ -189,3 +189,38 @@ func GetPostHeatByCategory(categoryID uint64) (int64, error) {
 
 	return totalHeat, err
 }
+
+// GetOpenDocumentsInCategoryTree 获取分类及其所有子分类下的公开文档（用于课程助教限定检索范围和生成课程大纲）
+func GetOpenDocumentsInCategoryTree(categoryID uint64) ([]models.Document, error) {
+	db := config.GetDB()
+
+	// 获取所有子分类ID
+	descendantIDs, err := getAllDescendantCategoryIDs(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 构建分类ID列表：包括当前分类和所有子分类
+	categoryIDs := []uint64{categoryID}
+	categoryIDs = append(categoryIDs, descendantIDs...)
+
+	var documents []models.Document
+	err = db.Where("category_id IN ? AND status = ? AND deleted_at IS NULL", categoryIDs, constant.DocumentStatusOpen).
+		Order("read_counts DESC").
+		Find(&documents).Error
+	return documents, err
+}
+
+// GetCategoriesByNameAndParent 根据名称获取父分类下的子分类，parentID 为 nil 时获取顶级分类（用于按路径查找分类）
+func GetCategoriesByNameAndParent(name string, parentID *uint64) ([]models.Category, error) {
+	db := config.GetDB()
+	var categories []models.Category
+	query := db.Where("name = ?", name)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	err := query.Find(&categories).Error
+	return categories, err
+}
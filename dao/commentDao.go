@@ -31,7 +31,7 @@ func GetCommentWithUserAndDocument(sourceID uint64, sourceType string) ([]models
 	var comments []models.Comment
 
 	err := preloadCommentRelations(db).
-		Where("source_id = ? AND source_type = ? AND deleted_at IS NULL", sourceID, sourceType).
+		Where("source_id = ? AND source_type = ? AND deleted_at IS NULL AND is_hidden = ?", sourceID, sourceType, false).
 		Order("created_at DESC").
 		Find(&comments).Error
 
@@ -115,3 +115,9 @@ func DeleteUserComment(userID, commentID uint64) error {
 	return db.Where("id = ? AND user_id = ?", commentID, userID).
 		Delete(&models.Comment{}).Error
 }
+
+// SetCommentHidden 设置评论是否被隐藏（内容审核使用）
+func SetCommentHidden(commentID uint64, hidden bool) error {
+	db := config.GetDB()
+	return db.Model(&models.Comment{}).Where("id = ?", commentID).Update("is_hidden", hidden).Error
+}
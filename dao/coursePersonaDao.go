@@ -0,0 +1,44 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// GetCoursePersonaByCategoryID 获取课程的助教设定，未配置时返回 nil
+func GetCoursePersonaByCategoryID(categoryID uint64) (*models.CoursePersona, error) {
+	db := config.GetDB()
+	var persona models.CoursePersona
+	err := db.Where("category_id = ?", categoryID).First(&persona).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &persona, nil
+}
+
+// SaveCoursePersona 创建或更新课程的助教设定（每个课程只保留一条）
+func SaveCoursePersona(persona *models.CoursePersona) error {
+	db := config.GetDB()
+	var existing models.CoursePersona
+	err := db.Where("category_id = ?", persona.CategoryID).First(&existing).Error
+	if err == nil {
+		existing.Persona = persona.Persona
+		existing.Syllabus = persona.Syllabus
+		existing.UpdaterID = persona.UpdaterID
+		if err := db.Save(&existing).Error; err != nil {
+			return err
+		}
+		*persona = existing
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return db.Create(persona).Error
+}
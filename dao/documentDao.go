@@ -3,6 +3,7 @@ package dao
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/antidote-kt/SSE_Library-back/config"
 	"github.com/antidote-kt/SSE_Library-back/constant"
@@ -191,6 +192,25 @@ func SearchDocumentsByParams(request dto.SearchDocumentDTO) ([]models.Document,
 		query = query.Where("d.create_year = ?", *request.Year)
 	}
 
+	// 按文件元数据筛选，页数为 0 表示未知，不参与页数筛选
+	if request.MinPages != nil {
+		query = query.Where("d.page_count >= ?", *request.MinPages)
+	}
+	if request.MaxPages != nil {
+		query = query.Where("d.page_count > 0 AND d.page_count <= ?", *request.MaxPages)
+	}
+	if request.Language != nil && *request.Language != "" {
+		query = query.Where("d.language = ?", strings.ToLower(*request.Language))
+	}
+	if request.MimeType != nil && *request.MimeType != "" {
+		query = query.Where("d.mime_type = ?", *request.MimeType)
+	}
+
+	// 按评分排序：平均星级相同时评分人数多的在前，没有评分的文档排在最后
+	if request.Sort == constant.DocumentSortRating {
+		query = query.Order("d.rating_average DESC, d.rating_count DESC, d.id DESC")
+	}
+
 	// 执行查询
 	var documents []models.Document
 	err := query.Find(&documents).Error
@@ -221,7 +241,8 @@ func GetAllDocuments() ([]models.Document, error) {
 // GetDocumentList 获取文档列表
 // isSuggest: 是否为推荐模式 (true: 返回阅读量前10的文档)
 // categoryID: 分类ID查找特定分类的所有文档 (nil: 默认推荐模式)
-func GetDocumentList(isSuggest bool, categoryID *uint64) ([]models.Document, error) {
+// sort: 排序方式 (rating: 按评分从高到低，原有排序作为评分相同时的次序；推荐模式下返回评分前10的文档)
+func GetDocumentList(isSuggest bool, categoryID *uint64, sort string) ([]models.Document, error) {
 	db := config.GetDB()
 	var documents []models.Document
 	query := db.Model(&models.Document{})
@@ -229,6 +250,11 @@ func GetDocumentList(isSuggest bool, categoryID *uint64) ([]models.Document, err
 	// 基础条件：只返回状态为Open的文档，且未删除
 	query = query.Where("status = ? AND deleted_at IS NULL", constant.DocumentStatusOpen)
 
+	// 按评分排序时先按平均星级、评分人数排序
+	if sort == constant.DocumentSortRating {
+		query = query.Order("rating_average DESC, rating_count DESC")
+	}
+
 	// 1. 处理分类筛选 (无论是推荐模式还是普通模式，分类筛选如果传了都应该生效)
 	// 如果不希望在推荐模式下筛选分类，可以将这段移到 else 分支里
 	if categoryID != nil && *categoryID != 0 {
@@ -289,3 +315,64 @@ func GetTopReadBooks(limit int) ([]models.Document, error) {
 	}
 	return documents, nil
 }
+
+// GetDocumentsWithoutCover 获取没有封面的文档
+func GetDocumentsWithoutCover() ([]models.Document, error) {
+	db := config.GetDB()
+	var documents []models.Document
+	err := db.Where("cover = ? OR cover IS NULL", "").Find(&documents).Error
+	return documents, err
+}
+
+// GetCoverPaths 获取所有文档正在使用的封面路径
+func GetCoverPaths() ([]string, error) {
+	db := config.GetDB()
+	var covers []string
+	err := db.Model(&models.Document{}).Where("cover <> ?", "").Distinct().Pluck("cover", &covers).Error
+	return covers, err
+}
+
+// SetGeneratedDocumentCover 为没有封面的文档设置自动生成的封面，同时补到当前版本上；
+// 文档在生成期间已设置了封面时不覆盖，返回 false
+func SetGeneratedDocumentCover(documentID uint64, cover string) (bool, error) {
+	db := config.GetDB()
+	updated := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Document{}).
+			Where("id = ? AND (cover = ? OR cover IS NULL)", documentID, "").
+			UpdateColumn("cover", cover)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+		currentVersion := tx.Model(&models.Document{}).Select("version").Where("id = ?", documentID)
+		return tx.Model(&models.DocumentVersion{}).
+			Where("document_id = ? AND version = (?) AND (cover = ? OR cover IS NULL)", documentID, currentVersion, "").
+			UpdateColumn("cover", cover).Error
+	})
+	return updated, err
+}
+
+// UpdateDocumentFileMetadata 更新文档的文件技术元数据（文件大小、类型、哈希、页数、字数、语言和 PDF 信息）
+func UpdateDocumentFileMetadata(documentID uint64, metadata models.Document) error {
+	db := config.GetDB()
+	return db.Model(&models.Document{}).Where("id = ?", documentID).
+		Select("file_size", "mime_type", "content_hash", "page_count", "word_count", "language", "pdf_title", "pdf_author", "pdf_created_at").
+		UpdateColumns(metadata).Error
+}
+
+// GetDocumentsWithoutFileMetadata 获取还没有提取文件元数据的非视频文档（用于补全历史文档）
+func GetDocumentsWithoutFileMetadata() ([]models.Document, error) {
+	db := config.GetDB()
+	var documents []models.Document
+	err := db.Where("type <> ? AND (content_hash = ? OR content_hash IS NULL)", constant.VideoType, "").Find(&documents).Error
+	return documents, err
+}
+
+// GetDocumentByContentHash 根据文件 SHA-256 获取文档（用于批量导入时检查重复文件）
+func GetDocumentByContentHash(hash string) (models.Document, error) {
+	db := config.GetDB()
+	var document models.Document
+	err := db.Where("content_hash = ?", hash).First(&document).Error
+	return document, err
+}
@@ -0,0 +1,19 @@
+package dao
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// RecordDocumentDownload 记录一次下载并原子地增加文档的下载次数（不更新 UpdatedAt）
+func RecordDocumentDownload(download *models.DocumentDownload) error {
+	db := config.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(download).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Document{}).Where("id = ?", download.DocumentID).
+			UpdateColumn("download_counts", gorm.Expr("download_counts + ?", 1)).Error
+	})
+}
@@ -0,0 +1,47 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// GetMetadataSuggestionByDocumentID 获取文档的 AI 元数据建议，尚未生成时返回 nil
+func GetMetadataSuggestionByDocumentID(documentID uint64) (*models.DocumentMetadataSuggestion, error) {
+	db := config.GetDB()
+	var suggestion models.DocumentMetadataSuggestion
+	err := db.Where("document_id = ?", documentID).First(&suggestion).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// SaveMetadataSuggestion 创建或覆盖文档的 AI 元数据建议（每个文档只保留最新一条，重新生成后恢复为待处理）
+func SaveMetadataSuggestion(suggestion *models.DocumentMetadataSuggestion) error {
+	db := config.GetDB()
+	var existing models.DocumentMetadataSuggestion
+	err := db.Where("document_id = ?", suggestion.DocumentID).First(&existing).Error
+	if err == nil {
+		suggestion.ID = existing.ID
+		suggestion.CreatedAt = existing.CreatedAt
+		return db.Save(suggestion).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return db.Create(suggestion).Error
+}
+
+// MarkMetadataSuggestionAcceptedWithTx 在事务中记录建议已被采纳的字段
+func MarkMetadataSuggestionAcceptedWithTx(tx *gorm.DB, suggestion *models.DocumentMetadataSuggestion, acceptedFields string) error {
+	return tx.Model(suggestion).Updates(map[string]interface{}{
+		"status":          suggestion.Status,
+		"accepted_fields": acceptedFields,
+	}).Error
+}
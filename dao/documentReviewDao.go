@@ -0,0 +1,186 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetDocumentReview 获取用户对文档的评价
+func GetDocumentReview(userID, documentID uint64) (models.DocumentReview, error) {
+	db := config.GetDB()
+	var review models.DocumentReview
+	err := db.Where("user_id = ? AND document_id = ?", userID, documentID).First(&review).Error
+	return review, err
+}
+
+// GetDocumentReviewByID 根据ID获取评价
+func GetDocumentReviewByID(id uint64) (models.DocumentReview, error) {
+	db := config.GetDB()
+	var review models.DocumentReview
+	err := db.First(&review, id).Error
+	return review, err
+}
+
+// SaveDocumentReview 新建或修改用户对文档的评价（按用户和文档的唯一索引 upsert，同一用户并发首次提交时不会因唯一索引冲突失败），
+// 并在同一事务中重新计算文档的评分汇总。review 会被更新为保存后的评价，返回是否为新建
+func SaveDocumentReview(review *models.DocumentReview) (bool, error) {
+	db := config.GetDB()
+	created := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "document_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"stars", "content", "updated_at"}),
+		}).Create(review)
+		if result.Error != nil {
+			return result.Error
+		}
+		// MySQL 的 INSERT ... ON DUPLICATE KEY UPDATE 新插入时影响行数为 1，修改已有评价时为 2
+		created = result.RowsAffected == 1
+
+		// 修改已有评价时 review 中的 ID、有帮助人数等不是数据库中的值，重新读取
+		var saved models.DocumentReview
+		if err := tx.Where("user_id = ? AND document_id = ?", review.UserID, review.DocumentID).First(&saved).Error; err != nil {
+			return err
+		}
+		*review = saved
+		return refreshDocumentRating(tx, review.DocumentID)
+	})
+	return created, err
+}
+
+// DeleteDocumentReview 删除评价及其有帮助投票，并重新计算文档的评分汇总
+func DeleteDocumentReview(review *models.DocumentReview) error {
+	db := config.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("review_id = ?", review.ID).Delete(&models.ReviewHelpfulVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(review).Error; err != nil {
+			return err
+		}
+		return refreshDocumentRating(tx, review.DocumentID)
+	})
+}
+
+// refreshDocumentRating 按文档当前的全部评价重新计算平均星级和评分人数（不更新文档的修改时间）
+func refreshDocumentRating(tx *gorm.DB, documentID uint64) error {
+	var summary struct {
+		Average float64
+		Count   int
+	}
+	err := tx.Model(&models.DocumentReview{}).
+		Select("COALESCE(ROUND(AVG(stars), 2), 0) AS average, COUNT(*) AS count").
+		Where("document_id = ?", documentID).
+		Scan(&summary).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.Document{}).Where("id = ?", documentID).UpdateColumns(map[string]interface{}{
+		"rating_average": summary.Average,
+		"rating_count":   summary.Count,
+	}).Error
+}
+
+// GetDocumentReviews 分页获取文档的评价，sort 为 helpful 时有帮助人数最多的在前，为 latest 时最近修改的在前
+func GetDocumentReviews(documentID uint64, sort string, page, pageSize int) ([]models.DocumentReview, int64, error) {
+	db := config.GetDB()
+	query := db.Model(&models.DocumentReview{}).Where("document_id = ?", documentID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if sort == constant.ReviewSortHelpful {
+		query = query.Order("helpful_count DESC")
+	}
+	var reviews []models.DocumentReview
+	err := query.Order("updated_at DESC").Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&reviews).Error
+	return reviews, total, err
+}
+
+// GetDocumentStarCounts 统计文档各星级的评价数，没有评价的星级为 0
+func GetDocumentStarCounts(documentID uint64) (map[int]int64, error) {
+	db := config.GetDB()
+	var rows []struct {
+		Stars int
+		Count int64
+	}
+	err := db.Model(&models.DocumentReview{}).
+		Select("stars, COUNT(*) AS count").
+		Where("document_id = ?", documentID).
+		Group("stars").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int]int64, constant.MaxReviewStars)
+	for stars := constant.MinReviewStars; stars <= constant.MaxReviewStars; stars++ {
+		counts[stars] = 0
+	}
+	for _, row := range rows {
+		counts[row.Stars] = row.Count
+	}
+	return counts, nil
+}
+
+// VoteReviewHelpful 标记评价有帮助 (事务处理：插入投票 + 计数+1)
+func VoteReviewHelpful(userID, reviewID uint64) error {
+	db := config.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 依靠唯一索引判断是否已投票，并发重复投票时只有一次插入成功
+		vote := models.ReviewHelpfulVote{ReviewID: reviewID, UserID: userID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&vote)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New(constant.AlreadyVotedHelpful)
+		}
+		return tx.Model(&models.DocumentReview{}).Where("id = ?", reviewID).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count + ?", 1)).Error
+	})
+}
+
+// UnvoteReviewHelpful 取消有帮助 (事务处理：删除投票 + 计数-1)
+func UnvoteReviewHelpful(userID, reviewID uint64) error {
+	db := config.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("review_id = ? AND user_id = ?", reviewID, userID).Delete(&models.ReviewHelpfulVote{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New(constant.NotVotedHelpful)
+		}
+		return tx.Model(&models.DocumentReview{}).Where("id = ? AND helpful_count > 0", reviewID).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count - ?", 1)).Error
+	})
+}
+
+// GetHelpfulVotedReviewIDs 获取用户在给定评价中已标记有帮助的评价ID
+func GetHelpfulVotedReviewIDs(userID uint64, reviewIDs []uint64) (map[uint64]bool, error) {
+	voted := make(map[uint64]bool)
+	if len(reviewIDs) == 0 {
+		return voted, nil
+	}
+	db := config.GetDB()
+	var ids []uint64
+	err := db.Model(&models.ReviewHelpfulVote{}).
+		Where("user_id = ? AND review_id IN ?", userID, reviewIDs).
+		Pluck("review_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		voted[id] = true
+	}
+	return voted, nil
+}
@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// CountDocumentVersionsWithTx 统计文档的版本数（含已清理的版本）
+func CountDocumentVersionsWithTx(tx *gorm.DB, documentID uint64) (int64, error) {
+	var count int64
+	err := tx.Unscoped().Model(&models.DocumentVersion{}).Where("document_id = ?", documentID).Count(&count).Error
+	return count, err
+}
+
+// CreateDocumentVersionWithTx 在事务中为文档新增版本，版本号为已有最大版本号（含已清理的版本）加 1
+func CreateDocumentVersionWithTx(tx *gorm.DB, version *models.DocumentVersion) error {
+	var maxVersion int
+	err := tx.Unscoped().Model(&models.DocumentVersion{}).
+		Where("document_id = ?", version.DocumentID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&maxVersion).Error
+	if err != nil {
+		return err
+	}
+	version.Version = maxVersion + 1
+	return tx.Create(version).Error
+}
+
+// GetDocumentVersions 获取文档的全部版本，按版本号倒序
+func GetDocumentVersions(documentID uint64) ([]models.DocumentVersion, error) {
+	db := config.GetDB()
+	var versions []models.DocumentVersion
+	if err := db.Where("document_id = ?", documentID).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetDocumentVersion 获取文档的指定版本
+func GetDocumentVersion(documentID uint64, version int) (models.DocumentVersion, error) {
+	db := config.GetDB()
+	var documentVersion models.DocumentVersion
+	err := db.Where("document_id = ? AND version = ?", documentID, version).First(&documentVersion).Error
+	return documentVersion, err
+}
+
+// GetDocumentVersionWithTx 在事务中获取文档的指定版本
+func GetDocumentVersionWithTx(tx *gorm.DB, documentID uint64, version int) (models.DocumentVersion, error) {
+	var documentVersion models.DocumentVersion
+	err := tx.Where("document_id = ? AND version = ?", documentID, version).First(&documentVersion).Error
+	return documentVersion, err
+}
+
+// GetAllDocumentVersions 获取全部文档版本，按文档和版本号倒序，用于按保留策略清理
+func GetAllDocumentVersions() ([]models.DocumentVersion, error) {
+	db := config.GetDB()
+	var versions []models.DocumentVersion
+	if err := db.Order("document_id ASC, version DESC").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// DeleteDocumentVersions 删除（软删除）指定的文档版本
+func DeleteDocumentVersions(ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	db := config.GetDB()
+	return db.Where("id IN ?", ids).Delete(&models.DocumentVersion{}).Error
+}
+
+// IsDocumentFileReferenced 判断 COS 中的文件是否仍被某个文档或未清理的版本使用（回滚后多个版本可能共用同一文件）
+func IsDocumentFileReferenced(path string) (bool, error) {
+	db := config.GetDB()
+	var count int64
+	err := db.Model(&models.DocumentVersion{}).Where("url = ? OR cover = ?", path, path).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+	err = db.Model(&models.Document{}).Where("url = ? OR cover = ?", path, path).Count(&count).Error
+	return count > 0, err
+}
@@ -80,8 +80,8 @@ func GetFavoritePostsByUserID(userID uint64) ([]models.Post, error) {
 	var posts []models.Post
 	for _, favorite := range favorites {
 		var post models.Post
-		// 获取收藏的帖子
-		err := db.Where("id = ?", favorite.SourceID).First(&post).Error
+		// 获取收藏的帖子（跳过被内容审核隐藏的他人帖子）
+		err := db.Where("id = ? AND (is_hidden = ? OR sender_id = ?)", favorite.SourceID, false, userID).First(&post).Error
 		if err != nil {
 			continue // 跳过不存在的帖子
 		}
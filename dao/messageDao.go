@@ -71,3 +71,17 @@ func SearchMessagesByUserAndKeyword(userID uint64, keyword string) ([]models.Mes
 
 	return messages, err
 }
+
+// GetMessageByID 根据ID获取私信
+func GetMessageByID(messageID uint64) (models.Message, error) {
+	db := config.GetDB()
+	var message models.Message
+	err := db.First(&message, messageID).Error
+	return message, err
+}
+
+// DeleteMessage 删除私信（内容审核驳回时使用）
+func DeleteMessage(messageID uint64) error {
+	db := config.GetDB()
+	return db.Where("id = ?", messageID).Delete(&models.Message{}).Error
+}
@@ -0,0 +1,71 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// SaveModerationRecord 创建或覆盖某条内容的审核记录（内容修改后重新审核时覆盖旧结果）
+func SaveModerationRecord(record *models.ModerationRecord) error {
+	db := config.GetDB()
+	var existing models.ModerationRecord
+	err := db.Where("content_type = ? AND content_id = ?", record.ContentType, record.ContentID).First(&existing).Error
+	if err == nil {
+		record.ID = existing.ID
+		record.CreatedAt = existing.CreatedAt
+		return db.Save(record).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return db.Create(record).Error
+}
+
+// GetModerationRecordByID 根据ID获取审核记录
+func GetModerationRecordByID(id uint64) (models.ModerationRecord, error) {
+	db := config.GetDB()
+	var record models.ModerationRecord
+	err := db.First(&record, id).Error
+	return record, err
+}
+
+// GetModerationRecords 按状态和内容类型筛选审核记录（为空表示不筛选），风险分高的排在前面
+func GetModerationRecords(statuses []string, contentType string) ([]models.ModerationRecord, error) {
+	db := config.GetDB()
+	var records []models.ModerationRecord
+	query := db.Model(&models.ModerationRecord{})
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	if contentType != "" {
+		query = query.Where("content_type = ?", contentType)
+	}
+	err := query.Order("risk_score DESC, created_at DESC").Find(&records).Error
+	return records, err
+}
+
+// GetModerationRecordsByContentIDs 批量获取同一类型内容的审核记录，按内容ID索引
+func GetModerationRecordsByContentIDs(contentType string, contentIDs []uint64) (map[uint64]models.ModerationRecord, error) {
+	db := config.GetDB()
+	result := make(map[uint64]models.ModerationRecord)
+	if len(contentIDs) == 0 {
+		return result, nil
+	}
+	var records []models.ModerationRecord
+	if err := db.Where("content_type = ? AND content_id IN ?", contentType, contentIDs).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		result[record.ContentID] = record
+	}
+	return result, nil
+}
+
+// UpdateModerationRecord 更新审核记录（用于人工复核）
+func UpdateModerationRecord(record *models.ModerationRecord) error {
+	db := config.GetDB()
+	return db.Save(record).Error
+}
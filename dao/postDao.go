@@ -57,7 +57,8 @@ func GetPostByID(postID uint64) (models.Post, error) {
 func GetPostList(key string, order string) ([]models.Post, error) {
 	db := config.GetDB()
 	var posts []models.Post
-	query := db.Model(&models.Post{})
+	// 被内容审核隐藏的帖子不出现在列表中
+	query := db.Model(&models.Post{}).Where("is_hidden = ?", false)
 
 	// 1. 处理关键词搜索 (标题或内容)
 	if key != "" {
@@ -168,15 +169,16 @@ func DecrementPostCommentCount(postID uint64) error {
 		Update("comment_count", gorm.Expr("CASE WHEN comment_count > 0 THEN comment_count - 1 ELSE 0 END")).Error
 }
 
-// GetPostsByUserID 获取指定用户发布的帖子列表
-func GetPostsByUserID(userID uint64) ([]models.Post, error) {
+// GetPostsByUserID 获取指定用户发布的帖子列表，被内容审核隐藏的帖子只有发布者本人（viewerID 与 userID 相同）可以看到
+func GetPostsByUserID(userID, viewerID uint64) ([]models.Post, error) {
 	db := config.GetDB()
 	var posts []models.Post
 
-	err := db.Model(&models.Post{}).
-		Where("sender_id = ? AND deleted_at IS NULL", userID).
-		Order("created_at DESC").
-		Find(&posts).Error
+	query := db.Model(&models.Post{}).Where("sender_id = ? AND deleted_at IS NULL", userID)
+	if viewerID != userID {
+		query = query.Where("is_hidden = ?", false)
+	}
+	err := query.Order("created_at DESC").Find(&posts).Error
 
 	if err != nil {
 		return nil, err
@@ -210,3 +212,9 @@ func DeletePostWithTx(postID uint64) error {
 		return nil
 	})
 }
+
+// SetPostHidden 设置帖子是否被隐藏（内容审核使用）
+func SetPostHidden(postID uint64, hidden bool) error {
+	db := config.GetDB()
+	return db.Model(&models.Post{}).Where("id = ?", postID).Update("is_hidden", hidden).Error
+}
@@ -5,15 +5,18 @@ import (
 	"github.com/antidote-kt/SSE_Library-back/models"
 )
 
-// GetPostsByDocumentID 获取与指定文档关联的帖子列表
-func GetPostsByDocumentID(documentID uint64) ([]models.Post, error) {
+// GetPostsByDocumentID 获取与指定文档关联的帖子列表，被内容审核隐藏的帖子只返回给发帖人（viewerID）和管理员
+func GetPostsByDocumentID(documentID, viewerID uint64, isAdmin bool) ([]models.Post, error) {
 	db := config.GetDB()
 	var posts []models.Post
 
-	err := db.Table("posts").
+	query := db.Table("posts").
 		Joins("JOIN post_documents ON posts.id = post_documents.post_id").
-		Where("post_documents.document_id = ?", documentID).
-		Find(&posts).Error
+		Where("post_documents.document_id = ?", documentID)
+	if !isAdmin {
+		query = query.Where("posts.is_hidden = ? OR posts.sender_id = ?", false, viewerID)
+	}
+	err := query.Find(&posts).Error
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// GetActivePromptTemplate 获取某个提示词标识当前启用的版本，未配置时返回 nil
+func GetActivePromptTemplate(key string) (*models.PromptTemplate, error) {
+	db := config.GetDB()
+	var tmpl models.PromptTemplate
+	err := db.Where("template_key = ? AND is_active = ?", key, true).First(&tmpl).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// GetPromptTemplateVersions 获取某个提示词标识的全部版本（按版本号倒序）
+func GetPromptTemplateVersions(key string) ([]models.PromptTemplate, error) {
+	db := config.GetDB()
+	var templates []models.PromptTemplate
+	err := db.Where("template_key = ?", key).Order("version DESC").Find(&templates).Error
+	return templates, err
+}
+
+// GetPromptTemplateByVersion 获取某个提示词标识的指定版本
+func GetPromptTemplateByVersion(key string, version int) (models.PromptTemplate, error) {
+	db := config.GetDB()
+	var tmpl models.PromptTemplate
+	err := db.Where("template_key = ? AND version = ?", key, version).First(&tmpl).Error
+	return tmpl, err
+}
+
+// CreatePromptTemplateVersion 为提示词标识新增一个版本（版本号自动递增），activate 为 true 时同时启用该版本
+func CreatePromptTemplateVersion(tmpl *models.PromptTemplate, activate bool) error {
+	db := config.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 包含已删除的版本，避免版本号重复
+		var maxVersion int
+		if err := tx.Unscoped().Model(&models.PromptTemplate{}).
+			Where("template_key = ?", tmpl.TemplateKey).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&maxVersion).Error; err != nil {
+			return err
+		}
+		tmpl.Version = maxVersion + 1
+		tmpl.IsActive = activate
+
+		if activate {
+			if err := tx.Model(&models.PromptTemplate{}).
+				Where("template_key = ? AND is_active = ?", tmpl.TemplateKey, true).
+				Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(tmpl).Error
+	})
+}
+
+// ActivatePromptTemplateVersion 启用指定版本（用于回滚），同一标识下的其他版本全部停用
+func ActivatePromptTemplateVersion(key string, version int) (models.PromptTemplate, error) {
+	db := config.GetDB()
+	var tmpl models.PromptTemplate
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_key = ? AND version = ?", key, version).First(&tmpl).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.PromptTemplate{}).
+			Where("template_key = ? AND is_active = ?", key, true).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+		tmpl.IsActive = true
+		return tx.Save(&tmpl).Error
+	})
+	return tmpl, err
+}
+
+// DeactivatePromptTemplates 停用某个标识的全部版本，之后将回退到内置提示词
+func DeactivatePromptTemplates(key string) error {
+	db := config.GetDB()
+	return db.Model(&models.PromptTemplate{}).
+		Where("template_key = ? AND is_active = ?", key, true).
+		Update("is_active", false).Error
+}
+
+// DeletePromptTemplate 删除提示词模板的某个版本
+func DeletePromptTemplate(tmpl *models.PromptTemplate) error {
+	db := config.GetDB()
+	return db.Delete(tmpl).Error
+}
@@ -0,0 +1,67 @@
+package dao
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// QuizAttemptWithTitle 答题记录及其测验标题
+type QuizAttemptWithTitle struct {
+	models.QuizAttempt
+	QuizTitle string
+}
+
+// CreateQuiz 保存测验
+func CreateQuiz(quiz *models.Quiz) error {
+	db := config.GetDB()
+	return db.Create(quiz).Error
+}
+
+// GetQuizByID 根据ID获取测验
+func GetQuizByID(id uint64) (models.Quiz, error) {
+	db := config.GetDB()
+	var quiz models.Quiz
+	err := db.First(&quiz, id).Error
+	return quiz, err
+}
+
+// GetQuizzesByUserAndDocument 获取用户为某个文档保存的全部测验（按创建时间倒序）
+func GetQuizzesByUserAndDocument(userID, documentID uint64) ([]models.Quiz, error) {
+	db := config.GetDB()
+	var quizzes []models.Quiz
+	err := db.Where("user_id = ? AND document_id = ?", userID, documentID).
+		Order("created_at DESC").
+		Find(&quizzes).Error
+	return quizzes, err
+}
+
+// DeleteQuiz 删除测验及其答题记录
+func DeleteQuiz(quiz *models.Quiz) error {
+	db := config.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("quiz_id = ?", quiz.ID).Delete(&models.QuizAttempt{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(quiz).Error
+	})
+}
+
+// CreateQuizAttempt 保存答题记录
+func CreateQuizAttempt(attempt *models.QuizAttempt) error {
+	db := config.GetDB()
+	return db.Create(attempt).Error
+}
+
+// GetQuizAttemptsByUserAndDocument 获取用户在某个文档下全部测验的答题记录（按答题时间倒序）
+func GetQuizAttemptsByUserAndDocument(userID, documentID uint64) ([]QuizAttemptWithTitle, error) {
+	db := config.GetDB()
+	var attempts []QuizAttemptWithTitle
+	err := db.Model(&models.QuizAttempt{}).
+		Select("quiz_attempts.*, quizzes.title AS quiz_title").
+		Joins("JOIN quizzes ON quizzes.id = quiz_attempts.quiz_id AND quizzes.deleted_at IS NULL").
+		Where("quiz_attempts.user_id = ? AND quiz_attempts.document_id = ? AND quiz_attempts.deleted_at IS NULL", userID, documentID).
+		Order("quiz_attempts.created_at DESC").
+		Scan(&attempts).Error
+	return attempts, err
+}
@@ -0,0 +1,186 @@
+package dao
+
+import (
+	"fmt"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ContinueReadingItem 继续阅读列表项：阅读进度及其文档
+type ContinueReadingItem struct {
+	Progress models.ReadingProgress
+	Document models.Document
+}
+
+// GetReadingProgress 获取用户在文档中的阅读进度
+func GetReadingProgress(userID, documentID uint64) (models.ReadingProgress, error) {
+	db := config.GetDB()
+	var progress models.ReadingProgress
+	err := db.Where("user_id = ? AND document_id = ?", userID, documentID).First(&progress).Error
+	return progress, err
+}
+
+// SyncReadingProgress 保存阅读器同步的阅读进度，只有记录时间不早于已保存的进度时才覆盖，
+// 多台设备同时同步时由数据库按 read_at 判断新旧。progress 会被更新为数据库中保存的进度，返回同步的进度是否已过时
+func SyncReadingProgress(progress *models.ReadingProgress) (bool, error) {
+	db := config.GetDB()
+	readAt := progress.ReadAt
+	// MySQL 按顺序计算赋值，read_at 必须最后更新，前面的列才能与原来的 read_at 比较
+	newer := func(column string) clause.Assignment {
+		return clause.Assignment{
+			Column: clause.Column{Name: column},
+			Value:  gorm.Expr(fmt.Sprintf("IF(VALUES(read_at) >= read_at, VALUES(%s), %s)", column, column)),
+		}
+	}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "document_id"}},
+		DoUpdates: clause.Set{newer("page"), newer("position"), newer("percent"), newer("updated_at"), newer("read_at")},
+	}).Create(progress).Error
+	if err != nil {
+		return false, err
+	}
+
+	// 未覆盖时 progress 中是阅读器提交的进度，重新读取数据库中保存的进度
+	var saved models.ReadingProgress
+	if err := db.Where("user_id = ? AND document_id = ?", progress.UserID, progress.DocumentID).First(&saved).Error; err != nil {
+		return false, err
+	}
+	*progress = saved
+	return saved.ReadAt.After(readAt), nil
+}
+
+// GetContinueReading 获取用户未读完的文档（按最近阅读时间倒序），只包含开放的文档和用户本人上传的文档
+func GetContinueReading(userID uint64, page, pageSize int) ([]ContinueReadingItem, int64, error) {
+	db := config.GetDB()
+	query := db.Model(&models.ReadingProgress{}).
+		Joins("JOIN documents ON documents.id = reading_progresses.document_id AND documents.deleted_at IS NULL").
+		Where("reading_progresses.user_id = ? AND reading_progresses.percent < ?", userID, constant.ReadingFinishedPercent).
+		Where("(documents.status = ? OR documents.uploader_id = ?)", constant.DocumentStatusOpen, userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var progresses []models.ReadingProgress
+	err := query.Select("reading_progresses.*").
+		Order("reading_progresses.read_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&progresses).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uint64, len(progresses))
+	for i, progress := range progresses {
+		ids[i] = progress.DocumentID
+	}
+	documents, err := GetDocumentsByIDsIgnoreStatus(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	documentMap := make(map[uint64]models.Document, len(documents))
+	for _, document := range documents {
+		documentMap[document.ID] = document
+	}
+
+	items := make([]ContinueReadingItem, 0, len(progresses))
+	for _, progress := range progresses {
+		if document, ok := documentMap[progress.DocumentID]; ok {
+			items = append(items, ContinueReadingItem{Progress: progress, Document: document})
+		}
+	}
+	return items, total, nil
+}
+
+// GetReadingBookmarks 获取用户在文档中的书签（按页码排序）
+func GetReadingBookmarks(userID, documentID uint64) ([]models.ReadingBookmark, error) {
+	db := config.GetDB()
+	var bookmarks []models.ReadingBookmark
+	err := db.Where("user_id = ? AND document_id = ?", userID, documentID).
+		Order("page ASC, created_at ASC").
+		Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+// CountReadingBookmarks 统计用户在文档中的书签数
+func CountReadingBookmarks(userID, documentID uint64) (int64, error) {
+	db := config.GetDB()
+	var count int64
+	err := db.Model(&models.ReadingBookmark{}).Where("user_id = ? AND document_id = ?", userID, documentID).Count(&count).Error
+	return count, err
+}
+
+// GetReadingBookmarkByID 根据ID获取书签
+func GetReadingBookmarkByID(id uint64) (models.ReadingBookmark, error) {
+	db := config.GetDB()
+	var bookmark models.ReadingBookmark
+	err := db.First(&bookmark, id).Error
+	return bookmark, err
+}
+
+// CreateReadingBookmark 添加书签
+func CreateReadingBookmark(bookmark *models.ReadingBookmark) error {
+	db := config.GetDB()
+	return db.Create(bookmark).Error
+}
+
+// UpdateReadingBookmark 更新书签
+func UpdateReadingBookmark(bookmark *models.ReadingBookmark) error {
+	db := config.GetDB()
+	return db.Save(bookmark).Error
+}
+
+// DeleteReadingBookmark 删除书签
+func DeleteReadingBookmark(bookmark *models.ReadingBookmark) error {
+	db := config.GetDB()
+	return db.Delete(bookmark).Error
+}
+
+// GetReadingHighlights 获取用户在文档中的划线（按页码和位置排序）
+func GetReadingHighlights(userID, documentID uint64) ([]models.ReadingHighlight, error) {
+	db := config.GetDB()
+	var highlights []models.ReadingHighlight
+	err := db.Where("user_id = ? AND document_id = ?", userID, documentID).
+		Order("page ASC, start_offset ASC").
+		Find(&highlights).Error
+	return highlights, err
+}
+
+// CountReadingHighlights 统计用户在文档中的划线数
+func CountReadingHighlights(userID, documentID uint64) (int64, error) {
+	db := config.GetDB()
+	var count int64
+	err := db.Model(&models.ReadingHighlight{}).Where("user_id = ? AND document_id = ?", userID, documentID).Count(&count).Error
+	return count, err
+}
+
+// GetReadingHighlightByID 根据ID获取划线
+func GetReadingHighlightByID(id uint64) (models.ReadingHighlight, error) {
+	db := config.GetDB()
+	var highlight models.ReadingHighlight
+	err := db.First(&highlight, id).Error
+	return highlight, err
+}
+
+// CreateReadingHighlight 添加划线
+func CreateReadingHighlight(highlight *models.ReadingHighlight) error {
+	db := config.GetDB()
+	return db.Create(highlight).Error
+}
+
+// UpdateReadingHighlight 更新划线
+func UpdateReadingHighlight(highlight *models.ReadingHighlight) error {
+	db := config.GetDB()
+	return db.Save(highlight).Error
+}
+
+// DeleteReadingHighlight 删除划线
+func DeleteReadingHighlight(highlight *models.ReadingHighlight) error {
+	db := config.GetDB()
+	return db.Delete(highlight).Error
+}
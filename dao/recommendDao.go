@@ -0,0 +1,318 @@
+package dao
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// UserInteraction 用户与文档或帖子的一次交互
+type UserInteraction struct {
+	UserID    uint64
+	ItemType  string // document、post
+	ItemID    uint64
+	Action    string // view、favorite、like、comment、own
+	UpdatedAt time.Time
+}
+
+// interactionSource 一类交互记录的来源表及各列
+type interactionSource struct {
+	table   string
+	userCol string
+	typeCol string // 内容类型列，或固定的内容类型
+	itemCol string
+	action  string
+	timeCol string
+}
+
+// interactionSources 交互记录的来源：浏览、收藏、点赞、评论以及自己上传/发布的内容
+var interactionSources = []interactionSource{
+	{"view_histories", "user_id", "source_type", "source_id", constant.InteractionView, "updated_at"},
+	{"favorites", "user_id", "source_type", "source_id", constant.InteractionFavorite, "updated_at"},
+	{"post_likes", "user_id", "'post'", "post_id", constant.InteractionLike, "created_at"},
+	{"comments", "user_id", "source_type", "source_id", constant.InteractionComment, "updated_at"},
+	{"documents", "uploader_id", "'document'", "id", constant.InteractionOwn, "created_at"},
+	{"posts", "sender_id", "'post'", "id", constant.InteractionOwn, "created_at"},
+}
+
+// boundedInteractionSQL 拼接各类来源的交互记录（UNION ALL），每类来源只取满足 where 条件、
+// since 之后（为零值时不限时间）最近的 limit 条，避免扫描全部历史；includeOwn 为 false 时不含自己上传/发布的内容
+func boundedInteractionSQL(where func(source interactionSource) (string, []interface{}), since time.Time, limit int, includeOwn bool) (string, []interface{}) {
+	parts := make([]string, 0, len(interactionSources))
+	args := make([]interface{}, 0, len(interactionSources)*4)
+	for _, source := range interactionSources {
+		if !includeOwn && source.action == constant.InteractionOwn {
+			continue
+		}
+		condition, conditionArgs := where(source)
+		part := fmt.Sprintf("(SELECT %s AS user_id, %s AS item_type, %s AS item_id, '%s' AS action, %s AS updated_at FROM %s "+
+			"WHERE deleted_at IS NULL AND %s", source.userCol, source.typeCol, source.itemCol, source.action,
+			source.timeCol, source.table, condition)
+		args = append(args, conditionArgs...)
+		if !since.IsZero() {
+			part += fmt.Sprintf(" AND %s >= ?", source.timeCol)
+			args = append(args, since)
+		}
+		part += fmt.Sprintf(" ORDER BY %s DESC LIMIT ?)", source.timeCol)
+		args = append(args, limit)
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " UNION ALL "), args
+}
+
+// itemInteractionSQL 与指定内容产生过交互的记录（不含上传者/发帖人），只取最近 RecommendNeighborWindowDays 天内、
+// 每类来源最多 RecommendNeighborInteractionLimit 条
+func itemInteractionSQL(itemType string, itemIDs []uint64) (string, []interface{}) {
+	since := time.Now().AddDate(0, 0, -constant.RecommendNeighborWindowDays)
+	return boundedInteractionSQL(func(source interactionSource) (string, []interface{}) {
+		return fmt.Sprintf("%s = ? AND %s IN ?", source.typeCol, source.itemCol), []interface{}{itemType, itemIDs}
+	}, since, constant.RecommendNeighborInteractionLimit, false)
+}
+
+// queryBoundedInteractions 按用户查询交互记录，每类来源只取 since 之后（为零值时不限时间）最近的 limit 条
+func queryBoundedInteractions(userIDs []uint64, since time.Time, limit int, includeOwn bool) ([]UserInteraction, error) {
+	db := config.GetDB()
+	var interactions []UserInteraction
+	if len(userIDs) == 0 {
+		return interactions, nil
+	}
+
+	union, args := boundedInteractionSQL(func(source interactionSource) (string, []interface{}) {
+		return source.userCol + " IN ?", []interface{}{userIDs}
+	}, since, limit, includeOwn)
+	err := db.Raw("SELECT * FROM ("+union+") AS i ORDER BY i.updated_at DESC", args...).
+		Scan(&interactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return interactions, nil
+}
+
+// GetUserInteractions 获取用户最近的交互记录（每类来源最多 RecommendInteractionLimit 条），按时间倒序
+func GetUserInteractions(userID uint64) ([]UserInteraction, error) {
+	return queryBoundedInteractions([]uint64{userID}, time.Time{}, constant.RecommendInteractionLimit, true)
+}
+
+// GetCoInteractedUserIDs 获取最近同样与指定内容产生过交互的其他用户，按共同交互数倒序
+func GetCoInteractedUserIDs(itemType string, itemIDs []uint64, excludeUserID uint64, limit int) ([]uint64, error) {
+	db := config.GetDB()
+	var userIDs []uint64
+	if len(itemIDs) == 0 {
+		return userIDs, nil
+	}
+	union, args := itemInteractionSQL(itemType, itemIDs)
+	err := db.Raw("SELECT i.user_id FROM ("+union+") AS i WHERE i.user_id <> ? "+
+		"GROUP BY i.user_id ORDER BY COUNT(DISTINCT i.item_id) DESC LIMIT ?",
+		append(args, excludeUserID, limit)...).
+		Scan(&userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// GetInteractionsByUserIDs 获取一批用户最近 RecommendNeighborWindowDays 天内的交互记录（不含自己上传/发布的内容），
+// 每类来源最多 RecommendNeighborInteractionLimit 条
+func GetInteractionsByUserIDs(userIDs []uint64) ([]UserInteraction, error) {
+	since := time.Now().AddDate(0, 0, -constant.RecommendNeighborWindowDays)
+	return queryBoundedInteractions(userIDs, since, constant.RecommendNeighborInteractionLimit, false)
+}
+
+// GetItemInteractedUserCounts 统计每个内容最近有多少不同用户交互过（不含上传者/发帖人），用于协同过滤归一化，
+// 与 GetCoInteractedUserIDs 使用相同的时间窗口和条数上限
+func GetItemInteractedUserCounts(itemType string, itemIDs []uint64) (map[uint64]int64, error) {
+	db := config.GetDB()
+	counts := make(map[uint64]int64)
+	if len(itemIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		ItemID    uint64
+		UserCount int64
+	}
+	union, args := itemInteractionSQL(itemType, itemIDs)
+	err := db.Raw("SELECT i.item_id, COUNT(DISTINCT i.user_id) AS user_count FROM ("+union+") AS i GROUP BY i.item_id", args...).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ItemID] = row.UserCount
+	}
+	return counts, nil
+}
+
+// GetRecommendCandidateDocuments 获取指定类型的公开文档作为推荐候选：属于给定分类或带有给定标签的文档，
+// 以及浏览量最高的文档，按浏览量倒序
+func GetRecommendCandidateDocuments(docType string, categoryIDs []uint64, tagNames []string, limit int) ([]models.Document, error) {
+	db := config.GetDB()
+	var documents []models.Document
+	query := db.Model(&models.Document{}).
+		Where("documents.type = ? AND documents.status = ?", docType, constant.DocumentStatusOpen)
+
+	if len(categoryIDs) > 0 || len(tagNames) > 0 {
+		matched := db.Model(&models.Document{}).
+			Joins("LEFT JOIN document_tag ON document_tag.document_id = documents.id AND document_tag.deleted_at IS NULL").
+			Joins("LEFT JOIN tags ON tags.id = document_tag.tag_id AND tags.deleted_at IS NULL").
+			Where("documents.type = ? AND documents.status = ?", docType, constant.DocumentStatusOpen)
+		if len(categoryIDs) > 0 && len(tagNames) > 0 {
+			matched = matched.Where("documents.category_id IN ? OR tags.tag_name IN ?", categoryIDs, tagNames)
+		} else if len(categoryIDs) > 0 {
+			matched = matched.Where("documents.category_id IN ?", categoryIDs)
+		} else {
+			matched = matched.Where("tags.tag_name IN ?", tagNames)
+		}
+		var matchedIDs []uint64
+		if err := matched.Distinct().Limit(limit).Pluck("documents.id", &matchedIDs).Error; err != nil {
+			return nil, err
+		}
+
+		var popularIDs []uint64
+		if err := query.Session(&gorm.Session{}).Order("read_counts DESC").Limit(limit).Pluck("id", &popularIDs).Error; err != nil {
+			return nil, err
+		}
+		query = query.Where("documents.id IN ?", append(matchedIDs, popularIDs...))
+	}
+
+	err := query.Order("read_counts DESC").Limit(2 * limit).Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// GetDocumentTagNamesByDocumentIDs 批量获取文档的标签名称
+func GetDocumentTagNamesByDocumentIDs(documentIDs []uint64) (map[uint64][]string, error) {
+	db := config.GetDB()
+	tagNames := make(map[uint64][]string)
+	if len(documentIDs) == 0 {
+		return tagNames, nil
+	}
+	var rows []struct {
+		DocumentID uint64
+		TagName    string
+	}
+	err := db.Table("document_tag").
+		Select("document_tag.document_id, tags.tag_name").
+		Joins("JOIN tags ON tags.id = document_tag.tag_id AND tags.deleted_at IS NULL").
+		Where("document_tag.document_id IN ? AND document_tag.deleted_at IS NULL", documentIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		tagNames[row.DocumentID] = append(tagNames[row.DocumentID], row.TagName)
+	}
+	return tagNames, nil
+}
+
+// GetDocumentsByIDsIgnoreStatus 根据一系列 ID 获取文档（不限状态），用于生成推荐理由等展示
+func GetDocumentsByIDsIgnoreStatus(ids []uint64) ([]models.Document, error) {
+	db := config.GetDB()
+	var documents []models.Document
+	if len(ids) == 0 {
+		return documents, nil
+	}
+	if err := db.Where("id IN ?", ids).Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// GetVisiblePostsByIDs 根据一系列 ID 获取未被隐藏的帖子
+func GetVisiblePostsByIDs(ids []uint64) ([]models.Post, error) {
+	db := config.GetDB()
+	var posts []models.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	if err := db.Where("id IN ? AND is_hidden = ?", ids, false).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetRecommendCandidatePosts 获取推荐候选帖子：关联了给定分类文档的帖子，以及点赞、收藏、评论最多的帖子
+func GetRecommendCandidatePosts(categoryIDs []uint64, limit int) ([]models.Post, error) {
+	db := config.GetDB()
+	var posts []models.Post
+	query := db.Model(&models.Post{}).Where("posts.is_hidden = ?", false)
+
+	if len(categoryIDs) > 0 {
+		var matchedIDs []uint64
+		err := db.Table("post_documents").
+			Joins("JOIN documents ON documents.id = post_documents.document_id AND documents.deleted_at IS NULL").
+			Where("post_documents.deleted_at IS NULL AND documents.category_id IN ?", categoryIDs).
+			Distinct().Limit(limit).Pluck("post_documents.post_id", &matchedIDs).Error
+		if err != nil {
+			return nil, err
+		}
+
+		var popularIDs []uint64
+		err = query.Session(&gorm.Session{}).Order("like_count + collect_count + comment_count DESC").
+			Limit(limit).Pluck("id", &popularIDs).Error
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("posts.id IN ?", append(matchedIDs, popularIDs...))
+	}
+
+	err := query.Order("like_count + collect_count + comment_count DESC").Limit(2 * limit).Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetPostDocumentCategoryIDs 批量获取帖子关联文档所属的分类
+func GetPostDocumentCategoryIDs(postIDs []uint64) (map[uint64][]uint64, error) {
+	db := config.GetDB()
+	categoryIDs := make(map[uint64][]uint64)
+	if len(postIDs) == 0 {
+		return categoryIDs, nil
+	}
+	var rows []struct {
+		PostID     uint64
+		CategoryID uint64
+	}
+	err := db.Table("post_documents").
+		Select("DISTINCT post_documents.post_id, documents.category_id").
+		Joins("JOIN documents ON documents.id = post_documents.document_id AND documents.deleted_at IS NULL").
+		Where("post_documents.post_id IN ? AND post_documents.deleted_at IS NULL", postIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		categoryIDs[row.PostID] = append(categoryIDs[row.PostID], row.CategoryID)
+	}
+	return categoryIDs, nil
+}
+
+// GetActiveInteractionUserIDs 获取指定时间之后有过交互（浏览、收藏、点赞、评论、上传或发帖）的用户，
+// 最近活跃的在前，最多 RecommendActiveUserLimit 个
+func GetActiveInteractionUserIDs(since time.Time) ([]uint64, error) {
+	db := config.GetDB()
+	parts := make([]string, 0, len(interactionSources))
+	args := make([]interface{}, 0, len(interactionSources)*2+1)
+	for _, source := range interactionSources {
+		parts = append(parts, fmt.Sprintf("(SELECT %s AS user_id, MAX(%s) AS active_at FROM %s WHERE deleted_at IS NULL AND %s >= ? "+
+			"GROUP BY %s ORDER BY active_at DESC LIMIT ?)", source.userCol, source.timeCol, source.table, source.timeCol, source.userCol))
+		args = append(args, since, constant.RecommendActiveUserLimit)
+	}
+	args = append(args, constant.RecommendActiveUserLimit)
+
+	var userIDs []uint64
+	err := db.Raw("SELECT i.user_id FROM ("+strings.Join(parts, " UNION ALL ")+") AS i "+
+		"GROUP BY i.user_id ORDER BY MAX(i.active_at) DESC LIMIT ?", args...).
+		Scan(&userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
@@ -0,0 +1,125 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// RecommendVariantStats 实验中一个变体的曝光、点击和收藏统计
+type RecommendVariantStats struct {
+	Variant     string
+	Users       int64
+	Impressions int64
+	Clicks      int64
+	Conversions int64
+}
+
+// CreateRecommendExperiment 创建推荐实验
+func CreateRecommendExperiment(experiment *models.RecommendExperiment) error {
+	db := config.GetDB()
+	return db.Create(experiment).Error
+}
+
+// GetRecommendExperimentByID 根据 ID 获取推荐实验
+func GetRecommendExperimentByID(id uint64) (models.RecommendExperiment, error) {
+	db := config.GetDB()
+	var experiment models.RecommendExperiment
+	err := db.First(&experiment, id).Error
+	return experiment, err
+}
+
+// GetRecommendExperiments 获取全部推荐实验，按创建时间倒序
+func GetRecommendExperiments() ([]models.RecommendExperiment, error) {
+	db := config.GetDB()
+	var experiments []models.RecommendExperiment
+	if err := db.Order("created_at DESC").Find(&experiments).Error; err != nil {
+		return nil, err
+	}
+	return experiments, nil
+}
+
+// GetRunningRecommendExperiment 获取正在进行的推荐实验，没有时返回 nil
+func GetRunningRecommendExperiment() (*models.RecommendExperiment, error) {
+	db := config.GetDB()
+	var experiment models.RecommendExperiment
+	err := db.Where("status = ?", constant.RecommendExperimentRunning).First(&experiment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &experiment, nil
+}
+
+// UpdateRecommendExperiment 更新推荐实验
+func UpdateRecommendExperiment(experiment *models.RecommendExperiment) error {
+	db := config.GetDB()
+	return db.Save(experiment).Error
+}
+
+// CreateRecommendImpressions 批量记录推荐曝光
+func CreateRecommendImpressions(impressions []models.RecommendImpression) error {
+	if len(impressions) == 0 {
+		return nil
+	}
+	db := config.GetDB()
+	return db.Create(&impressions).Error
+}
+
+// markLatestRecommendImpression 将用户在归因窗口内对该内容最近一次尚未标记的曝光标记为已点击或已收藏
+func markLatestRecommendImpression(column string, userID uint64, itemType string, itemID uint64) error {
+	db := config.GetDB()
+	since := time.Now().Add(-constant.RecommendAttributionWindowHours * time.Hour)
+	var impression models.RecommendImpression
+	err := db.Where("user_id = ? AND item_type = ? AND item_id = ? AND created_at >= ? AND "+column+" IS NULL",
+		userID, itemType, itemID, since).
+		Order("created_at DESC").
+		First(&impression).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return db.Model(&impression).Update(column, time.Now()).Error
+}
+
+// MarkRecommendImpressionClicked 用户浏览内容时，将其最近一次曝光记为点击
+func MarkRecommendImpressionClicked(userID uint64, itemType string, itemID uint64) error {
+	return markLatestRecommendImpression("clicked_at", userID, itemType, itemID)
+}
+
+// MarkRecommendImpressionFavorited 用户收藏内容时，将其最近一次曝光记为转化
+func MarkRecommendImpressionFavorited(userID uint64, itemType string, itemID uint64) error {
+	return markLatestRecommendImpression("favorited_at", userID, itemType, itemID)
+}
+
+// GetRecommendVariantStats 按变体统计实验的曝光用户数、曝光数、点击数和收藏数
+func GetRecommendVariantStats(experimentID uint64) ([]RecommendVariantStats, error) {
+	db := config.GetDB()
+	var stats []RecommendVariantStats
+	err := db.Model(&models.RecommendImpression{}).
+		Select("variant, COUNT(DISTINCT user_id) AS users, COUNT(*) AS impressions, "+
+			"COUNT(clicked_at) AS clicks, COUNT(favorited_at) AS conversions").
+		Where("experiment_id = ?", experimentID).
+		Group("variant").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// RecommendExperimentNameExists 检查实验名称是否已被其他实验使用
+func RecommendExperimentNameExists(name string, excludeID uint64) (bool, error) {
+	db := config.GetDB()
+	var count int64
+	err := db.Model(&models.RecommendExperiment{}).Where("name = ? AND id <> ?", name, excludeID).Count(&count).Error
+	return count > 0, err
+}
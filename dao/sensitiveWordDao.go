@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"gorm.io/gorm"
+)
+
+// GetAllSensitiveWords 获取全部敏感词
+func GetAllSensitiveWords() ([]models.SensitiveWord, error) {
+	db := config.GetDB()
+	var words []models.SensitiveWord
+	err := db.Find(&words).Error
+	return words, err
+}
+
+// SearchSensitiveWords 按分类和关键词筛选敏感词（为空表示不筛选）
+func SearchSensitiveWords(category, keyword string) ([]models.SensitiveWord, error) {
+	db := config.GetDB()
+	var words []models.SensitiveWord
+	query := db.Model(&models.SensitiveWord{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if keyword != "" {
+		query = query.Where("word LIKE ?", "%"+keyword+"%")
+	}
+	err := query.Order("created_at DESC").Find(&words).Error
+	return words, err
+}
+
+// CreateSensitiveWords 批量添加敏感词，已存在的词改为新的分类，返回新增的数量
+func CreateSensitiveWords(words []string, category string, creatorID uint64) (int, error) {
+	db := config.GetDB()
+	created := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, word := range words {
+			var existing models.SensitiveWord
+			err := tx.Where("word = ?", word).First(&existing).Error
+			if err == nil {
+				if err := tx.Model(&existing).Update("category", category).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err := tx.Create(&models.SensitiveWord{Word: word, Category: category, CreatorID: creatorID}).Error; err != nil {
+				return err
+			}
+			created++
+		}
+		return nil
+	})
+	return created, err
+}
+
+// DeleteSensitiveWord 删除敏感词
+func DeleteSensitiveWord(id uint64) (int64, error) {
+	db := config.GetDB()
+	result := db.Delete(&models.SensitiveWord{}, id)
+	return result.RowsAffected, result.Error
+}
+
+// GetSensitiveWordCategories 获取全部敏感词分类
+func GetSensitiveWordCategories() ([]models.SensitiveWordCategory, error) {
+	db := config.GetDB()
+	var categories []models.SensitiveWordCategory
+	err := db.Order("name").Find(&categories).Error
+	return categories, err
+}
+
+// SaveSensitiveWordCategory 创建或更新敏感词分类（按名称）
+func SaveSensitiveWordCategory(category *models.SensitiveWordCategory) error {
+	db := config.GetDB()
+	var existing models.SensitiveWordCategory
+	err := db.Where("name = ?", category.Name).First(&existing).Error
+	if err == nil {
+		existing.Action = category.Action
+		existing.Description = category.Description
+		if err := db.Save(&existing).Error; err != nil {
+			return err
+		}
+		*category = existing
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return db.Create(category).Error
+}
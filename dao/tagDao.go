@@ -1 +1,20 @@
 package dao
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/models"
+)
+
+// GetPopularTagNames 获取关联文档最多的标签名称（用于让 AI 从已有标签中挑选）
+func GetPopularTagNames(limit int) ([]string, error) {
+	db := config.GetDB()
+	var names []string
+	err := db.Model(&models.Tag{}).
+		Select("tags.tag_name").
+		Joins("LEFT JOIN document_tag ON document_tag.tag_id = tags.id AND document_tag.deleted_at IS NULL").
+		Group("tags.id, tags.tag_name").
+		Order("COUNT(document_tag.id) DESC").
+		Limit(limit).
+		Pluck("tags.tag_name", &names).Error
+	return names, err
+}
@@ -95,3 +95,17 @@ func GetUsersList() ([]models.User, error) {
 	}
 	return users, nil
 }
+
+// GetUsersByIDs 根据一系列 ID 批量获取用户（不过滤状态）
+func GetUsersByIDs(ids []uint64) ([]models.User, error) {
+	db := config.GetDB()
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := db.Where("id IN ?", ids).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
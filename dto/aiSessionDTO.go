@@ -1,7 +1,8 @@
 package dto
 
 type CreateAISessionDTO struct {
-	UserID uint64 `json:"userId" binding:"required"`
+	UserID     uint64  `json:"userId" binding:"required"`
+	CategoryID *uint64 `json:"categoryId"` // 课程分类ID（可选），传入时创建该课程的助教会话
 }
 
 type UpdateAISessionDTO struct {
@@ -19,3 +20,24 @@ type SendMessageRequest struct {
 	Content string `json:"question" binding:"required"`
 	IsThink bool   `json:"isThink"`
 }
+
+// SearchAIMessagesDTO 搜索AI对话记录接口的请求参数
+type SearchAIMessagesDTO struct {
+	Key      string `form:"key" binding:"required"` // 搜索关键词
+	Page     int    `form:"page"`                   // 页码 (默认为1)
+	PageSize int    `form:"pageSize"`               // 每页数量 (默认为10)
+}
+
+// AIMessageFeedbackDTO 用户评价AI回答接口的请求参数
+type AIMessageFeedbackDTO struct {
+	UserID uint64 `json:"userId" binding:"required"`
+	Rating string `json:"rating" binding:"required,oneof=up down"` // up-点赞，down-点踩
+	Reason string `json:"reason"`                                  // 评价原因（可选）
+}
+
+// AIFeedbackStatsDTO 管理员查看AI回答质量统计的请求参数
+type AIFeedbackStatsDTO struct {
+	StartDate string `form:"startDate"` // 开始日期 (格式 2006-01-02，默认为30天前)
+	EndDate   string `form:"endDate"`   // 结束日期 (格式 2006-01-02，包含当天，默认为今天)
+	Limit     int    `form:"limit"`     // 差评回答数量 (默认为10)
+}
@@ -19,3 +19,9 @@ type ModifyCategoryDTO struct {
 	ParentID    *uint64 `form:"parentId,omitempty"`    // 父分类ID（可选）
 
 }
+
+// UpdateCoursePersonaDTO 管理员修改课程助教设定的请求参数
+type UpdateCoursePersonaDTO struct {
+	Persona  string `json:"persona"`  // 助教人设（语气、教学风格等）
+	Syllabus string `json:"syllabus"` // 课程大纲，为空时根据课程下的子分类和资料自动生成
+}
@@ -11,7 +11,7 @@ type UploadDTO struct {
 	CategoryID uint64 `form:"categoryId" binding:"required"`
 	// 上传的资料类型
 	Type         string  `form:"type" binding:"required"`
-	Name         string  `form:"name" binding:"required"`
+	Name         string  `form:"name"` // 书籍提供了 ISBN 时可以不填，根据 ISBN 补全
 	ISBN         *string `form:"ISBN,omitempty"`
 	Introduction *string `form:"introduction,omitempty"`
 	// 关键词
@@ -23,6 +23,10 @@ type UploadDTO struct {
 	CreateYear *string `form:"createYear,omitempty"`
 	// 上传者
 	UploaderID uint64 `form:"uploaderId" binding:"required"`
+	// 分片上传或直传完成后的文件路径，与 file 二选一
+	ObjectKey *string `form:"objectKey,omitempty"`
+	// 出版社
+	Publisher *string `form:"publisher,omitempty" binding:"omitempty,max=200"`
 }
 type WithdrawUploadDTO struct {
 	DocumentID uint64 `form:"documentId" binding:"required"`
@@ -43,6 +47,13 @@ type ModifyDocumentDTO struct {
 	Tags         string  `form:"tags,omitempty"` // 接收 JSON 字符串，需要手动解析为 []string
 	Type         *string `form:"type,omitempty"`
 	Introduction *string `form:"introduction,omitempty"`
+	Difficulty   *string `form:"difficulty,omitempty"` // 难度：beginner、intermediate、advanced
+	// 要采纳的 AI 元数据建议字段，逗号分隔（introduction、tags、category、difficulty），all 表示全部采纳；显式传入的字段优先
+	AcceptSuggestions string `form:"acceptSuggestions,omitempty"`
+	// 替换文件或封面时的版本说明
+	ChangeNote string `form:"changeNote,omitempty" binding:"max=500"`
+	// 出版社
+	Publisher *string `form:"publisher,omitempty" binding:"omitempty,max=200"`
 }
 type SearchDocumentDTO struct {
 	// 筛选科目
@@ -55,6 +66,14 @@ type SearchDocumentDTO struct {
 	Type *string `form:"type,omitempty"`
 	// 筛选创作时间
 	Year *string `form:"year,omitempty"`
+	// 按文件元数据筛选：页数范围（页数未知的文档不参与页数筛选）、正文语言（如 zh、en）、文件类型
+	MinPages *int    `form:"minPages,omitempty" binding:"omitempty,min=1"`
+	MaxPages *int    `form:"maxPages,omitempty" binding:"omitempty,min=1"`
+	Language *string `form:"language,omitempty"`
+	MimeType *string `form:"mimeType,omitempty"`
+
+	// 排序方式：rating 按评分从高到低，不传时保持原有顺序
+	Sort string `form:"sort" binding:"omitempty,oneof=rating"`
 }
 type AdminModifyDocumentStatusRequest struct {
 	DocumentID uint64  `json:"documentId"`
@@ -79,4 +98,7 @@ type DocumentBriefDTO struct {
 type GetDocumentListDTO struct {
 	IsSuggest  *bool   `form:"is_suggest"` // 是否为推荐模式
 	CategoryID *uint64 `form:"categoryId"` // 分类ID (可选，空则返回全部)
+
+	// 排序方式：rating 按评分从高到低，不传时保持原有顺序
+	Sort string `form:"sort" binding:"omitempty,oneof=rating"`
 }
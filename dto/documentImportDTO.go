@@ -0,0 +1,13 @@
+package dto
+
+import "mime/multipart"
+
+// ImportDocumentsDTO 管理员批量导入文档
+type ImportDocumentsDTO struct {
+	// 包含全部文档文件的 ZIP 压缩包
+	Archive *multipart.FileHeader `form:"archive" binding:"required"`
+	// CSV 或 JSON 清单，不上传时使用压缩包根目录的 manifest.csv 或 manifest.json
+	Manifest *multipart.FileHeader `form:"manifest,omitempty"`
+	// 试运行：只校验清单和文件，不创建文档
+	DryRun bool `form:"dryRun"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// RollbackDocumentVersionDTO 回滚文档到指定版本
+type RollbackDocumentVersionDTO struct {
+	Note string `json:"note" binding:"max=500"` // 版本说明，为空时使用默认说明
+}
+
+// PruneDocumentVersionsDTO 按保留策略清理文档旧版本
+type PruneDocumentVersionsDTO struct {
+	DryRun bool `json:"dryRun"` // 只统计将被清理的版本，不实际删除
+}
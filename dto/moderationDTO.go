@@ -0,0 +1,13 @@
+package dto
+
+// GetModerationQueueDTO 管理员获取内容审核队列的查询参数
+type GetModerationQueueDTO struct {
+	Status      string `form:"status"`      // 审核状态，逗号分隔，默认为待复核（review、hidden）
+	ContentType string `form:"contentType"` // document、post、comment、message，为空表示全部
+}
+
+// ReviewModerationDTO 管理员人工复核审核记录
+type ReviewModerationDTO struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"` // approve 通过（恢复显示），reject 驳回（隐藏或关闭内容）
+	Note     string `json:"note" binding:"max=500"`
+}
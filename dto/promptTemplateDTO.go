@@ -0,0 +1,21 @@
+package dto
+
+// CreatePromptTemplateDTO 管理员新增提示词版本的请求参数
+type CreatePromptTemplateDTO struct {
+	Content     string `json:"content" binding:"required"`
+	Description string `json:"description"`
+	Activate    bool   `json:"activate"` // 是否立即启用该版本
+}
+
+// PreviewPromptTemplateDTO 管理员预览提示词渲染结果的请求参数
+// Content 非空时预览该内容；否则预览 Version 指定的版本；都不传时预览当前生效的提示词
+type PreviewPromptTemplateDTO struct {
+	Content   string            `json:"content"`
+	Version   *int              `json:"version"`
+	Variables map[string]string `json:"variables"`
+}
+
+// RollbackPromptTemplateDTO 管理员回滚提示词的请求参数，version 为 0 表示回退到内置提示词
+type RollbackPromptTemplateDTO struct {
+	Version *int `json:"version" binding:"required,min=0"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+// GenerateQuizDTO 根据文档生成测验接口的请求参数，数量均为 0 时使用默认数量
+type GenerateQuizDTO struct {
+	ChoiceCount      int `json:"choiceCount" binding:"min=0,max=20"`      // 选择题数量
+	ShortAnswerCount int `json:"shortAnswerCount" binding:"min=0,max=10"` // 简答题数量
+	FlashcardCount   int `json:"flashcardCount" binding:"min=0,max=30"`   // 记忆卡片数量
+	StartPage        int `json:"startPage" binding:"min=0"`               // 出题起始页（可选，默认从第一页开始）
+	EndPage          int `json:"endPage" binding:"min=0"`                 // 出题结束页（可选）
+}
+
+// SaveQuizDTO 保存测验接口的请求参数，题目为生成接口返回的内容
+type SaveQuizDTO struct {
+	Title      string             `json:"title" binding:"required,max=100"`
+	StartPage  int                `json:"startPage" binding:"required,min=1"`
+	EndPage    int                `json:"endPage" binding:"required,min=1"`
+	Questions  []QuizQuestionDTO  `json:"questions"`
+	Flashcards []QuizFlashcardDTO `json:"flashcards"`
+}
+
+// QuizQuestionDTO 测验题目
+type QuizQuestionDTO struct {
+	Type        string   `json:"type"`
+	Question    string   `json:"question"`
+	Options     []string `json:"options"`
+	Answer      string   `json:"answer"`
+	Explanation string   `json:"explanation"`
+	SourcePages []int    `json:"sourcePages"`
+}
+
+// QuizFlashcardDTO 记忆卡片
+type QuizFlashcardDTO struct {
+	Front       string `json:"front"`
+	Back        string `json:"back"`
+	SourcePages []int  `json:"sourcePages"`
+}
+
+// SubmitQuizAttemptDTO 提交答题接口的请求参数
+type SubmitQuizAttemptDTO struct {
+	Answers []QuizAnswerDTO `json:"answers" binding:"required,dive"`
+}
+
+// QuizAnswerDTO 单道题的作答
+type QuizAnswerDTO struct {
+	QuestionIndex int    `json:"questionIndex" binding:"min=0"` // 题目下标，从 0 开始
+	Answer        string `json:"answer"`                        // 选择题为选项字母，简答题为作答内容
+	SelfCorrect   bool   `json:"selfCorrect"`                   // 简答题由用户对照参考答案自评是否答对
+}
@@ -0,0 +1,43 @@
+package dto
+
+// SyncReadingProgressDTO 阅读器同步阅读进度
+type SyncReadingProgressDTO struct {
+	Page     int     `json:"page" binding:"min=0"`            // 当前页码（从 1 开始，没有页码的文档为 0）
+	Position string  `json:"position" binding:"max=255"`      // 阅读器中的精确位置（如 EPUB CFI、滚动位置、视频秒数）
+	Percent  float64 `json:"percent" binding:"min=0,max=100"` // 阅读进度百分比
+	ReadAt   int64   `json:"readAt" binding:"min=0"`          // 阅读器记录进度的时间（毫秒时间戳），不传时为服务器当前时间
+}
+
+// CreateBookmarkDTO 添加书签
+type CreateBookmarkDTO struct {
+	Name     string `json:"name" binding:"max=100"` // 不填时按页码命名
+	Page     int    `json:"page" binding:"min=0"`
+	Position string `json:"position" binding:"max=255"`
+}
+
+// UpdateBookmarkDTO 重命名书签
+type UpdateBookmarkDTO struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// CreateHighlightDTO 添加划线，按页码和页内字符偏移定位
+type CreateHighlightDTO struct {
+	Page        int    `json:"page" binding:"min=0"`
+	StartOffset int    `json:"startOffset" binding:"min=0"`
+	EndOffset   int    `json:"endOffset" binding:"gtfield=StartOffset"` // 不含结束位置
+	Text        string `json:"text" binding:"required,max=1000"`
+	Note        string `json:"note" binding:"max=2000"`
+	Color       string `json:"color"` // 不填时为 yellow
+}
+
+// UpdateHighlightDTO 修改划线的笔记和颜色，只更新传入的字段
+type UpdateHighlightDTO struct {
+	Note  *string `json:"note" binding:"omitempty,max=2000"`
+	Color *string `json:"color"`
+}
+
+// GetContinueReadingDTO 继续阅读列表的分页参数
+type GetContinueReadingDTO struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"pageSize" binding:"omitempty,min=1,max=50"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// GetRecommendationsDTO 获取个性化推荐的查询参数
+type GetRecommendationsDTO struct {
+	Type  string `form:"type" binding:"required,oneof=book file video post"` // 推荐内容类型
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`             // 推荐条数，默认 10
+}
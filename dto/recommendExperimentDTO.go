@@ -0,0 +1,20 @@
+package dto
+
+// RecommendVariantDTO 推荐实验的一个变体
+type RecommendVariantDTO struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	Strategy string `json:"strategy" binding:"required,oneof=vector_only llm_rerank popularity hybrid"`
+	Weight   int    `json:"weight" binding:"required,min=1,max=100"` // 流量权重，按各变体权重占比分配用户
+}
+
+// SaveRecommendExperimentDTO 创建或修改推荐实验
+type SaveRecommendExperimentDTO struct {
+	Name        string                `json:"name" binding:"required,max=100"`
+	Description string                `json:"description" binding:"max=500"`
+	Variants    []RecommendVariantDTO `json:"variants" binding:"required,min=2,dive"`
+}
+
+// UpdateRecommendExperimentStatusDTO 开始或结束推荐实验
+type UpdateRecommendExperimentStatusDTO struct {
+	Status string `json:"status" binding:"required,oneof=running stopped"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// SaveReviewDTO 评价文档，已评价过时修改原评价
+type SaveReviewDTO struct {
+	Stars   int    `json:"stars" binding:"required,min=1,max=5"`
+	Content string `json:"content" binding:"max=2000"` // 可以只评分不写评价
+}
+
+// GetReviewsDTO 评价列表的分页和排序参数
+type GetReviewsDTO struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" binding:"omitempty,min=1,max=50"`
+	Sort     string `form:"sort" binding:"omitempty,oneof=helpful latest"` // 默认 helpful
+}
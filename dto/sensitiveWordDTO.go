@@ -0,0 +1,25 @@
+package dto
+
+// GetSensitiveWordsDTO 管理员查询敏感词的查询参数
+type GetSensitiveWordsDTO struct {
+	Category string `form:"category"`
+	Keyword  string `form:"keyword"`
+}
+
+// CreateSensitiveWordsDTO 管理员批量添加敏感词
+type CreateSensitiveWordsDTO struct {
+	Words    []string `json:"words" binding:"required,min=1"`
+	Category string   `json:"category" binding:"required,max=50"`
+}
+
+// SaveSensitiveWordCategoryDTO 管理员创建或修改敏感词分类的处理方式
+type SaveSensitiveWordCategoryDTO struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	Action      string `json:"action" binding:"required,oneof=mask reject flag"` // mask 打码，reject 拒绝提交，flag 放行并进入人工复核
+	Description string `json:"description" binding:"max=200"`
+}
+
+// TestSensitiveTextDTO 管理员测试一段文本的敏感词命中情况
+type TestSensitiveTextDTO struct {
+	Text string `json:"text" binding:"required"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// InitUploadSessionDTO 创建分片上传会话
+type InitUploadSessionDTO struct {
+	FileName    string `json:"fileName" binding:"required,max=255"`
+	FileSize    int64  `json:"fileSize" binding:"required,min=1"`
+	ContentType string `json:"contentType" binding:"max=100"`
+	CategoryID  uint64 `json:"categoryId" binding:"required"`
+	PartSize    int64  `json:"partSize" binding:"omitempty,min=1"`            // 分片大小（字节），不传时使用默认值
+	SHA256      string `json:"sha256" binding:"omitempty,len=64,hexadecimal"` // 整个文件的 SHA-256，传入时合并后校验
+}
+
+// PresignUploadDTO 获取直传到存储的签名地址
+type PresignUploadDTO struct {
+	FileName    string `json:"fileName" binding:"required,max=255"`
+	FileSize    int64  `json:"fileSize" binding:"required,min=1"`
+	ContentType string `json:"contentType" binding:"max=100"`
+	CategoryID  uint64 `json:"categoryId" binding:"required"`
+}
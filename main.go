@@ -1,7 +1,10 @@
 package main
 
 import (
+	"log"
+
 	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/controllers"
 	"github.com/antidote-kt/SSE_Library-back/router"
 	"github.com/antidote-kt/SSE_Library-back/utils"
 )
@@ -11,8 +14,20 @@ func main() {
 	config.InitDatabase()
 	config.InitRedis()
 	config.InitEmail()
+	if err := utils.InitBlobStore(); err != nil {
+		log.Fatalf("对象存储初始化失败: %v", err)
+	}
+	if err := utils.InitMalwareScanner(); err != nil {
+		log.Fatalf("文件扫描器初始化失败: %v", err)
+	}
+	if err := utils.InitBookCatalogue(); err != nil {
+		log.Fatalf("图书目录服务初始化失败: %v", err)
+	}
 	go utils.WSManager.Start()
 	utils.InitMilvus()
+	utils.InitSensitiveWordFilter(controllers.LoadSensitiveWordLexicon)
+	controllers.StartRecommendationJob()
+	controllers.RecoverDocumentImportJobs()
 	router := router.SetupRouter()
 	router.Run()
 }
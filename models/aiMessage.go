@@ -7,12 +7,15 @@ import (
 )
 
 type AIMessage struct {
-	ID           uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
-	AISessionsID uint64         `gorm:"column:ai_sessions_id;not null;index:idx_session_messages" json:"aiSessionsId"`
-	Role         string         `gorm:"size:20;not null" json:"role"`
-	Status          string         `gorm:"size:20;not null;default:generating" json:"status"`
-	Content         string         `gorm:"type:longtext;not null" json:"content"`
-	ThinkingContent string         `gorm:"type:longtext;column:thinking_content" json:"thinkingContent"`
-	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"createdAt"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	AISessionsID     uint64         `gorm:"column:ai_sessions_id;not null;index:idx_session_messages" json:"aiSessionsId"`
+	Role             string         `gorm:"size:20;not null" json:"role"`
+	Status           string         `gorm:"size:20;not null;default:generating" json:"status"`
+	Content          string         `gorm:"type:longtext;not null" json:"content"`
+	ThinkingContent  string         `gorm:"type:longtext;column:thinking_content" json:"thinkingContent"`
+	Model            string         `gorm:"size:50" json:"model"`                            // 生成该回答所用的模型（仅 assistant 消息）
+	PromptVersion    string         `gorm:"size:100" json:"promptVersion"`                   // 生成该回答所用的提示词版本（仅 assistant 消息）
+	RetrievalContext string         `gorm:"type:longtext;column:retrieval_context" json:"-"` // 生成该回答时检索到的知识片段（JSON 数组）
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AIMessageFeedback 用户对 AI 回答的评价（每个用户对每条回答仅保留一条，可修改）
+type AIMessageFeedback struct {
+	ID          uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	AIMessageID uint64         `gorm:"column:ai_message_id;not null;uniqueIndex:uk_message_user_feedback" json:"aiMessageId"`
+	UserID      uint64         `gorm:"not null;uniqueIndex:uk_message_user_feedback" json:"userId"`
+	Rating      string         `gorm:"size:10;not null" json:"rating"` // up-点赞，down-点踩
+	Reason      string         `gorm:"type:text" json:"reason"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime;index:idx_feedback_created_at" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -7,10 +7,11 @@ import (
 )
 
 type AISession struct {
-	ID        uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID    uint64         `gorm:"not null;index:idx_user_sessions" json:"userId"`
-	Title     string         `gorm:"size:255;default:'新对话'" json:"title"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime;index:idx_user_sessions" json:"updatedAt"`
-	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID         uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64         `gorm:"not null;index:idx_user_sessions" json:"userId"`
+	Title      string         `gorm:"size:255;default:'新对话'" json:"title"`
+	CategoryID *uint64        `gorm:"index" json:"categoryId"` // 从课程创建的会话对应的课程分类ID，为空表示通用图书馆助手会话
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime;index:idx_user_sessions" json:"updatedAt"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AISummary AI 生成的文档/帖子摘要，每次生成新增一条记录，同一内容同一风格的最新一条为当前摘要
+type AISummary struct {
+	ID            uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ContentType   string         `gorm:"type:varchar(20);not null;index:idx_summary_content" json:"contentType"` // document 或 post
+	ContentID     uint64         `gorm:"not null;index:idx_summary_content" json:"contentId"`
+	Style         string         `gorm:"type:varchar(20);not null;index:idx_summary_content" json:"style"` // abstract、key_points、outline、glossary
+	Summary       string         `gorm:"type:longtext;not null" json:"summary"`
+	SrcHash       string         `gorm:"type:varchar(64);not null" json:"-"` // 生成时正文的 hash，正文变化后需要重新生成
+	Model         string         `gorm:"size:100" json:"model"`
+	PromptVersion string         `gorm:"size:100" json:"promptVersion"`
+	CreatorID     uint64         `gorm:"not null" json:"creatorId"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
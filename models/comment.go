@@ -13,6 +13,7 @@ type Comment struct {
 	ParentID   *uint64        `gorm:"index:idx_parent_id" json:"parent_id"`
 	SourceID   uint64         `gorm:"not null;index:idx_source" json:"source_id"`
 	SourceType string         `gorm:"type:varchar(20);not null;default:'document';index:idx_source" json:"source_type"`
+	IsHidden   bool           `gorm:"default:false" json:"is_hidden"` // 内容审核判定为高风险时自动隐藏
 	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
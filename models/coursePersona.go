@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CoursePersona 课程助教设定，每个课程分类一条，由管理员维护
+type CoursePersona struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	CategoryID uint64         `gorm:"not null;uniqueIndex:uk_course_persona_category" json:"categoryId"`
+	Persona    string         `gorm:"type:text" json:"persona"`  // 助教人设（语气、教学风格等）
+	Syllabus   string         `gorm:"type:text" json:"syllabus"` // 课程大纲，为空时根据课程下的子分类和资料自动生成
+	UpdaterID  uint64         `gorm:"not null" json:"updaterId"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
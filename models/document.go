@@ -17,11 +17,34 @@ type Document struct {
 	Cover        string         `gorm:"type:varchar(500)" json:"cover"`
 	Introduction string         `gorm:"type:text" json:"introduction"`
 	CreateYear   string         `gorm:"type:varchar(10)" json:"create_year"`
+	Difficulty   string         `gorm:"type:varchar(20)" json:"difficulty"` // 难度：beginner、intermediate、advanced
 	Status       string         `gorm:"type:varchar(20);default:'audit'" json:"status"`
 	ReadCounts   int            `gorm:"default:0" json:"read_counts"`
 	Collections  int            `gorm:"default:0" json:"collections"`
 	URL          string         `gorm:"type:varchar(500);not null" json:"url"`
+	Version      int            `gorm:"default:1" json:"version"` // 当前文件对应的版本号（document_versions.version）
 	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 文件技术元数据：大小、类型和哈希在上传时记录，页数、字数、语言和 PDF 信息在学习文档时提取
+	FileSize     int64      `gorm:"default:0" json:"file_size"`
+	MimeType     string     `gorm:"type:varchar(100)" json:"mime_type"`
+	ContentHash  string     `gorm:"type:varchar(64)" json:"content_hash"`
+	PageCount    int        `gorm:"default:0;index:idx_page_count" json:"page_count"`
+	WordCount    int        `gorm:"default:0" json:"word_count"`
+	Language     string     `gorm:"type:varchar(10);index:idx_language" json:"language"`
+	PDFTitle     string     `gorm:"type:varchar(500)" json:"pdf_title"`
+	PDFAuthor    string     `gorm:"type:varchar(200)" json:"pdf_author"`
+	PDFCreatedAt *time.Time `json:"pdf_created_at"`
+
+	// 下载次数（获取下载地址的次数），与阅读量分开统计
+	DownloadCounts int `gorm:"default:0" json:"download_counts"`
+
+	// 出版社，书籍可根据 ISBN 从图书目录服务补全
+	Publisher string `gorm:"type:varchar(200)" json:"publisher"`
+
+	// 评分汇总，评价增删改时重新计算
+	RatingAverage float64 `gorm:"type:decimal(3,2);not null;default:0;index:idx_rating" json:"rating_average"`
+	RatingCount   int     `gorm:"not null;default:0;index:idx_rating" json:"rating_count"`
 }
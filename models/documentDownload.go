@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// DocumentDownload 文档下载记录：每次获取下载地址记录一条（同一用户短时间内重复获取只记一次），用于下载量统计
+type DocumentDownload struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	DocumentID  uint64    `gorm:"not null;index:idx_download_document" json:"documentId"`
+	UserID      uint64    `gorm:"not null;index:idx_download_user" json:"userId"`
+	Version     int       `gorm:"not null;default:1" json:"version"` // 下载时文档的版本号
+	Watermarked bool      `gorm:"not null;default:false" json:"watermarked"`
+	IP          string    `gorm:"type:varchar(64)" json:"ip"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index:idx_download_document" json:"createdAt"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DocumentMetadataSuggestion 文档上传后由 AI 根据正文生成的元数据建议，上传者或审核员可在修改文档时采纳
+type DocumentMetadataSuggestion struct {
+	ID                  uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	DocumentID          uint64         `gorm:"not null;uniqueIndex:uk_metadata_suggestion_document" json:"documentId"`
+	Introduction        string         `gorm:"type:text" json:"introduction"`
+	Tags                string         `gorm:"type:text" json:"tags"`    // 匹配到的已有标签（JSON 数组）
+	NewTags             string         `gorm:"type:text" json:"newTags"` // 建议新增的标签（JSON 数组），仅供参考
+	SuggestedCategoryID *uint64        `json:"suggestedCategoryId"`      // 建议分类，为空表示没有合适的分类
+	Difficulty          string         `gorm:"type:varchar(20)" json:"difficulty"`
+	Status              string         `gorm:"type:varchar(20);not null;default:'pending'" json:"status"` // pending、accepted
+	AcceptedFields      string         `gorm:"type:varchar(100)" json:"acceptedFields"`                   // 已采纳的字段，逗号分隔
+	Model               string         `gorm:"size:100" json:"model"`
+	PromptVersion       string         `gorm:"size:100" json:"promptVersion"`
+	CreatedAt           time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt           time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// DocumentReview 用户对文档的评分和评价，每个用户每个文档一条，可以修改
+type DocumentReview struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       uint64    `gorm:"not null;uniqueIndex:idx_review_user_document" json:"userId"`
+	DocumentID   uint64    `gorm:"not null;uniqueIndex:idx_review_user_document;index:idx_review_document" json:"documentId"`
+	Stars        int       `gorm:"not null" json:"stars"`    // 1-5 星
+	Content      string    `gorm:"type:text" json:"content"` // 评价内容，可以只评分不写评价
+	HelpfulCount int       `gorm:"not null;default:0" json:"helpfulCount"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// ReviewHelpfulVote 用户认为评价“有帮助”的投票，每个用户对每条评价只能投一次
+type ReviewHelpfulVote struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ReviewID  uint64    `gorm:"not null;uniqueIndex:idx_helpful_review_user" json:"reviewId"`
+	UserID    uint64    `gorm:"not null;uniqueIndex:idx_helpful_review_user" json:"userId"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
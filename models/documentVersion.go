@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DocumentVersion 文档文件的一个版本：上传、替换文件或封面、回滚时都会新增版本，旧文件一直保留，只按保留策略清理
+type DocumentVersion struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	DocumentID uint64         `gorm:"not null;uniqueIndex:uk_document_version" json:"documentId"`
+	Version    int            `gorm:"not null;uniqueIndex:uk_document_version" json:"version"` // 从 1 开始递增，清理后也不会复用
+	Type       string         `gorm:"type:varchar(20);not null" json:"type"`                   // 该版本的资源类型：book、file、video
+	URL        string         `gorm:"type:varchar(500);not null" json:"url"`                   // 文件在 COS 中的路径，视频为链接
+	Cover      string         `gorm:"type:varchar(500)" json:"cover"`
+	FileSize   int64          `gorm:"not null;default:0" json:"fileSize"` // 文件大小（字节），视频和早期数据为 0
+	FileHash   string         `gorm:"type:varchar(64)" json:"fileHash"`   // 文件 SHA-256，视频和早期数据为空
+	UploaderID uint64         `gorm:"not null" json:"uploaderId"`
+	ChangeNote string         `gorm:"type:varchar(500)" json:"changeNote"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"` // 按保留策略清理的版本
+}
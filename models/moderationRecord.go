@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ModerationRecord 内容审核记录：文档、帖子、评论和私信写入后由本地规则和 AI 预审，疑似违规的进入人工复核队列
+type ModerationRecord struct {
+	ID          uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	ContentType string         `gorm:"type:varchar(20);not null;uniqueIndex:uk_moderation_content" json:"contentType"` // document、post、comment、message
+	ContentID   uint64         `gorm:"not null;uniqueIndex:uk_moderation_content" json:"contentId"`
+	AuthorID    uint64         `gorm:"not null;index" json:"authorId"`
+	RiskScore   float64        `gorm:"not null;default:0" json:"riskScore"`
+	Categories  string         `gorm:"type:varchar(100)" json:"categories"` // 命中的风险类别，逗号分隔
+	Reason      string         `gorm:"type:varchar(500)" json:"reason"`
+	RuleHits    string         `gorm:"type:text" json:"ruleHits"` // 命中的本地规则（JSON 数组）
+	AIChecked   bool           `gorm:"not null;default:false" json:"aiChecked"`
+	Status      string         `gorm:"type:varchar(20);not null;index" json:"status"` // pass、review、hidden、approved、rejected
+	ReviewerID  *uint64        `json:"reviewerId"`
+	ReviewNote  string         `gorm:"type:varchar(500)" json:"reviewNote"`
+	ReviewedAt  *time.Time     `json:"reviewedAt"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
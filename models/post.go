@@ -14,6 +14,7 @@ type Post struct {
 	LikeCount    uint32         `gorm:"default:0" json:"like_count"`
 	CollectCount uint32         `gorm:"default:0" json:"collect_count"`
 	CommentCount uint32         `gorm:"default:0" json:"comment_count"`
+	IsHidden     bool           `gorm:"default:false" json:"is_hidden"` // 内容审核判定为高风险时自动隐藏
 	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 }
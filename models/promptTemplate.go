@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PromptTemplate 提示词模板（同一标识可有多个版本，同一时刻只有一个版本处于启用状态）
+type PromptTemplate struct {
+	ID          uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	TemplateKey string         `gorm:"size:50;not null;uniqueIndex:uk_prompt_key_version" json:"templateKey"`
+	Version     int            `gorm:"not null;uniqueIndex:uk_prompt_key_version" json:"version"`
+	Content     string         `gorm:"type:text;not null" json:"content"`
+	Description string         `gorm:"size:255" json:"description"` // 版本说明（修改了什么）
+	IsActive    bool           `gorm:"not null;default:0" json:"isActive"`
+	CreatorID   uint64         `gorm:"not null" json:"creatorId"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Quiz 用户保存的测验（由 AI 根据文档生成），仅保存者本人可见
+type Quiz struct {
+	ID             uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	DocumentID     uint64         `gorm:"not null;index:idx_quiz_user_document" json:"documentId"`
+	UserID         uint64         `gorm:"not null;index:idx_quiz_user_document" json:"userId"`
+	Title          string         `gorm:"type:varchar(100);not null" json:"title"`
+	StartPage      int            `gorm:"not null" json:"startPage"` // 出题素材的页码范围
+	EndPage        int            `gorm:"not null" json:"endPage"`
+	Content        string         `gorm:"type:longtext;not null" json:"-"` // 题目和记忆卡片（JSON）
+	QuestionCount  int            `gorm:"not null" json:"questionCount"`
+	FlashcardCount int            `gorm:"not null" json:"flashcardCount"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// QuizAttempt 用户的一次答题记录
+type QuizAttempt struct {
+	ID           uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	QuizID       uint64         `gorm:"not null;index" json:"quizId"`
+	UserID       uint64         `gorm:"not null;index:idx_attempt_user_document" json:"userId"`
+	DocumentID   uint64         `gorm:"not null;index:idx_attempt_user_document" json:"documentId"`
+	Answers      string         `gorm:"type:longtext" json:"-"` // 每道题的作答与判分结果（JSON）
+	CorrectCount int            `gorm:"not null" json:"correctCount"`
+	TotalCount   int            `gorm:"not null" json:"totalCount"`
+	Score        float64        `gorm:"not null" json:"score"` // 百分制得分
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReadingProgress 用户在文档中的阅读进度，每个用户每个文档一条，由阅读器同步
+type ReadingProgress struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64    `gorm:"not null;uniqueIndex:idx_reading_user_document;index:idx_reading_user_read_at" json:"userId"`
+	DocumentID uint64    `gorm:"not null;uniqueIndex:idx_reading_user_document" json:"documentId"`
+	Page       int       `gorm:"not null;default:0" json:"page"`               // 当前页码，没有页码的文档为 0
+	Position   string    `gorm:"type:varchar(255)" json:"position"`            // 阅读器中的精确位置（如 EPUB CFI、滚动位置、视频秒数）
+	Percent    float64   `gorm:"not null;default:0" json:"percent"`            // 阅读进度百分比
+	ReadAt     time.Time `gorm:"index:idx_reading_user_read_at" json:"readAt"` // 阅读器记录进度的时间，多端同步时以此判断新旧
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// ReadingBookmark 用户在文档中添加的书签，仅本人可见
+type ReadingBookmark struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64         `gorm:"not null;index:idx_bookmark_user_document" json:"userId"`
+	DocumentID uint64         `gorm:"not null;index:idx_bookmark_user_document" json:"documentId"`
+	Name       string         `gorm:"type:varchar(100);not null" json:"name"`
+	Page       int            `gorm:"not null;default:0" json:"page"`
+	Position   string         `gorm:"type:varchar(255)" json:"position"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ReadingHighlight 用户在文档中划线的文本及笔记，按页码和页内字符偏移定位，仅本人可见
+type ReadingHighlight struct {
+	ID          uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      uint64         `gorm:"not null;index:idx_highlight_user_document" json:"userId"`
+	DocumentID  uint64         `gorm:"not null;index:idx_highlight_user_document" json:"documentId"`
+	Page        int            `gorm:"not null;default:0" json:"page"`
+	StartOffset int            `gorm:"not null" json:"startOffset"` // 划线在页内的起止字符偏移，不含结束位置
+	EndOffset   int            `gorm:"not null" json:"endOffset"`
+	Text        string         `gorm:"type:text;not null" json:"text"` // 划线的文本
+	Note        string         `gorm:"type:text" json:"note"`
+	Color       string         `gorm:"type:varchar(20);not null" json:"color"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecommendExperiment 书籍推荐 A/B 实验：按用户 ID 哈希将用户稳定地分配到各个策略变体
+type RecommendExperiment struct {
+	ID          uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string         `gorm:"type:varchar(100);not null;uniqueIndex:uk_recommend_experiment_name" json:"name"`
+	Description string         `gorm:"type:varchar(500)" json:"description"`
+	Variants    string         `gorm:"type:text;not null" json:"variants"`                      // 变体列表（JSON 数组），包含名称、策略和流量权重
+	Status      string         `gorm:"type:varchar(20);not null;default:'draft'" json:"status"` // draft、running、stopped
+	StartedAt   *time.Time     `json:"startedAt"`
+	StoppedAt   *time.Time     `json:"stoppedAt"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// RecommendImpression 推荐曝光记录：推荐结果展示给用户时记录，之后的浏览和收藏归因到曝光上
+type RecommendImpression struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ExperimentID uint64     `gorm:"not null;index:idx_impression_experiment" json:"experimentId"`
+	Variant      string     `gorm:"type:varchar(50);not null;index:idx_impression_experiment" json:"variant"`
+	UserID       uint64     `gorm:"not null;index:idx_impression_user_item" json:"userId"`
+	ItemType     string     `gorm:"type:varchar(20);not null;index:idx_impression_user_item" json:"itemType"` // document、post
+	ItemID       uint64     `gorm:"not null;index:idx_impression_user_item" json:"itemId"`
+	Position     int        `gorm:"not null" json:"position"` // 在推荐列表中的位置，从 1 开始
+	ClickedAt    *time.Time `json:"clickedAt"`                // 曝光后浏览该内容的时间
+	FavoritedAt  *time.Time `json:"favoritedAt"`              // 曝光后收藏该内容的时间
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SensitiveWord 敏感词（已规范化为小写并去掉空白和标点）
+type SensitiveWord struct {
+	ID        uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	Word      string         `gorm:"type:varchar(100);not null;uniqueIndex:uk_sensitive_word" json:"word"`
+	Category  string         `gorm:"type:varchar(50);not null;index" json:"category"`
+	CreatorID uint64         `gorm:"not null" json:"creatorId"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// SensitiveWordCategory 敏感词分类及其处理方式
+type SensitiveWordCategory struct {
+	ID          uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string         `gorm:"type:varchar(50);not null;uniqueIndex:uk_sensitive_word_category" json:"name"`
+	Action      string         `gorm:"type:varchar(20);not null" json:"action"` // mask、reject、flag
+	Description string         `gorm:"type:varchar(200)" json:"description"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,122 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+)
+
+// AIMessageFeedbackResponse 用户评价AI回答后的返回数据
+type AIMessageFeedbackResponse struct {
+	FeedbackID  uint64 `json:"feedbackId"`
+	AIMessageID uint64 `json:"aiMessageId"`
+	Rating      string `json:"rating"`
+	Reason      string `json:"reason"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+// AIFeedbackDailyStatResponse 按天、模型、提示词版本聚合的反馈统计
+type AIFeedbackDailyStatResponse struct {
+	Day              string  `json:"day"`
+	Model            string  `json:"model"`
+	PromptVersion    string  `json:"promptVersion"`
+	UpCount          int64   `json:"upCount"`
+	DownCount        int64   `json:"downCount"`
+	SatisfactionRate float64 `json:"satisfactionRate"` // 点赞数 / 总评价数
+}
+
+// AIWorstAnswerResponse 差评回答详情（附带用户提问与生成时检索到的知识片段）
+type AIWorstAnswerResponse struct {
+	AIMessageID     uint64   `json:"aiMessageId"`
+	AISessionID     uint64   `json:"aiSessionId"`
+	Question        string   `json:"question"`
+	Answer          string   `json:"answer"`
+	Model           string   `json:"model"`
+	PromptVersion   string   `json:"promptVersion"`
+	UpCount         int64    `json:"upCount"`
+	DownCount       int64    `json:"downCount"`
+	Reasons         []string `json:"reasons"`
+	RetrievedChunks []string `json:"retrievedChunks"`
+	AnswerTime      string   `json:"answerTime"`
+}
+
+// AIFeedbackDashboardResponse AI回答质量看板
+type AIFeedbackDashboardResponse struct {
+	StartDate    string                        `json:"startDate"`
+	EndDate      string                        `json:"endDate"`
+	DailyStats   []AIFeedbackDailyStatResponse `json:"dailyStats"`
+	WorstAnswers []AIWorstAnswerResponse       `json:"worstAnswers"`
+}
+
+func BuildAIMessageFeedbackResponse(feedback models.AIMessageFeedback) AIMessageFeedbackResponse {
+	return AIMessageFeedbackResponse{
+		FeedbackID:  feedback.ID,
+		AIMessageID: feedback.AIMessageID,
+		Rating:      feedback.Rating,
+		Reason:      feedback.Reason,
+		UpdateTime:  feedback.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func BuildAIFeedbackDailyStatResponses(stats []dao.AIFeedbackDailyStat) []AIFeedbackDailyStatResponse {
+	responses := make([]AIFeedbackDailyStatResponse, 0, len(stats))
+	for _, stat := range stats {
+		var rate float64
+		if total := stat.UpCount + stat.DownCount; total > 0 {
+			rate = float64(stat.UpCount) / float64(total)
+		}
+		responses = append(responses, AIFeedbackDailyStatResponse{
+			Day:              stat.Day,
+			Model:            stat.Model,
+			PromptVersion:    stat.PromptVersion,
+			UpCount:          stat.UpCount,
+			DownCount:        stat.DownCount,
+			SatisfactionRate: rate,
+		})
+	}
+	return responses
+}
+
+// BuildAIWorstAnswerResponse 构建差评回答详情，检索片段从回答保存的 RetrievalContext 中解析
+func BuildAIWorstAnswerResponse(stat dao.AIFeedbackMessageStat) (AIWorstAnswerResponse, error) {
+	message, err := dao.GetAIMessageByID(stat.AIMessageID)
+	if err != nil {
+		return AIWorstAnswerResponse{}, err
+	}
+
+	var question string
+	if q, err := dao.GetPreviousUserAIMessage(message); err == nil && q != nil {
+		question = q.Content
+	}
+
+	chunks := make([]string, 0)
+	if message.RetrievalContext != "" {
+		_ = json.Unmarshal([]byte(message.RetrievalContext), &chunks)
+		if chunks == nil {
+			chunks = make([]string, 0)
+		}
+	}
+
+	reasons := make([]string, 0)
+	feedbacks, err := dao.GetAIFeedbackReasons(message.ID)
+	if err == nil {
+		for _, feedback := range feedbacks {
+			reasons = append(reasons, feedback.Reason)
+		}
+	}
+
+	return AIWorstAnswerResponse{
+		AIMessageID:     message.ID,
+		AISessionID:     message.AISessionsID,
+		Question:        question,
+		Answer:          message.Content,
+		Model:           message.Model,
+		PromptVersion:   message.PromptVersion,
+		UpCount:         stat.UpCount,
+		DownCount:       stat.DownCount,
+		Reasons:         reasons,
+		RetrievedChunks: chunks,
+		AnswerTime:      message.CreatedAt.Format("2006-01-02 15:04:05"),
+	}, nil
+}
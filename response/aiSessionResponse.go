@@ -3,17 +3,23 @@ package response
 import (
 	"github.com/antidote-kt/SSE_Library-back/dao"
 	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/utils"
 )
 
+// aiMessageSnippetRadius 搜索结果中关键词前后保留的字符数
+const aiMessageSnippetRadius = 40
+
 type CreateAISessionResponse struct {
-	AISessionID uint64 `json:"aiSessionId"`
-	CreateTime  string `json:"createTime"`
+	AISessionID uint64  `json:"aiSessionId"`
+	CategoryID  *uint64 `json:"categoryId"` // 课程助教会话对应的课程分类ID
+	CreateTime  string  `json:"createTime"`
 }
 
 type AISessionListItemResponse struct {
 	AISessionID   uint64  `json:"aiSessionId"`
 	UserID        uint64  `json:"userId"`
 	AISessionName string  `json:"aiSessionName"`
+	CategoryID    *uint64 `json:"categoryId"` // 课程助教会话对应的课程分类ID
 	LastTime      *string `json:"lasttime"`
 }
 
@@ -26,6 +32,7 @@ type CancelAISessionResponse struct {
 func BuildCreateAISessionResponse(session models.AISession) CreateAISessionResponse {
 	return CreateAISessionResponse{
 		AISessionID: session.ID,
+		CategoryID:  session.CategoryID,
 		CreateTime:  session.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
@@ -43,6 +50,7 @@ func BuildAISessionListItemResponse(session models.AISession) AISessionListItemR
 		AISessionID:   session.ID,
 		UserID:        session.UserID,
 		AISessionName: session.Title,
+		CategoryID:    session.CategoryID,
 		LastTime:      lastTime,
 	}
 }
@@ -71,3 +79,51 @@ type AIMessageHistoryResponse struct {
 	Content        string `json:"content"`
 	State          string `json:"state"`
 }
+
+// AIMessageSearchItemResponse AI对话记录搜索结果（单条命中消息）
+type AIMessageSearchItemResponse struct {
+	AISessionID   uint64 `json:"aiSessionId"`
+	AISessionName string `json:"aiSessionName"`
+	AIMessageID   uint64 `json:"aiMessageId"`
+	IsUserSend    bool   `json:"isUserSend"`
+	Snippet       string `json:"snippet"` // 命中片段，关键词使用 <em></em> 包裹
+	SendTime      string `json:"sendTime"`
+}
+
+// AIMessageSearchResponse AI对话记录搜索分页结果
+type AIMessageSearchResponse struct {
+	Total    int64                         `json:"total"`
+	Page     int                           `json:"page"`
+	PageSize int                           `json:"pageSize"`
+	List     []AIMessageSearchItemResponse `json:"list"`
+}
+
+// BuildAIMessageSearchResponse 构建AI对话记录搜索结果，同一会话的标题只查询一次
+func BuildAIMessageSearchResponse(messages []models.AIMessage, keyword string, total int64, page, pageSize int) AIMessageSearchResponse {
+	sessionTitles := make(map[uint64]string)
+	list := make([]AIMessageSearchItemResponse, 0, len(messages))
+	for _, msg := range messages {
+		title, ok := sessionTitles[msg.AISessionsID]
+		if !ok {
+			session, err := dao.GetAISessionByID(msg.AISessionsID)
+			if err == nil {
+				title = session.Title
+			}
+			sessionTitles[msg.AISessionsID] = title
+		}
+		list = append(list, AIMessageSearchItemResponse{
+			AISessionID:   msg.AISessionsID,
+			AISessionName: title,
+			AIMessageID:   msg.ID,
+			IsUserSend:    msg.Role == "user",
+			Snippet:       utils.BuildHighlightSnippet(msg.Content, keyword, aiMessageSnippetRadius),
+			SendTime:      msg.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return AIMessageSearchResponse{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		List:     list,
+	}
+}
@@ -1,10 +1,40 @@
 package response
 
+import "github.com/antidote-kt/SSE_Library-back/models"
+
 // AISummaryData 查看/生成 AI 摘要接口返回的 data 结构（与前端约定字段名一致）。
 type AISummaryData struct {
-	FromCache   bool   `json:"fromcache"`
-	ContentType string `json:"contentType"`
-	ContentID   uint64 `json:"contentId"`
-	SummaryID   uint64 `json:"summaryId"`
-	Summary     string `json:"summary"`
+	FromCache     bool   `json:"fromcache"` // 是否直接返回了已保存的摘要
+	ContentType   string `json:"contentType"`
+	ContentID     uint64 `json:"contentId"`
+	SummaryID     uint64 `json:"summaryId"`
+	Style         string `json:"style"` // 摘要风格：abstract、key_points、outline、glossary
+	Summary       string `json:"summary"`
+	Model         string `json:"model"`
+	PromptVersion string `json:"promptVersion"` // 生成该摘要所用的提示词版本
+	CreateTime    string `json:"createTime"`
+}
+
+// BuildAISummaryData 构建摘要响应
+func BuildAISummaryData(summary models.AISummary, fromCache bool) AISummaryData {
+	return AISummaryData{
+		FromCache:     fromCache,
+		ContentType:   summary.ContentType,
+		ContentID:     summary.ContentID,
+		SummaryID:     summary.ID,
+		Style:         summary.Style,
+		Summary:       summary.Summary,
+		Model:         summary.Model,
+		PromptVersion: summary.PromptVersion,
+		CreateTime:    summary.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// BuildAISummaryDataList 构建摘要列表响应（摘要历史、文档详情中的各风格摘要）
+func BuildAISummaryDataList(summaries []models.AISummary) []AISummaryData {
+	list := make([]AISummaryData, len(summaries))
+	for i, summary := range summaries {
+		list[i] = BuildAISummaryData(summary, true)
+	}
+	return list
 }
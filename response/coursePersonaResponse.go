@@ -0,0 +1,31 @@
+package response
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/models"
+)
+
+// CoursePersonaResponse 课程助教设定
+type CoursePersonaResponse struct {
+	CategoryID uint64  `json:"categoryId"`
+	CourseName string  `json:"courseName"`
+	Persona    string  `json:"persona"`
+	Syllabus   string  `json:"syllabus"`
+	UpdaterID  uint64  `json:"updaterId"`
+	UpdateTime *string `json:"updateTime"` // 未配置时为空
+}
+
+// BuildCoursePersonaResponse 构建课程助教设定响应，persona 为 nil 表示该课程尚未配置
+func BuildCoursePersonaResponse(course models.Category, persona *models.CoursePersona) CoursePersonaResponse {
+	resp := CoursePersonaResponse{
+		CategoryID: course.ID,
+		CourseName: course.Name,
+	}
+	if persona != nil {
+		updateTime := persona.UpdatedAt.Format("2006-01-02 15:04:05")
+		resp.Persona = persona.Persona
+		resp.Syllabus = persona.Syllabus
+		resp.UpdaterID = persona.UpdaterID
+		resp.UpdateTime = &updateTime
+	}
+	return resp
+}
@@ -0,0 +1,53 @@
+package response
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/utils"
+)
+
+// DocumentImportJobResponse 批量导入任务的进度和结果
+type DocumentImportJobResponse struct {
+	JobID       string                      `json:"jobId"`
+	Status      string                      `json:"status"` // running、completed、failed
+	DryRun      bool                        `json:"dryRun"`
+	Total       int                         `json:"total"`
+	Processed   int                         `json:"processed"`
+	Succeeded   int                         `json:"succeeded"` // 成功创建（试运行时为校验通过）的行数
+	Failed      int                         `json:"failed"`
+	DocumentIDs []uint64                    `json:"documentIds"`
+	Errors      []utils.DocumentImportError `json:"errors"`
+	Message     string                      `json:"message"`   // 任务整体失败的原因
+	ReportURL   string                      `json:"reportUrl"` // 错误报告（CSV）下载地址
+	CreateTime  string                      `json:"createTime"`
+	FinishTime  string                      `json:"finishTime"` // 未完成时为空
+}
+
+// BuildDocumentImportJobResponse 构建批量导入任务响应
+func BuildDocumentImportJobResponse(job *utils.DocumentImportJob) DocumentImportJobResponse {
+	resp := DocumentImportJobResponse{
+		JobID:       job.ID,
+		Status:      job.Status,
+		DryRun:      job.DryRun,
+		Total:       job.Total,
+		Processed:   job.Processed,
+		Succeeded:   job.Succeeded,
+		Failed:      job.Failed,
+		DocumentIDs: job.DocumentIDs,
+		Errors:      job.Errors,
+		Message:     job.Message,
+		ReportURL:   fmt.Sprintf("/api/admin/documents/import/%s/report", job.ID),
+		CreateTime:  time.Unix(job.CreatedAt, 0).Format("2006-01-02 15:04:05"),
+	}
+	if resp.DocumentIDs == nil {
+		resp.DocumentIDs = []uint64{}
+	}
+	if resp.Errors == nil {
+		resp.Errors = []utils.DocumentImportError{}
+	}
+	if job.FinishedAt > 0 {
+		resp.FinishTime = time.Unix(job.FinishedAt, 0).Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
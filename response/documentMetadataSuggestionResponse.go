@@ -0,0 +1,46 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/antidote-kt/SSE_Library-back/models"
+)
+
+// DocumentMetadataSuggestionResponse 文档的 AI 元数据建议
+type DocumentMetadataSuggestionResponse struct {
+	DocumentID            uint64   `json:"documentId"`
+	Introduction          string   `json:"introduction"`
+	Tags                  []string `json:"tags"`
+	NewTags               []string `json:"newTags"`
+	SuggestedCategoryID   *uint64  `json:"suggestedCategoryId"`
+	SuggestedCategoryName string   `json:"suggestedCategoryName"`
+	Difficulty            string   `json:"difficulty"`
+	Status                string   `json:"status"`
+	AcceptedFields        string   `json:"acceptedFields"`
+	Model                 string   `json:"model"`
+	PromptVersion         string   `json:"promptVersion"`
+	CreateTime            string   `json:"createTime"`
+}
+
+// BuildDocumentMetadataSuggestionResponse 构建文档的 AI 元数据建议响应，categoryName 为建议分类的名称
+func BuildDocumentMetadataSuggestionResponse(suggestion models.DocumentMetadataSuggestion, categoryName string) DocumentMetadataSuggestionResponse {
+	tags := []string{}
+	newTags := []string{}
+	_ = json.Unmarshal([]byte(suggestion.Tags), &tags)
+	_ = json.Unmarshal([]byte(suggestion.NewTags), &newTags)
+
+	return DocumentMetadataSuggestionResponse{
+		DocumentID:            suggestion.DocumentID,
+		Introduction:          suggestion.Introduction,
+		Tags:                  tags,
+		NewTags:               newTags,
+		SuggestedCategoryID:   suggestion.SuggestedCategoryID,
+		SuggestedCategoryName: categoryName,
+		Difficulty:            suggestion.Difficulty,
+		Status:                suggestion.Status,
+		AcceptedFields:        suggestion.AcceptedFields,
+		Model:                 suggestion.Model,
+		PromptVersion:         suggestion.PromptVersion,
+		CreateTime:            suggestion.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -16,6 +16,16 @@ type InfoBriefResponse struct {
 	Collections int    `json:"collections"`
 	ReadCounts  int    `json:"readCounts"`
 	Cover       string `json:"cover"`
+
+	// 封面各规格缩略图，没有封面时自动生成
+	CoverThumbnails ImageVariantsResponse `json:"coverThumbnails"`
+
+	// 下载次数，与阅读量分开统计
+	DownloadCounts int `json:"downloadCounts"`
+
+	// 评分：平均星级和评分人数
+	RatingAverage float64 `json:"ratingAverage"`
+	RatingCount   int     `json:"ratingCount"`
 }
 
 type UploaderResponse struct {
@@ -26,6 +36,9 @@ type UploaderResponse struct {
 	CreateTime string `json:"createTime"`
 	Email      string `json:"email"`
 	Role       string `json:"role"`
+
+	// 头像各规格缩略图
+	AvatarThumbnails ImageVariantsResponse `json:"avatarThumbnails"`
 }
 
 type DocumentDetailResponse struct {
@@ -33,15 +46,42 @@ type DocumentDetailResponse struct {
 	BookISBN     string              `json:"bookISBN"`
 	Author       string              `json:"author"`
 	Uploader     UploaderResponse    `json:"uploader"`
-	URL          string              `json:"URL"`
+	URL          string              `json:"URL"` // 视频为外部链接，其他文档为获取限时下载地址的接口路径
 	Tags         []string            `json:"tags"`
 	Introduction string              `json:"introduction"`
 	CreateYear   string              `json:"createYear"`
+	Difficulty   string              `json:"difficulty"` // 难度：beginner、intermediate、advanced，未设置时为空
 	PostList     []PostBriefResponse `json:"postList"`
+	Summaries    []AISummaryData     `json:"summaries"` // 各风格的最新 AI 摘要
+
+	// 文件技术元数据
+	FileMetadata DocumentFileMetadataResponse `json:"fileMetadata"`
+
+	// 出版社
+	Publisher string `json:"publisher"`
 }
 
-// buildDocumentDetailResponse 构建文档详情响应对象
+// DocumentFileMetadataResponse 文档文件的技术元数据，尚未提取的字段为零值
+type DocumentFileMetadataResponse struct {
+	FileSize     int64  `json:"fileSize"`     // 文件大小（字节）
+	MimeType     string `json:"mimeType"`     // 按文件内容检测出的类型
+	ContentHash  string `json:"contentHash"`  // 文件 SHA-256
+	PageCount    int    `json:"pageCount"`    // 页数（仅 PDF）
+	WordCount    int    `json:"wordCount"`    // 字数
+	Language     string `json:"language"`     // 正文语言（ISO 639-1，如 zh、en），无法判断时为 und
+	PDFTitle     string `json:"pdfTitle"`     // PDF 信息字典中的标题
+	PDFAuthor    string `json:"pdfAuthor"`    // PDF 信息字典中的作者
+	PDFCreatedAt string `json:"pdfCreatedAt"` // PDF 信息字典中的创建时间
+}
+
+// buildDocumentDetailResponse 构建文档详情响应对象，关联帖子中不含被内容审核隐藏的帖子
 func BuildDocumentDetailResponse(document models.Document) (DocumentDetailResponse, error) {
+	return BuildDocumentDetailResponseForViewer(document, nil)
+}
+
+// BuildDocumentDetailResponseForViewer 构建当前用户查看的文档详情，被内容审核隐藏的关联帖子只对发帖人和管理员显示，
+// viewer 为空时视为未登录
+func BuildDocumentDetailResponseForViewer(document models.Document, viewer *utils.MyClaims) (DocumentDetailResponse, error) {
 	// 获取上传者信息
 	uploader, err := dao.GetUserByID(document.UploaderID)
 	if err != nil {
@@ -77,6 +117,10 @@ func BuildDocumentDetailResponse(document models.Document) (DocumentDetailRespon
 		Collections: document.Collections,
 		ReadCounts:  document.ReadCounts,
 		Cover:       utils.GetFileURL(document.Cover),
+
+		CoverThumbnails: BuildImageVariantsResponse(document.Cover),
+
+		DownloadCounts: document.DownloadCounts,
 	}
 
 	// 构建 UploaderResponse
@@ -88,10 +132,18 @@ func BuildDocumentDetailResponse(document models.Document) (DocumentDetailRespon
 		CreateTime: uploader.CreatedAt.Format("2006-01-02 15:04:05"),
 		Email:      uploader.Email,
 		Role:       uploader.Role,
+
+		AvatarThumbnails: BuildImageVariantsResponse(uploader.Avatar),
 	}
 
 	// 获取与文档相关的帖子列表
-	posts, err := dao.GetPostsByDocumentID(document.ID)
+	var viewerID uint64
+	isAdmin := false
+	if viewer != nil {
+		viewerID = viewer.UserID
+		isAdmin = viewer.Role == "admin"
+	}
+	posts, err := dao.GetPostsByDocumentID(document.ID, viewerID, isAdmin)
 	if err != nil {
 		// 如果获取帖子失败，记录错误但不中断整个流程，使用空切片
 		posts = []models.Post{}
@@ -100,6 +152,13 @@ func BuildDocumentDetailResponse(document models.Document) (DocumentDetailRespon
 	// 构建帖子简要响应列表
 	postBriefList := BuildPostBriefResponseList(posts)
 
+	// 获取各风格的最新 AI 摘要
+	summaries, err := dao.GetLatestAISummariesByContent("document", document.ID)
+	if err != nil {
+		// 如果获取摘要失败，记录错误但不中断整个流程，使用空切片
+		summaries = []models.AISummary{}
+	}
+
 	// 构建 DocumentDetailResponse
 	docDetailResponse := DocumentDetailResponse{
 		InfoBrief:    infoBrief,
@@ -110,7 +169,13 @@ func BuildDocumentDetailResponse(document models.Document) (DocumentDetailRespon
 		Tags:         tagNames,
 		Introduction: document.Introduction,
 		CreateYear:   document.CreateYear,
+		Difficulty:   document.Difficulty,
 		PostList:     postBriefList,
+		Summaries:    BuildAISummaryDataList(summaries),
+
+		FileMetadata: BuildDocumentFileMetadataResponse(document),
+
+		Publisher: document.Publisher,
 	}
 
 	return docDetailResponse, nil
@@ -131,7 +196,32 @@ func BuildInfoBriefResponse(document models.Document) (InfoBriefResponse, error)
 		Collections: document.Collections,
 		ReadCounts:  document.ReadCounts,
 		Cover:       utils.GetFileURL(document.Cover),
+
+		CoverThumbnails: BuildImageVariantsResponse(document.Cover),
+
+		DownloadCounts: document.DownloadCounts,
+
+		RatingAverage: document.RatingAverage,
+		RatingCount:   document.RatingCount,
 	}
 
 	return infoBriefResponse, nil
 }
+
+// BuildDocumentFileMetadataResponse 构建文档文件的技术元数据响应
+func BuildDocumentFileMetadataResponse(document models.Document) DocumentFileMetadataResponse {
+	metadata := DocumentFileMetadataResponse{
+		FileSize:    document.FileSize,
+		MimeType:    document.MimeType,
+		ContentHash: document.ContentHash,
+		PageCount:   document.PageCount,
+		WordCount:   document.WordCount,
+		Language:    document.Language,
+		PDFTitle:    document.PDFTitle,
+		PDFAuthor:   document.PDFAuthor,
+	}
+	if document.PDFCreatedAt != nil {
+		metadata.PDFCreatedAt = document.PDFCreatedAt.Format("2006-01-02 15:04:05")
+	}
+	return metadata
+}
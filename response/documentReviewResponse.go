@@ -0,0 +1,81 @@
+package response
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+)
+
+// ReviewerResponse 评价者信息
+type ReviewerResponse struct {
+	UserID     uint64 `json:"userId"`
+	Username   string `json:"username"`
+	UserAvatar string `json:"userAvatar"`
+}
+
+// DocumentReviewResponse 文档评价
+type DocumentReviewResponse struct {
+	ReviewID     uint64           `json:"reviewId"`
+	DocumentID   uint64           `json:"documentId"`
+	Reviewer     ReviewerResponse `json:"reviewer"`
+	Stars        int              `json:"stars"`
+	Content      string           `json:"content"`
+	HelpfulCount int              `json:"helpfulCount"`
+	Helpful      bool             `json:"helpful"` // 当前用户是否已标记有帮助
+	CreateTime   string           `json:"createTime"`
+	UpdateTime   string           `json:"updateTime"`
+}
+
+// DocumentReviewListResponse 分页的评价列表及文档的评分汇总
+type DocumentReviewListResponse struct {
+	RatingAverage float64                  `json:"ratingAverage"`
+	RatingCount   int                      `json:"ratingCount"`
+	StarCounts    map[int]int64            `json:"starCounts"` // 各星级的评价数
+	MyReview      *DocumentReviewResponse  `json:"myReview"`   // 当前用户的评价，没有评价时为 null
+	Total         int64                    `json:"total"`
+	Page          int                      `json:"page"`
+	PageSize      int                      `json:"pageSize"`
+	List          []DocumentReviewResponse `json:"list"`
+}
+
+// BuildDocumentReviewResponses 构建评价列表，voted 为当前用户已标记有帮助的评价ID；评价者已不存在时只返回用户ID
+func BuildDocumentReviewResponses(reviews []models.DocumentReview, voted map[uint64]bool) []DocumentReviewResponse {
+	userIDs := make([]uint64, len(reviews))
+	for i, review := range reviews {
+		userIDs[i] = review.UserID
+	}
+	users, err := dao.GetUsersByIDs(userIDs)
+	if err != nil {
+		users = []models.User{}
+	}
+	userMap := make(map[uint64]models.User, len(users))
+	for _, user := range users {
+		userMap[user.ID] = user
+	}
+
+	responses := make([]DocumentReviewResponse, len(reviews))
+	for i, review := range reviews {
+		reviewer := ReviewerResponse{UserID: review.UserID}
+		if user, ok := userMap[review.UserID]; ok {
+			reviewer.Username = user.Username
+			reviewer.UserAvatar = utils.GetFileURL(user.Avatar)
+		}
+		responses[i] = DocumentReviewResponse{
+			ReviewID:     review.ID,
+			DocumentID:   review.DocumentID,
+			Reviewer:     reviewer,
+			Stars:        review.Stars,
+			Content:      review.Content,
+			HelpfulCount: review.HelpfulCount,
+			Helpful:      voted[review.ID],
+			CreateTime:   review.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdateTime:   review.UpdatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return responses
+}
+
+// BuildDocumentReviewResponse 构建单条评价
+func BuildDocumentReviewResponse(review models.DocumentReview, helpful bool) DocumentReviewResponse {
+	return BuildDocumentReviewResponses([]models.DocumentReview{review}, map[uint64]bool{review.ID: helpful})[0]
+}
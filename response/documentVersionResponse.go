@@ -0,0 +1,47 @@
+package response
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+)
+
+// DocumentVersionResponse 文档的一个版本
+type DocumentVersionResponse struct {
+	Version      int    `json:"version"`
+	Type         string `json:"type"`
+	FileSize     int64  `json:"fileSize"`
+	FileHash     string `json:"fileHash"`
+	UploaderID   uint64 `json:"uploaderId"`
+	UploaderName string `json:"uploaderName"`
+	ChangeNote   string `json:"changeNote"`
+	IsCurrent    bool   `json:"isCurrent"` // 是否为文档当前使用的版本
+	CreateTime   string `json:"createTime"`
+}
+
+// BuildDocumentVersionResponses 构建文档版本列表，currentVersion 为文档当前版本号
+func BuildDocumentVersionResponses(versions []models.DocumentVersion, currentVersion int) []DocumentVersionResponse {
+	uploaderNames := make(map[uint64]string)
+	results := make([]DocumentVersionResponse, 0, len(versions))
+	for _, version := range versions {
+		name, ok := uploaderNames[version.UploaderID]
+		if !ok {
+			// 上传者已注销时用户名留空
+			if user, err := dao.GetUserByID(version.UploaderID); err == nil {
+				name = user.Username
+			}
+			uploaderNames[version.UploaderID] = name
+		}
+		results = append(results, DocumentVersionResponse{
+			Version:      version.Version,
+			Type:         version.Type,
+			FileSize:     version.FileSize,
+			FileHash:     version.FileHash,
+			UploaderID:   version.UploaderID,
+			UploaderName: name,
+			ChangeNote:   version.ChangeNote,
+			IsCurrent:    version.Version == currentVersion,
+			CreateTime:   version.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return results
+}
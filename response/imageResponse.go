@@ -0,0 +1,22 @@
+package response
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+)
+
+// ImageVariantsResponse 图片各规格缩略图的访问地址，没有图片时均为空
+type ImageVariantsResponse struct {
+	List   string `json:"list"`   // 列表页
+	Detail string `json:"detail"` // 详情页
+	Retina string `json:"retina"` // 详情页高分屏
+}
+
+// BuildImageVariantsResponse 根据图片在存储中的路径构建各规格缩略图的访问地址
+func BuildImageVariantsResponse(key string) ImageVariantsResponse {
+	return ImageVariantsResponse{
+		List:   utils.GetThumbnailURL(key, constant.ImageVariantList),
+		Detail: utils.GetThumbnailURL(key, constant.ImageVariantDetail),
+		Retina: utils.GetThumbnailURL(key, constant.ImageVariantRetina),
+	}
+}
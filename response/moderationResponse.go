@@ -0,0 +1,136 @@
+package response
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+)
+
+// moderationPreviewMaxRunes 审核队列中内容预览的最大字符数
+const moderationPreviewMaxRunes = 200
+
+// ModerationBriefResponse 内容的审核结果摘要
+type ModerationBriefResponse struct {
+	RiskScore  float64  `json:"riskScore"`
+	Categories []string `json:"categories"`
+	Reason     string   `json:"reason"`
+	Status     string   `json:"status"`
+}
+
+// ModerationRecordResponse 审核队列中的一条记录
+type ModerationRecordResponse struct {
+	ID             uint64   `json:"id"`
+	ContentType    string   `json:"contentType"`
+	ContentID      uint64   `json:"contentId"`
+	AuthorID       uint64   `json:"authorId"`
+	AuthorName     string   `json:"authorName"`
+	ContentPreview string   `json:"contentPreview"` // 内容已被删除时为空
+	RiskScore      float64  `json:"riskScore"`
+	Categories     []string `json:"categories"`
+	Reason         string   `json:"reason"`
+	RuleHits       []string `json:"ruleHits"`
+	AIChecked      bool     `json:"aiChecked"`
+	Status         string   `json:"status"`
+	ReviewerID     *uint64  `json:"reviewerId"`
+	ReviewNote     string   `json:"reviewNote"`
+	ReviewTime     string   `json:"reviewTime"`
+	CreateTime     string   `json:"createTime"`
+}
+
+// AdminDocumentDetailResponse 管理员查看的文档详情，附带内容审核结果（尚未审核时为 null）
+type AdminDocumentDetailResponse struct {
+	DocumentDetailResponse
+	Moderation *ModerationBriefResponse `json:"moderation"`
+}
+
+// splitModerationCategories 将逗号分隔的风险类别转换为数组
+func splitModerationCategories(categories string) []string {
+	if categories == "" {
+		return []string{}
+	}
+	return strings.Split(categories, ",")
+}
+
+// BuildModerationBriefResponse 构建内容的审核结果摘要
+func BuildModerationBriefResponse(record models.ModerationRecord) ModerationBriefResponse {
+	return ModerationBriefResponse{
+		RiskScore:  record.RiskScore,
+		Categories: splitModerationCategories(record.Categories),
+		Reason:     record.Reason,
+		Status:     record.Status,
+	}
+}
+
+// buildModerationContentPreview 获取被审核内容的预览文本
+func buildModerationContentPreview(record models.ModerationRecord) string {
+	var preview string
+	switch record.ContentType {
+	case constant.ModerationTypeDocument:
+		if document, err := dao.GetDocumentByID(record.ContentID); err == nil {
+			preview = document.Name + "：" + document.Introduction
+		}
+	case constant.ModerationTypePost:
+		if post, err := dao.GetPostByID(record.ContentID); err == nil {
+			preview = post.Title + "：" + post.Content
+		}
+	case constant.ModerationTypeComment:
+		if comment, err := dao.GetCommentByID(record.ContentID); err == nil {
+			preview = comment.Content
+		}
+	case constant.ModerationTypeMessage:
+		if message, err := dao.GetMessageByID(record.ContentID); err == nil {
+			preview = message.Content
+		}
+	}
+	runes := []rune(preview)
+	if len(runes) > moderationPreviewMaxRunes {
+		preview = string(runes[:moderationPreviewMaxRunes]) + "..."
+	}
+	return preview
+}
+
+// BuildModerationRecordResponse 构建审核队列中的一条记录
+func BuildModerationRecordResponse(record models.ModerationRecord) ModerationRecordResponse {
+	ruleHits := []string{}
+	_ = json.Unmarshal([]byte(record.RuleHits), &ruleHits)
+
+	authorName := ""
+	if author, err := dao.GetUserByID(record.AuthorID); err == nil {
+		authorName = author.Username
+	}
+	reviewTime := ""
+	if record.ReviewedAt != nil {
+		reviewTime = record.ReviewedAt.Format("2006-01-02 15:04:05")
+	}
+
+	return ModerationRecordResponse{
+		ID:             record.ID,
+		ContentType:    record.ContentType,
+		ContentID:      record.ContentID,
+		AuthorID:       record.AuthorID,
+		AuthorName:     authorName,
+		ContentPreview: buildModerationContentPreview(record),
+		RiskScore:      record.RiskScore,
+		Categories:     splitModerationCategories(record.Categories),
+		Reason:         record.Reason,
+		RuleHits:       ruleHits,
+		AIChecked:      record.AIChecked,
+		Status:         record.Status,
+		ReviewerID:     record.ReviewerID,
+		ReviewNote:     record.ReviewNote,
+		ReviewTime:     reviewTime,
+		CreateTime:     record.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// BuildModerationRecordResponses 构建审核队列列表
+func BuildModerationRecordResponses(records []models.ModerationRecord) []ModerationRecordResponse {
+	results := make([]ModerationRecordResponse, 0, len(records))
+	for _, record := range records {
+		results = append(results, BuildModerationRecordResponse(record))
+	}
+	return results
+}
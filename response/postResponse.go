@@ -95,6 +95,8 @@ func BuildPostDetailResponse(post models.Post, documents []models.Document) Post
 			Collections: doc.Collections,
 			ReadCounts:  doc.ReadCounts,
 			Cover:       utils.GetFileURL(doc.Cover), // 处理文档链接
+
+			CoverThumbnails: BuildImageVariantsResponse(doc.Cover),
 		})
 	}
 
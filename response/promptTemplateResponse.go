@@ -0,0 +1,76 @@
+package response
+
+import (
+	"fmt"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+)
+
+// PromptTemplateSummaryResponse 提示词标识及其当前生效内容
+type PromptTemplateSummaryResponse struct {
+	TemplateKey   string   `json:"templateKey"`
+	ActiveVersion string   `json:"activeVersion"` // 当前生效版本，如 v3；未配置时为 builtin
+	Content       string   `json:"content"`
+	Variables     []string `json:"variables"`
+}
+
+// PromptTemplateVersionResponse 提示词的单个版本
+type PromptTemplateVersionResponse struct {
+	TemplateKey string   `json:"templateKey"`
+	Version     int      `json:"version"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	IsActive    bool     `json:"isActive"`
+	Variables   []string `json:"variables"`
+	CreatorID   uint64   `json:"creatorId"`
+	CreateTime  string   `json:"createTime"`
+}
+
+// PromptPreviewResponse 提示词预览渲染结果
+type PromptPreviewResponse struct {
+	TemplateKey string   `json:"templateKey"`
+	Variables   []string `json:"variables"`
+	Rendered    string   `json:"rendered"`
+}
+
+// BuildPromptTemplateSummaryResponse 构建提示词概览，active 为 nil 时展示内置提示词
+func BuildPromptTemplateSummaryResponse(key string, active *models.PromptTemplate) PromptTemplateSummaryResponse {
+	if active == nil {
+		content := constant.DefaultPromptTemplates[key]
+		return PromptTemplateSummaryResponse{
+			TemplateKey:   key,
+			ActiveVersion: constant.PromptBuiltinVersion,
+			Content:       content,
+			Variables:     utils.ExtractPromptVariables(content),
+		}
+	}
+	return PromptTemplateSummaryResponse{
+		TemplateKey:   key,
+		ActiveVersion: fmt.Sprintf("v%d", active.Version),
+		Content:       active.Content,
+		Variables:     utils.ExtractPromptVariables(active.Content),
+	}
+}
+
+func BuildPromptTemplateVersionResponse(tmpl models.PromptTemplate) PromptTemplateVersionResponse {
+	return PromptTemplateVersionResponse{
+		TemplateKey: tmpl.TemplateKey,
+		Version:     tmpl.Version,
+		Content:     tmpl.Content,
+		Description: tmpl.Description,
+		IsActive:    tmpl.IsActive,
+		Variables:   utils.ExtractPromptVariables(tmpl.Content),
+		CreatorID:   tmpl.CreatorID,
+		CreateTime:  tmpl.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func BuildPromptTemplateVersionResponses(templates []models.PromptTemplate) []PromptTemplateVersionResponse {
+	responses := make([]PromptTemplateVersionResponse, 0, len(templates))
+	for _, tmpl := range templates {
+		responses = append(responses, BuildPromptTemplateVersionResponse(tmpl))
+	}
+	return responses
+}
@@ -0,0 +1,138 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+)
+
+// GeneratedQuizResponse 生成的测验（尚未保存）
+type GeneratedQuizResponse struct {
+	DocumentID    uint64               `json:"documentId"`
+	StartPage     int                  `json:"startPage"` // 实际用于出题的页码范围
+	EndPage       int                  `json:"endPage"`
+	PromptVersion string               `json:"promptVersion"`
+	Questions     []utils.QuizQuestion `json:"questions"`
+	Flashcards    []utils.Flashcard    `json:"flashcards"`
+}
+
+// QuizBriefResponse 测验列表项
+type QuizBriefResponse struct {
+	QuizID         uint64 `json:"quizId"`
+	DocumentID     uint64 `json:"documentId"`
+	Title          string `json:"title"`
+	StartPage      int    `json:"startPage"`
+	EndPage        int    `json:"endPage"`
+	QuestionCount  int    `json:"questionCount"`
+	FlashcardCount int    `json:"flashcardCount"`
+	CreateTime     string `json:"createTime"`
+}
+
+// QuizTakeQuestionResponse 答题时下发的题目（不含答案和解析）
+type QuizTakeQuestionResponse struct {
+	QuestionIndex int      `json:"questionIndex"`
+	Type          string   `json:"type"`
+	Question      string   `json:"question"`
+	Options       []string `json:"options,omitempty"`
+	SourcePages   []int    `json:"sourcePages"`
+}
+
+// QuizTakeResponse 答题时获取的测验详情
+type QuizTakeResponse struct {
+	QuizBriefResponse
+	Questions  []QuizTakeQuestionResponse `json:"questions"`
+	Flashcards []utils.Flashcard          `json:"flashcards"`
+}
+
+// QuizAnswerResultResponse 单道题的判分结果
+type QuizAnswerResultResponse struct {
+	QuestionIndex int    `json:"questionIndex"`
+	Answer        string `json:"answer"`
+	Correct       bool   `json:"correct"`
+	CorrectAnswer string `json:"correctAnswer"`
+	Explanation   string `json:"explanation"`
+}
+
+// QuizAttemptResponse 答题记录
+type QuizAttemptResponse struct {
+	AttemptID    uint64                     `json:"attemptId"`
+	QuizID       uint64                     `json:"quizId"`
+	QuizTitle    string                     `json:"quizTitle"`
+	CorrectCount int                        `json:"correctCount"`
+	TotalCount   int                        `json:"totalCount"`
+	Score        float64                    `json:"score"`
+	CreateTime   string                     `json:"createTime"`
+	Results      []QuizAnswerResultResponse `json:"results,omitempty"` // 仅提交答题时返回
+}
+
+// BuildQuizBriefResponse 构建测验列表项
+func BuildQuizBriefResponse(quiz models.Quiz) QuizBriefResponse {
+	return QuizBriefResponse{
+		QuizID:         quiz.ID,
+		DocumentID:     quiz.DocumentID,
+		Title:          quiz.Title,
+		StartPage:      quiz.StartPage,
+		EndPage:        quiz.EndPage,
+		QuestionCount:  quiz.QuestionCount,
+		FlashcardCount: quiz.FlashcardCount,
+		CreateTime:     quiz.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// BuildQuizBriefResponses 构建测验列表
+func BuildQuizBriefResponses(quizzes []models.Quiz) []QuizBriefResponse {
+	responses := make([]QuizBriefResponse, len(quizzes))
+	for i, quiz := range quizzes {
+		responses[i] = BuildQuizBriefResponse(quiz)
+	}
+	return responses
+}
+
+// BuildQuizTakeResponse 构建答题用的测验详情，隐藏答案和解析
+func BuildQuizTakeResponse(quiz models.Quiz) (QuizTakeResponse, error) {
+	var content utils.QuizContent
+	if err := json.Unmarshal([]byte(quiz.Content), &content); err != nil {
+		return QuizTakeResponse{}, err
+	}
+
+	questions := make([]QuizTakeQuestionResponse, len(content.Questions))
+	for i, q := range content.Questions {
+		questions[i] = QuizTakeQuestionResponse{
+			QuestionIndex: i,
+			Type:          q.Type,
+			Question:      q.Question,
+			Options:       q.Options,
+			SourcePages:   q.SourcePages,
+		}
+	}
+	return QuizTakeResponse{
+		QuizBriefResponse: BuildQuizBriefResponse(quiz),
+		Questions:         questions,
+		Flashcards:        content.Flashcards,
+	}, nil
+}
+
+// BuildQuizAttemptResponse 构建答题记录
+func BuildQuizAttemptResponse(attempt models.QuizAttempt, quizTitle string, results []QuizAnswerResultResponse) QuizAttemptResponse {
+	return QuizAttemptResponse{
+		AttemptID:    attempt.ID,
+		QuizID:       attempt.QuizID,
+		QuizTitle:    quizTitle,
+		CorrectCount: attempt.CorrectCount,
+		TotalCount:   attempt.TotalCount,
+		Score:        attempt.Score,
+		CreateTime:   attempt.CreatedAt.Format("2006-01-02 15:04:05"),
+		Results:      results,
+	}
+}
+
+// BuildQuizAttemptHistoryResponses 构建文档下的答题成绩历史
+func BuildQuizAttemptHistoryResponses(attempts []dao.QuizAttemptWithTitle) []QuizAttemptResponse {
+	responses := make([]QuizAttemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		responses[i] = BuildQuizAttemptResponse(attempt.QuizAttempt, attempt.QuizTitle, nil)
+	}
+	return responses
+}
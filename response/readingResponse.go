@@ -0,0 +1,141 @@
+package response
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+)
+
+// ReadingProgressResponse 阅读进度
+type ReadingProgressResponse struct {
+	DocumentID uint64  `json:"documentId"`
+	Page       int     `json:"page"`
+	Position   string  `json:"position"`
+	Percent    float64 `json:"percent"`
+	Finished   bool    `json:"finished"`
+	ReadAt     int64   `json:"readAt"` // 阅读器记录进度的时间（毫秒时间戳）
+	ReadTime   string  `json:"readTime"`
+}
+
+// ReadingBookmarkResponse 书签
+type ReadingBookmarkResponse struct {
+	BookmarkID uint64 `json:"bookmarkId"`
+	DocumentID uint64 `json:"documentId"`
+	Name       string `json:"name"`
+	Page       int    `json:"page"`
+	Position   string `json:"position"`
+	CreateTime string `json:"createTime"`
+}
+
+// ReadingHighlightResponse 划线及笔记
+type ReadingHighlightResponse struct {
+	HighlightID uint64 `json:"highlightId"`
+	DocumentID  uint64 `json:"documentId"`
+	Page        int    `json:"page"`
+	StartOffset int    `json:"startOffset"`
+	EndOffset   int    `json:"endOffset"`
+	Text        string `json:"text"`
+	Note        string `json:"note"`
+	Color       string `json:"color"`
+	CreateTime  string `json:"createTime"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+// ReadingStateResponse 用户在文档中的阅读状态，阅读器打开文档时获取
+type ReadingStateResponse struct {
+	Progress   *ReadingProgressResponse   `json:"progress"` // 还没有阅读进度时为 null
+	Bookmarks  []ReadingBookmarkResponse  `json:"bookmarks"`
+	Highlights []ReadingHighlightResponse `json:"highlights"`
+}
+
+// ContinueReadingResponse 继续阅读列表项
+type ContinueReadingResponse struct {
+	InfoBrief InfoBriefResponse       `json:"infoBrief"`
+	PageCount int                     `json:"pageCount"` // 文档页数，未知时为 0
+	Progress  ReadingProgressResponse `json:"progress"`
+}
+
+// ContinueReadingListResponse 分页的继续阅读列表
+type ContinueReadingListResponse struct {
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"pageSize"`
+	List     []ContinueReadingResponse `json:"list"`
+}
+
+// BuildReadingProgressResponse 构建阅读进度
+func BuildReadingProgressResponse(progress models.ReadingProgress) ReadingProgressResponse {
+	return ReadingProgressResponse{
+		DocumentID: progress.DocumentID,
+		Page:       progress.Page,
+		Position:   progress.Position,
+		Percent:    progress.Percent,
+		Finished:   progress.Percent >= constant.ReadingFinishedPercent,
+		ReadAt:     progress.ReadAt.UnixMilli(),
+		ReadTime:   progress.ReadAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// BuildReadingBookmarkResponse 构建书签
+func BuildReadingBookmarkResponse(bookmark models.ReadingBookmark) ReadingBookmarkResponse {
+	return ReadingBookmarkResponse{
+		BookmarkID: bookmark.ID,
+		DocumentID: bookmark.DocumentID,
+		Name:       bookmark.Name,
+		Page:       bookmark.Page,
+		Position:   bookmark.Position,
+		CreateTime: bookmark.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// BuildReadingHighlightResponse 构建划线
+func BuildReadingHighlightResponse(highlight models.ReadingHighlight) ReadingHighlightResponse {
+	return ReadingHighlightResponse{
+		HighlightID: highlight.ID,
+		DocumentID:  highlight.DocumentID,
+		Page:        highlight.Page,
+		StartOffset: highlight.StartOffset,
+		EndOffset:   highlight.EndOffset,
+		Text:        highlight.Text,
+		Note:        highlight.Note,
+		Color:       highlight.Color,
+		CreateTime:  highlight.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdateTime:  highlight.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// BuildReadingStateResponse 构建阅读状态，progress 为 nil 表示还没有阅读进度
+func BuildReadingStateResponse(progress *models.ReadingProgress, bookmarks []models.ReadingBookmark, highlights []models.ReadingHighlight) ReadingStateResponse {
+	state := ReadingStateResponse{
+		Bookmarks:  make([]ReadingBookmarkResponse, len(bookmarks)),
+		Highlights: make([]ReadingHighlightResponse, len(highlights)),
+	}
+	if progress != nil {
+		progressResponse := BuildReadingProgressResponse(*progress)
+		state.Progress = &progressResponse
+	}
+	for i, bookmark := range bookmarks {
+		state.Bookmarks[i] = BuildReadingBookmarkResponse(bookmark)
+	}
+	for i, highlight := range highlights {
+		state.Highlights[i] = BuildReadingHighlightResponse(highlight)
+	}
+	return state
+}
+
+// BuildContinueReadingResponses 构建继续阅读列表，分类已不存在的文档跳过
+func BuildContinueReadingResponses(items []dao.ContinueReadingItem) []ContinueReadingResponse {
+	responses := make([]ContinueReadingResponse, 0, len(items))
+	for _, item := range items {
+		infoBrief, err := BuildInfoBriefResponse(item.Document)
+		if err != nil {
+			continue
+		}
+		responses = append(responses, ContinueReadingResponse{
+			InfoBrief: infoBrief,
+			PageCount: item.Document.PageCount,
+			Progress:  BuildReadingProgressResponse(item.Progress),
+		})
+	}
+	return responses
+}
@@ -0,0 +1,115 @@
+package response
+
+import (
+	"math"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/dao"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+)
+
+// RecommendExperimentResponse 推荐实验信息
+type RecommendExperimentResponse struct {
+	ID          uint64                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Variants    []utils.RecommendVariant `json:"variants"`
+	Status      string                   `json:"status"`
+	StartTime   string                   `json:"startTime"`
+	StopTime    string                   `json:"stopTime"`
+	CreateTime  string                   `json:"createTime"`
+}
+
+// RecommendVariantReport 实验中一个变体的效果，置信区间为 95% Wilson 区间 [下限, 上限]
+type RecommendVariantReport struct {
+	Name               string     `json:"name"`
+	Strategy           string     `json:"strategy"`
+	Weight             int        `json:"weight"`
+	Users              int64      `json:"users"`       // 曝光用户数
+	Impressions        int64      `json:"impressions"` // 曝光数
+	Clicks             int64      `json:"clicks"`      // 曝光后浏览数
+	CTR                float64    `json:"ctr"`         // 点击率 = 点击数 / 曝光数
+	CTRInterval        [2]float64 `json:"ctrInterval"`
+	Conversions        int64      `json:"conversions"`    // 曝光后收藏数
+	ConversionRate     float64    `json:"conversionRate"` // 转化率 = 收藏数 / 曝光数
+	ConversionInterval [2]float64 `json:"conversionInterval"`
+}
+
+// RecommendExperimentReportResponse 推荐实验效果报告
+type RecommendExperimentReportResponse struct {
+	Experiment RecommendExperimentResponse `json:"experiment"`
+	Variants   []RecommendVariantReport    `json:"variants"`
+}
+
+// BuildRecommendExperimentResponse 构建推荐实验信息
+func BuildRecommendExperimentResponse(experiment models.RecommendExperiment) RecommendExperimentResponse {
+	variants, err := utils.ParseRecommendVariants(experiment.Variants)
+	if err != nil {
+		variants = []utils.RecommendVariant{}
+	}
+	startTime, stopTime := "", ""
+	if experiment.StartedAt != nil {
+		startTime = experiment.StartedAt.Format("2006-01-02 15:04:05")
+	}
+	if experiment.StoppedAt != nil {
+		stopTime = experiment.StoppedAt.Format("2006-01-02 15:04:05")
+	}
+	return RecommendExperimentResponse{
+		ID:          experiment.ID,
+		Name:        experiment.Name,
+		Description: experiment.Description,
+		Variants:    variants,
+		Status:      experiment.Status,
+		StartTime:   startTime,
+		StopTime:    stopTime,
+		CreateTime:  experiment.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// BuildRecommendExperimentResponses 构建推荐实验列表
+func BuildRecommendExperimentResponses(experiments []models.RecommendExperiment) []RecommendExperimentResponse {
+	results := make([]RecommendExperimentResponse, 0, len(experiments))
+	for _, experiment := range experiments {
+		results = append(results, BuildRecommendExperimentResponse(experiment))
+	}
+	return results
+}
+
+// roundRate 比例保留 4 位小数
+func roundRate(rate float64) float64 {
+	return math.Round(rate*10000) / 10000
+}
+
+// BuildRecommendExperimentReport 构建推荐实验效果报告，没有曝光的变体各项指标为 0
+func BuildRecommendExperimentReport(experiment models.RecommendExperiment, stats []dao.RecommendVariantStats) RecommendExperimentReportResponse {
+	info := BuildRecommendExperimentResponse(experiment)
+	statsByVariant := make(map[string]dao.RecommendVariantStats, len(stats))
+	for _, stat := range stats {
+		statsByVariant[stat.Variant] = stat
+	}
+
+	reports := make([]RecommendVariantReport, 0, len(info.Variants))
+	for _, variant := range info.Variants {
+		stat := statsByVariant[variant.Name]
+		report := RecommendVariantReport{
+			Name:        variant.Name,
+			Strategy:    variant.Strategy,
+			Weight:      variant.Weight,
+			Users:       stat.Users,
+			Impressions: stat.Impressions,
+			Clicks:      stat.Clicks,
+			Conversions: stat.Conversions,
+		}
+		if stat.Impressions > 0 {
+			report.CTR = roundRate(float64(stat.Clicks) / float64(stat.Impressions))
+			report.ConversionRate = roundRate(float64(stat.Conversions) / float64(stat.Impressions))
+		}
+		low, high := utils.WilsonInterval(stat.Clicks, stat.Impressions, constant.RecommendConfidenceZ)
+		report.CTRInterval = [2]float64{roundRate(low), roundRate(high)}
+		low, high = utils.WilsonInterval(stat.Conversions, stat.Impressions, constant.RecommendConfidenceZ)
+		report.ConversionInterval = [2]float64{roundRate(low), roundRate(high)}
+		reports = append(reports, report)
+	}
+	return RecommendExperimentReportResponse{Experiment: info, Variants: reports}
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+)
+
+// RecommendationResponse 一条个性化推荐结果，文档类推荐返回 document，帖子推荐返回 post
+type RecommendationResponse struct {
+	ItemType string             `json:"itemType"` // book、file、video、post
+	Score    float64            `json:"score"`    // 混合得分，0~1
+	Reason   string             `json:"reason"`   // 推荐理由
+	Document *InfoBriefResponse `json:"document,omitempty"`
+	Post     *PostBriefResponse `json:"post,omitempty"`
+}
+
+// BuildDocumentRecommendationResponse 构建文档推荐结果
+func BuildDocumentRecommendationResponse(document models.Document, score float64, reason string) (RecommendationResponse, error) {
+	brief, err := BuildInfoBriefResponse(document)
+	if err != nil {
+		return RecommendationResponse{}, err
+	}
+	return RecommendationResponse{
+		ItemType: document.Type,
+		Score:    score,
+		Reason:   reason,
+		Document: &brief,
+	}, nil
+}
+
+// BuildPostRecommendationResponse 构建帖子推荐结果
+func BuildPostRecommendationResponse(post models.Post, score float64, reason string) RecommendationResponse {
+	brief := BuildPostBriefResponse(post)
+	return RecommendationResponse{
+		ItemType: constant.RecommendTypePost,
+		Score:    score,
+		Reason:   reason,
+		Post:     &brief,
+	}
+}
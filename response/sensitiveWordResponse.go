@@ -0,0 +1,34 @@
+package response
+
+import (
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+)
+
+// SensitiveWordResponse 敏感词
+type SensitiveWordResponse struct {
+	ID         uint64 `json:"id"`
+	Word       string `json:"word"`
+	Category   string `json:"category"`
+	Action     string `json:"action"` // 所属分类的处理方式
+	CreateTime string `json:"createTime"`
+}
+
+// BuildSensitiveWordResponses 构建敏感词列表，categoryActions 为各分类的处理方式（未配置的分类使用默认处理方式）
+func BuildSensitiveWordResponses(words []models.SensitiveWord, categoryActions map[string]string) []SensitiveWordResponse {
+	results := make([]SensitiveWordResponse, 0, len(words))
+	for _, word := range words {
+		action, ok := categoryActions[word.Category]
+		if !ok {
+			action = constant.DefaultSensitiveAction
+		}
+		results = append(results, SensitiveWordResponse{
+			ID:         word.ID,
+			Word:       word.Word,
+			Category:   word.Category,
+			Action:     action,
+			CreateTime: word.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return results
+}
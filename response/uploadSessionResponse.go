@@ -0,0 +1,42 @@
+package response
+
+import (
+	"sort"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/utils"
+)
+
+// UploadSessionResponse 分片上传会话及进度
+type UploadSessionResponse struct {
+	UploadID      string `json:"uploadId"`
+	FileName      string `json:"fileName"`
+	FileSize      int64  `json:"fileSize"`
+	PartSize      int64  `json:"partSize"`
+	TotalParts    int    `json:"totalParts"`
+	UploadedParts []int  `json:"uploadedParts"` // 已上传的分片序号，升序
+	UploadedBytes int64  `json:"uploadedBytes"`
+	ExpireTime    string `json:"expireTime"` // 会话过期时间，过期后已上传的分片失效
+}
+
+// BuildUploadSessionResponse 构建分片上传会话及进度，parts 为已上传分片的校验和
+func BuildUploadSessionResponse(session *utils.UploadSession, parts map[int]string) UploadSessionResponse {
+	uploadedParts := make([]int, 0, len(parts))
+	var uploadedBytes int64
+	for partNumber := range parts {
+		uploadedParts = append(uploadedParts, partNumber)
+		uploadedBytes += session.PartSizeOf(partNumber)
+	}
+	sort.Ints(uploadedParts)
+	return UploadSessionResponse{
+		UploadID:      session.ID,
+		FileName:      session.FileName,
+		FileSize:      session.FileSize,
+		PartSize:      session.PartSize,
+		TotalParts:    session.TotalParts,
+		UploadedParts: uploadedParts,
+		UploadedBytes: uploadedBytes,
+		ExpireTime:    time.Unix(session.CreatedAt, 0).Add(constant.UploadSessionTTLHours * time.Hour).Format("2006-01-02 15:04:05"),
+	}
+}
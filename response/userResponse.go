@@ -22,10 +22,12 @@ type HomepageResponse struct {
 	UserBrief      UserBriefResponse   `json:"userBrief"`
 	Password       string              `json:"password"`
 	CollectionList []InfoBriefResponse `json:"collectionList"`
-	HistoryList    []InfoBriefResponse `json:"historyList"`
+
+	// 未读完的文档及阅读进度，按最近阅读时间倒序
+	ContinueReading []ContinueReadingResponse `json:"continueReading"`
 }
 
-func BuildHomepageResponse(user models.User, collectionList, historyList []models.Document) (HomepageResponse, error) {
+func BuildHomepageResponse(user models.User, collectionList []models.Document, continueReading []dao.ContinueReadingItem) (HomepageResponse, error) {
 	// 1. 构建用户简要信息 (UserBrief)
 	userBrief := UserBriefResponse{
 		UserID:     user.ID,
@@ -52,6 +54,8 @@ func BuildHomepageResponse(user models.User, collectionList, historyList []model
 			Collections: doc.Collections,
 			ReadCounts:  doc.ReadCounts,
 			Cover:       utils.GetFileURL(doc.Cover),
+
+			CoverThumbnails: BuildImageVariantsResponse(doc.Cover),
 		}
 		collectionResponses = append(collectionResponses, docBrief)
 	}
@@ -60,35 +64,16 @@ func BuildHomepageResponse(user models.User, collectionList, historyList []model
 		collectionResponses = make([]InfoBriefResponse, 0)
 	}
 
-	// 3. 构建历史记录列表 (HistoryList)
-	var historyResponses []InfoBriefResponse
-	for _, doc := range historyList {
-		// 将单个文档信息转换为 InfoBriefResponse 格式
-		category, _ := dao.GetCategoryByID(doc.CategoryID)
-		docBrief := InfoBriefResponse{
-			Name:        doc.Name,
-			DocumentID:  doc.ID,
-			Type:        doc.Type,
-			UploadTime:  doc.CreatedAt.Format("2006-01-02 15:04:05"),
-			Status:      doc.Status,
-			Category:    category.Name,
-			Collections: doc.Collections,
-			ReadCounts:  doc.ReadCounts,
-			Cover:       utils.GetFileURL(doc.Cover),
-		}
-		historyResponses = append(historyResponses, docBrief)
-	}
-	// 确保切片不为 nil
-	if historyResponses == nil {
-		historyResponses = make([]InfoBriefResponse, 0)
-	}
+	// 3. 构建继续阅读列表 (ContinueReading)
+	continueResponses := BuildContinueReadingResponses(continueReading)
 
 	// 4. 组装最终的 HomepageResponse
 	response := HomepageResponse{
 		UserBrief:      userBrief,
 		Password:       user.Password, // 注意安全风险
 		CollectionList: collectionResponses,
-		HistoryList:    historyResponses,
+
+		ContinueReading: continueResponses,
 	}
 
 	return response, nil
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"github.com/antidote-kt/SSE_Library-back/constant"
 	"github.com/antidote-kt/SSE_Library-back/controllers"
 	"github.com/antidote-kt/SSE_Library-back/middlewares"
 	"github.com/gin-gonic/gin"
@@ -9,6 +10,9 @@ import (
 func SetupRouter() *gin.Engine {
 	router := gin.Default()
 	router.Use(middlewares.CORSMiddleware())
+	// 使用本地存储驱动时的文件访问
+	router.GET(constant.LocalStorageRoutePrefix+"/*key", controllers.ServeLocalBlob)
+	router.PUT(constant.LocalStorageRoutePrefix+"/*key", controllers.ReceiveLocalBlob)
 	api := router.Group("/api")
 
 	api.POST("/login", controllers.Login)
@@ -19,6 +23,8 @@ func SetupRouter() *gin.Engine {
 	// AI 聊天测试接口
 	api.GET("/ai/chat/test", controllers.TestStreamChat)          // 测试 AI 聊天流式响应（直接构造请求示例）
 	api.GET("/ai/chat/test-title", controllers.TestGenerateTitle) // 测试会话标题生成
+	// 带水印的文档文件，凭下载接口签发的签名访问，浏览器阅读器无法携带登录凭证
+	api.GET("/document/:id/file", controllers.ServeDocumentFile)
 
 	// --- 需要认证才能访问的路由 ---
 	authed := api.Group("/")
@@ -29,15 +35,19 @@ func SetupRouter() *gin.Engine {
 		authed.GET("/ai/chat/sessions", controllers.GetAISessions)
 		authed.PUT("/ai/chat/sessions", controllers.UpdateAISession)
 		authed.DELETE("/ai/chat/sessions/:sessionId", controllers.DeleteAISessions)
+		authed.GET("/ai/chat/search", controllers.SearchAIMessages) // 搜索当前用户全部AI会话中的消息
 
 		// AI 消息接口
 		authed.POST("/ai/chat/sessions/:sessionId/stop", controllers.CancelAISessionStream)
 		authed.POST("/ai/chat/sessions/:sessionId/messages", controllers.SendAISessionMessages) // 用户发送问题并获取流式输出
-		authed.POST("/ai/:contentType/:contentId/summary", controllers.PostAISummary)          // 查看/生成摘要（JSON，Redis 缓存）
+		authed.POST("/ai/:contentType/:contentId/summary", controllers.PostAISummary)          // 查看/生成摘要（JSON，保存到摘要表）
+		authed.GET("/ai/summaries/:contentType/:contentId", controllers.GetAISummaryHistory)    // 查看摘要历史
 		authed.GET("/ai/chat/sessions/:sessionId/messages", controllers.GetAISessionMessages)   // 获取会话历史消息
+		authed.POST("/ai/chat/messages/:messageId/feedback", controllers.PostAIMessageFeedback) // 评价AI回答（点赞/点踩）
 
 		// AI推荐书籍接口
 		authed.GET("/ai/:userId/book-recommendations", controllers.GetBookRecommendations) // 获取书籍推荐
+		authed.GET("/recommendations", controllers.GetRecommendations)                     // 个性化推荐（书籍、文档、视频、帖子）
 
 		// 通用接口
 		authed.GET("/comment/:commentId", controllers.GetSingleComment)    // 获取单条评论
@@ -45,6 +55,14 @@ func SetupRouter() *gin.Engine {
 		authed.PUT("/user/:user_id", controllers.ModifyInfo)               // 修改个人资料
 		authed.GET("/document/:id", controllers.GetDocumentByID)           // 获取文档详情
 		authed.POST("/document/:id/summary/stream", controllers.StreamDocumentSummary) // PDF 正文摘要（SSE，StreamChat）
+		authed.POST("/document/:id/quiz/generate", controllers.GenerateDocumentQuiz)  // 根据文档生成测验和记忆卡片
+		authed.POST("/document/:id/quizzes", controllers.SaveDocumentQuiz)            // 保存测验
+		authed.GET("/document/:id/quizzes", controllers.GetDocumentQuizzes)           // 获取文档下自己保存的测验
+		authed.GET("/document/:id/quiz-attempts", controllers.GetDocumentQuizAttempts) // 获取文档下的答题成绩历史
+		authed.GET("/document/:id/metadata-suggestion", controllers.GetDocumentMetadataSuggestion) // 获取上传后 AI 生成的元数据建议（上传者和管理员）
+		authed.GET("/quizzes/:quizId", controllers.GetQuizForTaking)                  // 获取测验用于答题（不含答案）
+		authed.DELETE("/quizzes/:quizId", controllers.DeleteQuiz)                     // 删除测验
+		authed.POST("/quizzes/:quizId/attempts", controllers.SubmitQuizAttempt)       // 提交答题并判分
 		authed.GET("/searchdoc", controllers.SearchDocument)               // 搜索文档
 		authed.GET("/documents", controllers.GetDocumentList)              // 获取文档列表
 		authed.PUT("/document", controllers.ModifyDocument)                // 文件信息修改（上传该文件的用户才能修改）
@@ -71,6 +89,39 @@ func SetupRouter() *gin.Engine {
 		authed.POST("/markRead", controllers.MarkNotification)                      // 标记提醒为已读
 		authed.GET("/unreadMessage", controllers.GetUnreadMessage)                  // 获取总的未读消息（包括聊天记录和通知提醒）
 		authed.DELETE("/post", controllers.DeletePost)                              // 删除帖子
+		// 文档版本
+		authed.GET("/document/:id/versions", controllers.GetDocumentVersions)                          // 获取文档版本历史（上传者和管理员）
+		authed.GET("/document/:id/versions/:version/download", controllers.GetDocumentVersionDownload) // 获取指定版本的下载地址
+		authed.POST("/document/:id/versions/:version/rollback", controllers.RollbackDocumentVersion)   // 回滚到指定版本（重新审核、重新向量化）
+		// 文档下载
+		authed.GET("/document/:id/download", controllers.GetDocumentDownload) // 获取限时有效的下载地址（计入下载次数）
+		// 书籍信息
+		authed.GET("/isbn/:isbn", controllers.GetBookMetadata) // 根据 ISBN 查询书籍信息（上传书籍时自动填写）
+		// 引用导出
+		authed.GET("/document/:id/cite", controllers.GetDocumentCitation) // 生成文档的引用（bibtex、ris、gbt7714、apa）
+		// 阅读进度、书签和划线
+		authed.GET("/document/:id/reading", controllers.GetReadingState)              // 获取阅读进度、书签和划线（阅读器打开文档时）
+		authed.PUT("/document/:id/reading/progress", controllers.SyncReadingProgress) // 同步阅读进度
+		authed.GET("/reading/continue", controllers.GetContinueReading)               // 继续阅读列表（未读完的文档）
+		authed.POST("/document/:id/bookmarks", controllers.CreateReadingBookmark)     // 添加书签
+		authed.PUT("/bookmarks/:bookmarkId", controllers.UpdateReadingBookmark)       // 重命名书签
+		authed.DELETE("/bookmarks/:bookmarkId", controllers.DeleteReadingBookmark)    // 删除书签
+		authed.POST("/document/:id/highlights", controllers.CreateReadingHighlight)   // 添加划线和笔记
+		authed.PUT("/highlights/:highlightId", controllers.UpdateReadingHighlight)    // 修改划线的笔记和颜色
+		authed.DELETE("/highlights/:highlightId", controllers.DeleteReadingHighlight) // 删除划线
+		// 文档评分和评价
+		authed.PUT("/document/:id/review", controllers.SaveDocumentReview)           // 评价文档（已评价时修改原评价）
+		authed.GET("/document/:id/reviews", controllers.GetDocumentReviews)          // 评价列表及评分汇总
+		authed.DELETE("/reviews/:reviewId", controllers.DeleteDocumentReview)        // 删除评价（本人或管理员）
+		authed.POST("/reviews/:reviewId/helpful", controllers.VoteReviewHelpful)     // 标记评价有帮助
+		authed.DELETE("/reviews/:reviewId/helpful", controllers.CancelReviewHelpful) // 取消有帮助
+		// 分片上传和直传
+		authed.POST("/uploads", controllers.InitUploadSession)                            // 创建分片上传会话
+		authed.POST("/uploads/presign", controllers.PresignUpload)                        // 获取直传到存储的签名地址
+		authed.GET("/uploads/:uploadId", controllers.GetUploadSession)                    // 查询分片上传进度
+		authed.PUT("/uploads/:uploadId/parts/:partNumber", controllers.UploadSessionPart) // 上传分片（请求头 X-Part-SHA256 为分片校验和）
+		authed.POST("/uploads/:uploadId/complete", controllers.CompleteUploadSession)     // 合并分片，返回用于提交文档的 objectKey
+		authed.DELETE("/uploads/:uploadId", controllers.AbortUploadSession)               // 取消分片上传
 
 		// 用户相关操作
 		userApi := authed.Group("/user")
@@ -89,6 +140,8 @@ func SetupRouter() *gin.Engine {
 			userApi.DELETE("/like", controllers.DoUnlikePost)              // 取消点赞帖子
 			userApi.GET("/checkLike", controllers.GetPostLikeStatus)       // 判断帖子是否已点赞
 			userApi.GET("/postList/:userId", controllers.GetUserPostList)
+
+			userApi.GET("/favorites/cite", controllers.ExportFavoriteCitations) // 导出收藏文档的引用文件
 		}
 
 		// 管理员相关操作
@@ -101,6 +154,42 @@ func SetupRouter() *gin.Engine {
 			adminApi.GET("/docList", controllers.AdminGetDocumentList)              // 管理员获取文档列表
 			adminApi.GET("/comments", controllers.GetAllComments)                   // 管理员获取所有评论（需要认证）
 			adminApi.DELETE("/comment", controllers.DeleteComment)                  // 管理员删除评论（需要认证）
+			adminApi.GET("/ai/feedback", controllers.AdminGetAIFeedbackDashboard)   // AI回答质量看板
+			adminApi.GET("/category/:categoryId/persona", controllers.AdminGetCoursePersona)    // 获取课程助教设定
+			adminApi.PUT("/category/:categoryId/persona", controllers.AdminUpdateCoursePersona) // 修改课程助教设定
+			// 提示词模板管理
+			adminApi.GET("/prompts", controllers.AdminGetPromptTemplates)                                // 获取全部提示词及当前生效版本
+			adminApi.GET("/prompts/:key/versions", controllers.AdminGetPromptTemplateVersions)           // 获取提示词历史版本
+			adminApi.POST("/prompts/:key/versions", controllers.AdminCreatePromptTemplateVersion)        // 新增提示词版本
+			adminApi.DELETE("/prompts/:key/versions/:version", controllers.AdminDeletePromptTemplateVersion) // 删除提示词历史版本
+			adminApi.POST("/prompts/:key/preview", controllers.AdminPreviewPromptTemplate)              // 预览提示词渲染结果
+			adminApi.PUT("/prompts/:key/active", controllers.AdminRollbackPromptTemplate)               // 启用/回滚到指定版本
+			// 内容审核
+			adminApi.GET("/moderation", controllers.AdminGetModerationQueue)         // 审核队列（默认为待人工复核的内容）
+			adminApi.PUT("/moderation/:recordId", controllers.AdminReviewModeration) // 人工复核（通过/驳回）
+			// 敏感词管理
+			adminApi.GET("/sensitive-words", controllers.AdminGetSensitiveWords)                    // 查询敏感词
+			adminApi.POST("/sensitive-words", controllers.AdminCreateSensitiveWords)                // 批量添加敏感词
+			adminApi.DELETE("/sensitive-words/:wordId", controllers.AdminDeleteSensitiveWord)       // 删除敏感词
+			adminApi.POST("/sensitive-words/test", controllers.AdminTestSensitiveText)              // 测试文本的敏感词命中情况
+			adminApi.GET("/sensitive-word-categories", controllers.AdminGetSensitiveWordCategories) // 获取敏感词分类及处理方式
+			adminApi.PUT("/sensitive-word-categories", controllers.AdminSaveSensitiveWordCategory)  // 创建或修改敏感词分类的处理方式
+
+			adminApi.GET("/recommend-experiments", controllers.AdminGetRecommendExperiments)                              // 获取全部推荐实验
+			adminApi.POST("/recommend-experiments", controllers.AdminCreateRecommendExperiment)                           // 创建推荐实验
+			adminApi.PUT("/recommend-experiments/:experimentId", controllers.AdminUpdateRecommendExperiment)              // 修改尚未开始的推荐实验
+			adminApi.PUT("/recommend-experiments/:experimentId/status", controllers.AdminUpdateRecommendExperimentStatus) // 开始或结束推荐实验
+			adminApi.GET("/recommend-experiments/:experimentId/report", controllers.AdminGetRecommendExperimentReport)    // 推荐实验报告（各变体点击率、转化率及置信区间）
+
+			adminApi.POST("/document-versions/prune", controllers.AdminPruneDocumentVersions) // 按保留策略清理文档旧版本
+
+			adminApi.POST("/covers/generate", controllers.AdminGenerateCovers) // 为没有封面的文档生成封面，并补全封面和头像的缩略图
+
+			adminApi.POST("/documents/file-metadata/refresh", controllers.AdminRefreshDocumentFileMetadata) // 为历史文档提取页数、字数、语言等文件元数据
+
+			adminApi.POST("/documents/import", controllers.AdminImportDocuments)                      // 从 ZIP 和 CSV/JSON 清单批量导入文档（后台任务，支持试运行）
+			adminApi.GET("/documents/import/:jobId", controllers.AdminGetDocumentImport)              // 查询批量导入进度和结果
+			adminApi.GET("/documents/import/:jobId/report", controllers.AdminGetDocumentImportReport) // 下载批量导入的错误报告（CSV）
 		}
 	}
 
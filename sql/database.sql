@@ -218,3 +218,355 @@ CREATE TABLE notifications (
 
 
 
+-- AI 回复记录生成时使用的模型、提示词版本与检索上下文
+ALTER TABLE ai_messages
+    ADD COLUMN model VARCHAR(50) DEFAULT NULL COMMENT '生成该回答所用的模型',
+    ADD COLUMN prompt_version VARCHAR(100) DEFAULT NULL COMMENT '生成该回答所用的提示词版本',
+    ADD COLUMN retrieval_context LONGTEXT COMMENT '生成该回答时检索到的知识片段（JSON 数组）';
+
+CREATE TABLE ai_message_feedbacks (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '反馈记录ID',
+    ai_message_id BIGINT UNSIGNED NOT NULL COMMENT '被评价的AI回答ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '评价用户ID',
+    rating VARCHAR(10) NOT NULL COMMENT '评价：up-点赞，down-点踩',
+    reason TEXT COMMENT '评价原因（可选）',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '评价时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id),
+    KEY idx_feedback_created_at (created_at)
+) COMMENT='AI回答反馈表';
+-- 用户对同一回答只保留一条评价
+CREATE UNIQUE INDEX uk_message_user_feedback
+    ON ai_message_feedbacks (ai_message_id, user_id, (IF(deleted_at IS NULL, 1, NULL)));
+
+CREATE TABLE prompt_templates (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '提示词模板ID',
+    template_key VARCHAR(50) NOT NULL COMMENT '提示词标识：ai_chat_system, ai_session_title, document_summary_system, rag_augment, book_recommend_rerank, course_tutor_system, document_chunk_summary, summary_key_points, summary_outline, summary_glossary, quiz_generate, document_metadata_suggest, content_moderation',
+    version INT NOT NULL COMMENT '版本号，同一标识下从1递增',
+    content TEXT NOT NULL COMMENT '模板内容（text/template 语法，变量写作 {{.Name}}）',
+    description VARCHAR(255) DEFAULT NULL COMMENT '版本说明',
+    is_active TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否为当前启用版本',
+    creator_id BIGINT UNSIGNED NOT NULL COMMENT '创建该版本的管理员ID',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_prompt_key_version (template_key, version)
+) COMMENT='提示词模板版本表';
+
+
+-- 从课程创建的 AI 会话关联课程分类
+ALTER TABLE ai_sessions
+    ADD COLUMN category_id BIGINT UNSIGNED DEFAULT NULL COMMENT '课程分类ID，为空表示通用图书馆助手会话',
+    ADD KEY idx_ai_session_category (category_id);
+
+CREATE TABLE course_personas (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '助教设定ID',
+    category_id BIGINT UNSIGNED NOT NULL COMMENT '课程分类ID',
+    persona TEXT COMMENT '助教人设（语气、教学风格等）',
+    syllabus TEXT COMMENT '课程大纲，为空时根据课程下的子分类和资料自动生成',
+    updater_id BIGINT UNSIGNED NOT NULL COMMENT '最后修改的管理员ID',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id)
+) COMMENT='课程助教设定表';
+-- 每个课程只保留一条助教设定
+CREATE UNIQUE INDEX uk_course_persona_category
+    ON course_personas (category_id, (IF(deleted_at IS NULL, 1, NULL)));
+
+CREATE TABLE ai_summaries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '摘要ID',
+    content_type VARCHAR(20) NOT NULL COMMENT '内容类型：document, post',
+    content_id BIGINT UNSIGNED NOT NULL COMMENT '文档或帖子ID',
+    style VARCHAR(20) NOT NULL COMMENT '摘要风格：abstract-一段话摘要，key_points-要点列表，outline-章节大纲，glossary-术语表',
+    summary LONGTEXT NOT NULL COMMENT '摘要内容',
+    src_hash VARCHAR(64) NOT NULL COMMENT '生成时正文的 SHA-256，正文变化后需重新生成',
+    model VARCHAR(100) DEFAULT NULL COMMENT '生成该摘要所用的模型',
+    prompt_version VARCHAR(100) DEFAULT NULL COMMENT '生成该摘要所用的提示词版本',
+    creator_id BIGINT UNSIGNED NOT NULL COMMENT '触发生成的用户ID',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '生成时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id),
+    KEY idx_summary_content (content_type, content_id, style)
+) COMMENT='AI摘要表（保留历史版本，同一内容同一风格最新的一条为当前摘要）';
+
+CREATE TABLE quizzes (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '测验ID',
+    document_id BIGINT UNSIGNED NOT NULL COMMENT '出题文档ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '保存测验的用户ID',
+    title VARCHAR(100) NOT NULL COMMENT '测验标题',
+    start_page INT NOT NULL COMMENT '出题素材起始页',
+    end_page INT NOT NULL COMMENT '出题素材结束页',
+    content LONGTEXT NOT NULL COMMENT '题目和记忆卡片（JSON，含答案和出处页码）',
+    question_count INT NOT NULL DEFAULT 0 COMMENT '题目数量',
+    flashcard_count INT NOT NULL DEFAULT 0 COMMENT '记忆卡片数量',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id),
+    KEY idx_quiz_user_document (user_id, document_id)
+) COMMENT='测验表';
+
+CREATE TABLE quiz_attempts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '答题记录ID',
+    quiz_id BIGINT UNSIGNED NOT NULL COMMENT '测验ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '答题用户ID',
+    document_id BIGINT UNSIGNED NOT NULL COMMENT '测验所属文档ID',
+    answers LONGTEXT COMMENT '每道题的作答与判分结果（JSON）',
+    correct_count INT NOT NULL DEFAULT 0 COMMENT '答对题数',
+    total_count INT NOT NULL DEFAULT 0 COMMENT '总题数',
+    score DOUBLE NOT NULL DEFAULT 0 COMMENT '百分制得分',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '答题时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id),
+    KEY idx_attempt_quiz (quiz_id),
+    KEY idx_attempt_user_document (user_id, document_id)
+) COMMENT='测验答题记录表';
+
+-- 文档难度，可由上传者填写或采纳 AI 元数据建议
+ALTER TABLE documents
+    ADD COLUMN difficulty VARCHAR(20) DEFAULT NULL COMMENT '难度：beginner入门、intermediate进阶、advanced高级';
+
+CREATE TABLE document_metadata_suggestions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '建议ID',
+    document_id BIGINT UNSIGNED NOT NULL COMMENT '文档ID',
+    introduction TEXT COMMENT '建议简介',
+    tags TEXT COMMENT '建议标签（JSON数组，均为已有标签）',
+    new_tags TEXT COMMENT '建议新增的标签（JSON数组）',
+    suggested_category_id BIGINT UNSIGNED DEFAULT NULL COMMENT '建议分类ID，NULL表示没有合适的分类',
+    difficulty VARCHAR(20) DEFAULT NULL COMMENT '建议难度',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态：pending待处理、accepted已采纳',
+    accepted_fields VARCHAR(100) DEFAULT NULL COMMENT '已采纳的字段，逗号分隔',
+    model VARCHAR(100) DEFAULT NULL COMMENT '生成建议的模型',
+    prompt_version VARCHAR(100) DEFAULT NULL COMMENT '生成建议的提示词版本',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id)
+) COMMENT='文档元数据AI建议表';
+
+CREATE UNIQUE INDEX uk_metadata_suggestion_document ON document_metadata_suggestions (document_id, (IF(deleted_at IS NULL, 1, NULL)));
+
+-- 内容审核判定为高风险的帖子和评论自动隐藏
+ALTER TABLE posts
+    ADD COLUMN is_hidden TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否被内容审核隐藏';
+
+ALTER TABLE comments
+    ADD COLUMN is_hidden TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否被内容审核隐藏';
+
+CREATE TABLE moderation_records (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '审核记录ID',
+    content_type VARCHAR(20) NOT NULL COMMENT '内容类型：document、post、comment、message',
+    content_id BIGINT UNSIGNED NOT NULL COMMENT '内容ID',
+    author_id BIGINT UNSIGNED NOT NULL COMMENT '内容作者ID',
+    risk_score DOUBLE NOT NULL DEFAULT 0 COMMENT '风险分（0~1）',
+    categories VARCHAR(100) DEFAULT NULL COMMENT '命中的风险类别：spam、abuse、off_topic，逗号分隔',
+    reason VARCHAR(500) DEFAULT NULL COMMENT 'AI 给出的判断理由',
+    rule_hits TEXT COMMENT '命中的本地规则（JSON数组）',
+    ai_checked TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否经过 AI 审核（AI 不可用时仅依据本地规则）',
+    status VARCHAR(20) NOT NULL COMMENT '状态：pass自动通过、review待复核、hidden已自动隐藏、approved复核通过、rejected复核驳回',
+    reviewer_id BIGINT UNSIGNED DEFAULT NULL COMMENT '复核管理员ID',
+    review_note VARCHAR(500) DEFAULT NULL COMMENT '复核备注',
+    reviewed_at TIMESTAMP NULL DEFAULT NULL COMMENT '复核时间',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id),
+    KEY idx_moderation_status (status),
+    KEY idx_moderation_author (author_id)
+) COMMENT='内容审核记录表';
+
+CREATE UNIQUE INDEX uk_moderation_content ON moderation_records (content_type, content_id, (IF(deleted_at IS NULL, 1, NULL)));
+
+CREATE TABLE sensitive_words (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '敏感词ID',
+    word VARCHAR(100) NOT NULL COMMENT '敏感词（小写，已去掉空白和标点）',
+    category VARCHAR(50) NOT NULL COMMENT '所属分类',
+    creator_id BIGINT UNSIGNED NOT NULL COMMENT '添加者（管理员）ID',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id),
+    KEY idx_sensitive_word_category (category)
+) COMMENT='敏感词表';
+
+CREATE UNIQUE INDEX uk_sensitive_word ON sensitive_words (word, (IF(deleted_at IS NULL, 1, NULL)));
+
+CREATE TABLE sensitive_word_categories (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '分类ID',
+    name VARCHAR(50) NOT NULL COMMENT '分类名称',
+    action VARCHAR(20) NOT NULL COMMENT '处理方式：mask打码、reject拒绝提交、flag放行并进入人工复核',
+    description VARCHAR(200) DEFAULT NULL COMMENT '分类说明',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id)
+) COMMENT='敏感词分类表';
+
+CREATE UNIQUE INDEX uk_sensitive_word_category ON sensitive_word_categories (name, (IF(deleted_at IS NULL, 1, NULL)));
+
+CREATE TABLE recommend_experiments (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '实验ID',
+    name VARCHAR(100) NOT NULL COMMENT '实验名称，同时作为用户分桶哈希的盐',
+    description VARCHAR(500) DEFAULT NULL COMMENT '实验说明',
+    variants TEXT NOT NULL COMMENT '变体列表（JSON数组）：名称、策略（vector_only、llm_rerank、popularity、hybrid）和流量权重',
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' COMMENT '实验状态：draft草稿、running进行中、stopped已结束',
+    started_at TIMESTAMP NULL DEFAULT NULL COMMENT '开始时间',
+    stopped_at TIMESTAMP NULL DEFAULT NULL COMMENT '结束时间',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id)
+) COMMENT='书籍推荐A/B实验表';
+
+CREATE UNIQUE INDEX uk_recommend_experiment_name ON recommend_experiments (name, (IF(deleted_at IS NULL, 1, NULL)));
+
+CREATE TABLE recommend_impressions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '曝光记录ID',
+    experiment_id BIGINT UNSIGNED NOT NULL COMMENT '实验ID',
+    variant VARCHAR(50) NOT NULL COMMENT '用户所在的变体名称',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    item_type VARCHAR(20) NOT NULL COMMENT '推荐内容类型：document、post',
+    item_id BIGINT UNSIGNED NOT NULL COMMENT '推荐内容ID',
+    position INT NOT NULL COMMENT '在推荐列表中的位置，从1开始',
+    clicked_at TIMESTAMP NULL DEFAULT NULL COMMENT '曝光后浏览该内容的时间（归因窗口内）',
+    favorited_at TIMESTAMP NULL DEFAULT NULL COMMENT '曝光后收藏该内容的时间（归因窗口内）',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '曝光时间',
+    PRIMARY KEY (id),
+    KEY idx_impression_experiment (experiment_id, variant),
+    KEY idx_impression_user_item (user_id, item_type, item_id)
+) COMMENT='推荐曝光记录表';
+
+-- 文档当前使用的文件版本
+ALTER TABLE documents
+    ADD COLUMN version INT NOT NULL DEFAULT 1 COMMENT '当前文件对应的版本号（document_versions.version）';
+
+CREATE TABLE document_versions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '版本记录ID',
+    document_id BIGINT UNSIGNED NOT NULL COMMENT '文档ID',
+    version INT NOT NULL COMMENT '版本号，从1开始递增，清理后不复用',
+    type VARCHAR(20) NOT NULL COMMENT '该版本的资源类型：book、file、video',
+    url VARCHAR(500) NOT NULL COMMENT '文件在COS中的路径，视频为链接',
+    cover VARCHAR(500) DEFAULT NULL COMMENT '封面在COS中的路径',
+    file_size BIGINT NOT NULL DEFAULT 0 COMMENT '文件大小（字节），视频为0',
+    file_hash VARCHAR(64) DEFAULT NULL COMMENT '文件SHA-256',
+    uploader_id BIGINT UNSIGNED NOT NULL COMMENT '上传该版本的用户ID',
+    change_note VARCHAR(500) DEFAULT NULL COMMENT '版本说明',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '按保留策略清理的时间',
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_document_version (document_id, version),
+    KEY idx_document_version_deleted_at (deleted_at)
+) COMMENT='文档版本表';
+
+-- 文档文件的技术元数据：大小、类型和哈希在上传时记录，页数、字数、语言和 PDF 信息在学习文档时提取
+ALTER TABLE documents
+    ADD COLUMN file_size BIGINT NOT NULL DEFAULT 0 COMMENT '文件大小（字节），视频为0',
+    ADD COLUMN mime_type VARCHAR(100) DEFAULT NULL COMMENT '按文件内容检测出的类型',
+    ADD COLUMN content_hash VARCHAR(64) DEFAULT NULL COMMENT '文件SHA-256',
+    ADD COLUMN page_count INT NOT NULL DEFAULT 0 COMMENT '页数（仅PDF），0表示未知',
+    ADD COLUMN word_count INT NOT NULL DEFAULT 0 COMMENT '正文字数',
+    ADD COLUMN language VARCHAR(10) DEFAULT NULL COMMENT '正文语言（ISO 639-1），und表示无法判断',
+    ADD COLUMN pdf_title VARCHAR(500) DEFAULT NULL COMMENT 'PDF信息字典中的标题',
+    ADD COLUMN pdf_author VARCHAR(200) DEFAULT NULL COMMENT 'PDF信息字典中的作者',
+    ADD COLUMN pdf_created_at TIMESTAMP NULL DEFAULT NULL COMMENT 'PDF信息字典中的创建时间',
+    ADD KEY idx_page_count (page_count),
+    ADD KEY idx_language (language);
+
+-- 文档下载量，与阅读量分开统计
+ALTER TABLE documents
+    ADD COLUMN download_counts INT NOT NULL DEFAULT 0 COMMENT '下载次数（获取下载地址的次数）';
+
+CREATE TABLE document_downloads (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '下载记录ID',
+    document_id BIGINT UNSIGNED NOT NULL COMMENT '文档ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '下载用户ID',
+    version INT NOT NULL DEFAULT 1 COMMENT '下载时文档的版本号',
+    watermarked TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否为带水印的文件',
+    ip VARCHAR(64) DEFAULT NULL COMMENT '下载者IP',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下载时间',
+    PRIMARY KEY (id),
+    KEY idx_download_document (document_id, created_at),
+    KEY idx_download_user (user_id)
+) COMMENT='文档下载记录表';
+
+-- 出版社，书籍可根据 ISBN 从图书目录服务补全
+ALTER TABLE documents
+    ADD COLUMN publisher VARCHAR(200) DEFAULT NULL COMMENT '出版社';
+
+CREATE TABLE reading_progresses (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '阅读进度ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    document_id BIGINT UNSIGNED NOT NULL COMMENT '文档ID',
+    page INT NOT NULL DEFAULT 0 COMMENT '当前页码，没有页码的文档为0',
+    position VARCHAR(255) DEFAULT NULL COMMENT '阅读器中的精确位置（如EPUB CFI、滚动位置、视频秒数）',
+    percent DOUBLE NOT NULL DEFAULT 0 COMMENT '阅读进度百分比',
+    read_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '阅读器记录进度的时间，多端同步时以此判断新旧',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_reading_user_document (user_id, document_id),
+    KEY idx_reading_user_read_at (user_id, read_at)
+) COMMENT='阅读进度表';
+
+CREATE TABLE reading_bookmarks (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '书签ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    document_id BIGINT UNSIGNED NOT NULL COMMENT '文档ID',
+    name VARCHAR(100) NOT NULL COMMENT '书签名称',
+    page INT NOT NULL DEFAULT 0 COMMENT '页码',
+    position VARCHAR(255) DEFAULT NULL COMMENT '阅读器中的精确位置',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id),
+    KEY idx_bookmark_user_document (user_id, document_id)
+) COMMENT='书签表';
+
+CREATE TABLE reading_highlights (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '划线ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    document_id BIGINT UNSIGNED NOT NULL COMMENT '文档ID',
+    page INT NOT NULL DEFAULT 0 COMMENT '页码',
+    start_offset INT NOT NULL COMMENT '划线在页内的起始字符偏移',
+    end_offset INT NOT NULL COMMENT '划线在页内的结束字符偏移（不含）',
+    text TEXT NOT NULL COMMENT '划线的文本',
+    note TEXT COMMENT '笔记',
+    color VARCHAR(20) NOT NULL COMMENT '划线颜色',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT '软删除标记，（NULL表示未删除）',
+    PRIMARY KEY (id),
+    KEY idx_highlight_user_document (user_id, document_id)
+) COMMENT='划线笔记表';
+
+-- 文档评分汇总，评价增删改时重新计算
+ALTER TABLE documents
+    ADD COLUMN rating_average DECIMAL(3,2) NOT NULL DEFAULT 0 COMMENT '平均星级',
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0 COMMENT '评分人数',
+    ADD KEY idx_rating (rating_average, rating_count);
+
+CREATE TABLE document_reviews (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '评价ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '评价者ID',
+    document_id BIGINT UNSIGNED NOT NULL COMMENT '文档ID',
+    stars TINYINT NOT NULL COMMENT '星级（1-5）',
+    content TEXT COMMENT '评价内容，可以只评分不写评价',
+    helpful_count INT NOT NULL DEFAULT 0 COMMENT '认为有帮助的人数',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_review_user_document (user_id, document_id),
+    KEY idx_review_document (document_id)
+) COMMENT='文档评价表';
+
+CREATE TABLE review_helpful_votes (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '投票ID',
+    review_id BIGINT UNSIGNED NOT NULL COMMENT '评价ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '投票用户ID',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '投票时间',
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_helpful_review_user (review_id, user_id)
+) COMMENT='评价有帮助投票表';
@@ -2,15 +2,18 @@ package utils
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/antidote-kt/SSE_Library-back/constant"
@@ -19,14 +22,15 @@ import (
 	"github.com/tencentyun/cos-go-sdk-v5"
 )
 
-var (
-	cosClient *cos.Client
-	bucketURL string
-	once      sync.Once
-)
+// cosBlobStore 腾讯云 COS 存储驱动
+type cosBlobStore struct {
+	client    *cos.Client
+	secretID  string
+	secretKey string
+}
 
-// 初始化COS客户端（单例模式）
-func initCOSClient() {
+// 创建COS客户端
+func newCOSBlobStore() *cosBlobStore {
 	bucketName := viper.GetString("bucket.bucketName")
 	appID := viper.GetString("bucket.appID")
 	region := viper.GetString("bucket.region")
@@ -34,57 +38,102 @@ func initCOSClient() {
 	secretID := viper.GetString("bucket.secretID")
 	secretKey := viper.GetString("bucket.secretKey")
 
-	bucketURL = fmt.Sprintf("http://%s-%s.cos.%s.%s", bucketName, appID, region, domain)
+	bucketURL := fmt.Sprintf("http://%s-%s.cos.%s.%s", bucketName, appID, region, domain)
 	u, _ := url.Parse(bucketURL)
 	b := &cos.BaseURL{BucketURL: u}
-	cosClient = cos.NewClient(b, &http.Client{
+	client := cos.NewClient(b, &http.Client{
 		Transport: &cos.AuthorizationTransport{
 			SecretID:  secretID,
 			SecretKey: secretKey,
 		},
 	})
 	log.Println("COS 客户端初始化成功")
+	return &cosBlobStore{client: client, secretID: secretID, secretKey: secretKey}
 }
 
-// 获取COS客户端实例
-func getCOSClient() (*cos.Client, string) {
-	once.Do(initCOSClient)
-	return cosClient, bucketURL
+func (s *cosBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	opt := &cos.ObjectPutOptions{ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{ContentType: contentType}}
+	if size >= 0 {
+		opt.ObjectPutHeaderOptions.ContentLength = size
+	}
+	_, err := s.client.Object.Put(ctx, key, r, opt)
+	return err
 }
-func UploadFile(key string, file io.Reader) error {
-	// 对象键（Key）是对象在存储桶中的唯一标识。
-	client, _ := getCOSClient()
-	_, err := client.Object.Put(context.Background(), key, file, nil)
+
+func (s *cosBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.client.Object.Get(ctx, key, nil)
 	if err != nil {
-		log.Fatalf(err.Error())
+		if cos.IsNotFoundError(err) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, err
 	}
+	return resp.Body, nil
+}
+
+func (s *cosBlobStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.Object.Delete(ctx, key)
 	return err
 }
 
-func DeleteFile(filename string) error {
-	if filename == "" {
-		return nil
+func (s *cosBlobStore) Stat(ctx context.Context, key string) (BlobInfo, error) {
+	resp, err := s.client.Object.Head(ctx, key, nil)
+	if err != nil {
+		if cos.IsNotFoundError(err) {
+			return BlobInfo{}, ErrBlobNotFound
+		}
+		return BlobInfo{}, err
 	}
-	client, _ := getCOSClient()
-	_, err := client.Object.Delete(context.Background(), filename)
-	return err
+	lastModified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return BlobInfo{
+		Key:          key,
+		Size:         resp.ContentLength,
+		ContentType:  resp.Header.Get("Content-Type"),
+		LastModified: lastModified,
+	}, nil
+}
+
+func (s *cosBlobStore) URL(key string) string {
+	return s.client.Object.GetObjectURL(key).String()
 }
 
-// filename：文件在 COS 中的路径（如 "images/avatar.jpg"） 返回文件的完整 HTTP/HTTPS 访问地址
-func GetFileURL(filename string) string {
-	if filename == "" {
-		return ""
+func (s *cosBlobStore) SignedURL(ctx context.Context, key string, expire time.Duration) (string, error) {
+	u, err := s.client.Object.GetPresignedURL(ctx, http.MethodGet, key, s.secretID, s.secretKey, expire, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *cosBlobStore) SignedUploadURL(ctx context.Context, key string, expire time.Duration) (string, error) {
+	u, err := s.client.Object.GetPresignedURL(ctx, http.MethodPut, key, s.secretID, s.secretKey, expire, nil)
+	if err != nil {
+		return "", err
 	}
-	client, _ := getCOSClient()
-	oUrl := client.Object.GetObjectURL(filename)
-	return oUrl.String()
+	return u.String(), nil
 }
+
+// GetResponseFileURL 响应中返回的文档地址：视频返回外部链接，其他文档不再返回永久地址，而是返回获取限时下载地址的接口路径
 func GetResponseFileURL(document models.Document) string {
 	if document.Type == constant.VideoType {
 		return document.URL
 	} else {
-		return GetFileURL(document.URL)
+		return fmt.Sprintf(constant.DocumentDownloadPath, document.ID)
+	}
+}
+
+// HashUploadedFile 计算上传文件的 SHA-256，用于记录文档版本
+func HashUploadedFile(file *multipart.FileHeader) (string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, reader); err != nil {
+		return "", err
 	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
 // 上传主文件
@@ -98,10 +147,9 @@ func UploadMainFile(file *multipart.FileHeader, category string) (string, error)
 	}
 	defer fileReader.Close()
 
-	// 使用新的路径生成
-	secureFilename := generateSecureFilename(file.Filename)
-	filePath := fmt.Sprintf("files/%s/%s", category, secureFilename)
-	err = UploadFile(filePath, fileReader)
+	// 扩展名按文件内容确定，不使用客户端提供的文件名
+	filePath := fmt.Sprintf("files/%s/%s", category, generateSecureFilename(DetectFileExtension(file)))
+	err = UploadFileWithSize(filePath, fileReader, file.Size, file.Header.Get("Content-Type"))
 	if err != nil {
 		return "", fmt.Errorf(constant.DocumentUploadFailed)
 	}
@@ -109,6 +157,25 @@ func UploadMainFile(file *multipart.FileHeader, category string) (string, error)
 	return filePath, nil
 }
 
+// UploadMainFileFromPath 上传本地的文档主文件（如批量导入时从压缩包中解出的文件），ext 为按文件内容检测出的扩展名
+func UploadMainFileFromPath(filePath string, ext string, contentType string, category string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf(constant.DocumentOpenFailed)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf(constant.DocumentOpenFailed)
+	}
+
+	key := fmt.Sprintf("files/%s/%s", category, generateSecureFilename(ext))
+	if err := UploadFileWithSize(key, f, info.Size(), contentType); err != nil {
+		return "", fmt.Errorf(constant.DocumentUploadFailed)
+	}
+	return key, nil
+}
+
 // 上传封面图片
 func UploadCoverImage(cover *multipart.FileHeader, category string) (string, error) {
 	if cover == nil || cover.Size == 0 {
@@ -121,13 +188,16 @@ func UploadCoverImage(cover *multipart.FileHeader, category string) (string, err
 	}
 	defer coverFile.Close()
 
-	// 使用新的路径生成
-	secureFilename := generateSecureFilename(cover.Filename)
+	// 扩展名按文件内容确定，不使用客户端提供的文件名
+	secureFilename := generateSecureFilename(DetectFileExtension(cover))
 	coverPath := fmt.Sprintf("covers/%s/%s", category, secureFilename)
-	err = UploadFile(coverPath, coverFile)
+	err = UploadFileWithSize(coverPath, coverFile, cover.Size, cover.Header.Get("Content-Type"))
 	if err != nil {
 		return "", fmt.Errorf(constant.UploadCoverImageFailed)
 	}
+	if err := generateUploadedThumbnails(coverPath, cover, constant.CoverVariantWidths); err != nil {
+		return "", err
+	}
 
 	return coverPath, nil
 }
@@ -144,20 +214,47 @@ func UploadAvatar(avatar *multipart.FileHeader) (string, error) {
 	}
 	defer avatarFile.Close()
 
-	// 使用新的路径生成
-	secureFilename := generateSecureFilename(avatar.Filename)
-	avatarPath := fmt.Sprintf("avatars/%s", secureFilename)
-	err = UploadFile(avatarPath, avatarFile)
+	// 扩展名和类型按文件内容确定，不使用客户端提供的文件名和 Content-Type；
+	// 头像是公开文件，只允许图片，避免 HTML、SVG 等可执行脚本的文件被当作头像对外提供
+	fileType := DetectFileType(avatar)
+	if !slices.Contains(constant.AllowedCoverMIMETypes, fileType.MIME) {
+		return "", ErrAvatarTypeNotAllowed
+	}
+	avatarPath := fmt.Sprintf("avatars/%s", generateSecureFilename(fileType.Extension))
+	err = UploadFileWithSize(avatarPath, avatarFile, avatar.Size, fileType.MIME)
 	if err != nil {
 		return "", fmt.Errorf(constant.UploadAvatarFailed)
 	}
+	if err := generateUploadedThumbnails(avatarPath, avatar, constant.AvatarVariantWidths); err != nil {
+		return "", err
+	}
 
 	return avatarPath, nil
 }
 
-// 生成安全的文件名
-func generateSecureFilename(originalName string) string {
+// generateUploadedThumbnails 为刚上传的图片生成缩略图，图片无法解析时删除已上传的原图
+func generateUploadedThumbnails(key string, file *multipart.FileHeader, widths map[string]int) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf(constant.ImageDecodeFailed)
+	}
+	defer reader.Close()
+	if err := GenerateThumbnails(key, reader, widths); err != nil {
+		log.Printf("生成缩略图失败 %s: %v", key, err)
+		_ = DeleteFileWithThumbnails(key)
+		return fmt.Errorf(constant.ImageDecodeFailed)
+	}
+	return nil
+}
+
+// NewMainFileKey 生成分片上传或直传的主文件在存储中的路径，此时还没有文件内容，扩展名暂取自文件名（提交文档时校验内容后按检测出的类型重命名）
+func NewMainFileKey(originalName string, category string) string {
 	ext := strings.ToLower(filepath.Ext(originalName))
+	return fmt.Sprintf("files/%s/%s", category, generateSecureFilename(ext))
+}
+
+// 生成安全的文件名，ext 为带点的扩展名
+func generateSecureFilename(ext string) string {
 	timestamp := time.Now().UnixNano()
 	return fmt.Sprintf("%d%s", timestamp, ext)
 }
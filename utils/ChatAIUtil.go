@@ -51,6 +51,15 @@ type StreamResult struct {
 	ThinkingContent string
 }
 
+// GetChatModelName 获取当前配置的对话模型名称（未配置时默认为 qwen-plus）
+func GetChatModelName() string {
+	model := viper.GetString("dashscope.model")
+	if model == "" {
+		model = "qwen-plus"
+	}
+	return model
+}
+
 // processStreamResponse 处理流式响应并将数据发送到通道，同时收集完整内容
 func processStreamResponse(ctx context.Context, resp *http.Response, dataChan chan string, enableThinking bool, resultChan chan *StreamResult) {
 	defer close(dataChan)
@@ -141,10 +150,7 @@ func processStreamResponse(ctx context.Context, resp *http.Response, dataChan ch
 // 返回值: StreamResult（包含完整内容和思考内容）, error
 func StreamChatWithSessionID(c *gin.Context, sessionId string, messages []Message, enableThinking bool, customSystem ...string) (*StreamResult, error) {
 	// 从配置中读取参数
-	model := viper.GetString("dashscope.model")
-	if model == "" {
-		model = "qwen-plus"
-	}
+	model := GetChatModelName()
 	systemPrompt := constant.AIChatSystemPrompt
 	if len(customSystem) > 0 && strings.TrimSpace(customSystem[0]) != "" {
 		systemPrompt = customSystem[0]
@@ -251,10 +257,7 @@ func StreamChat(c *gin.Context, messages []Message, enableThinking bool, customS
 
 // Chat 非流式调用AI模型，返回完整内容
 func Chat(messages []Message) (string, error) {
-	model := viper.GetString("dashscope.model")
-	if model == "" {
-		model = "qwen-plus"
-	}
+	model := GetChatModelName()
 
 	apiKey := viper.GetString("dashscope.api_key")
 	if apiKey == "" {
@@ -308,12 +311,34 @@ func Chat(messages []Message) (string, error) {
 	return "", fmt.Errorf("no response from model")
 }
 
+// ParseModelJSON 解析模型输出的 JSON（兼容 ```json 代码块包裹），strict 为 true 时不允许出现未定义的字段
+func ParseModelJSON(output string, v any, strict bool) error {
+	output = strings.TrimSpace(output)
+	output = strings.TrimPrefix(output, "```json")
+	output = strings.TrimPrefix(output, "```")
+	output = strings.TrimSuffix(output, "```")
+
+	decoder := json.NewDecoder(strings.NewReader(strings.TrimSpace(output)))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("JSON 解析失败: %v", err)
+	}
+	return nil
+}
+
 // GenerateSessionTitle 根据用户输入生成会话标题
-func GenerateSessionTitle(userInput string) (string, error) {
+// customSystem 可选：非空时覆盖默认的标题生成提示词（如后台配置的提示词模板）。
+func GenerateSessionTitle(userInput string, customSystem ...string) (string, error) {
+	systemPrompt := constant.AISessionTitlePrompt
+	if len(customSystem) > 0 && strings.TrimSpace(customSystem[0]) != "" {
+		systemPrompt = customSystem[0]
+	}
 	messages := []Message{
 		{
 			Role:    "system",
-			Content: constant.AISessionTitlePrompt,
+			Content: systemPrompt,
 		},
 		{
 			Role:    "user",
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"github.com/antidote-kt/SSE_Library-back/constant"
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
@@ -83,15 +85,39 @@ func InsertChunks(fileID int64, chunks []string, vectors [][]float32) error {
 	return err
 }
 
+// DeleteChunks 删除文档在知识库中的全部分片，用于文档文件变更后重新向量化
+func DeleteChunks(fileID int64) error {
+	ctx := context.Background()
+	return MilvusClient.Delete(ctx, constant.CollectionName, "", fmt.Sprintf("file_id in [%d]", fileID))
+}
+
 // 相似度检索
 func SearchKnowledge(queryVector []float32, topK int) ([]string, error) {
+	return searchKnowledgeWithExpr(queryVector, topK, "")
+}
+
+// SearchKnowledgeInFiles 仅在指定文档（file_id）范围内做相似度检索，用于课程助教等限定资料范围的场景
+func SearchKnowledgeInFiles(queryVector []float32, topK int, fileIDs []int64) ([]string, error) {
+	if len(fileIDs) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(fileIDs))
+	for i, id := range fileIDs {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	expr := fmt.Sprintf("file_id in [%s]", strings.Join(ids, ","))
+	return searchKnowledgeWithExpr(queryVector, topK, expr)
+}
+
+// searchKnowledgeWithExpr 按过滤表达式在知识库集合中做相似度检索，expr 为空表示全库检索
+func searchKnowledgeWithExpr(queryVector []float32, topK int, expr string) ([]string, error) {
 	ctx := context.Background()
 	sp, _ := entity.NewIndexHNSWSearchParam(74) // 创建HNSW索引搜索参数(ef=74)
 
 	searchResult, err := MilvusClient.Search(
 		ctx, constant.CollectionName, // 1. collName: 集合名称
 		[]string{},          // 2. partitions: 分区列表，传空数组代表全库检索，不做条件过滤
-		"",                  // 3. expr表达式过滤，如果想要限定某个 file_id 可以在这里写 "file_id == 1"
+		expr,                // 3. expr表达式过滤，如果想要限定某个 file_id 可以在这里写 "file_id == 1"
 		[]string{"content"}, // 4. outputFields: 需要一同返回的标量字段
 		[]entity.Vector{entity.FloatVector(queryVector)}, // 5. vectors: 要查询的向量列表
 		"vector",  // 6. vectorField: 数据库里存向量的字段名
@@ -139,6 +165,15 @@ func InsertBookVector(bookID int64, content string, vector []float32) error {
 	return err
 }
 
+// UpsertBookVector 更新单本书籍的向量信息（先删除旧向量再插入），用于书籍简介等元数据修改后重新向量化
+func UpsertBookVector(bookID int64, content string, vector []float32) error {
+	ctx := context.Background()
+	if err := MilvusClient.Delete(ctx, constant.BookCollectionName, "", fmt.Sprintf("book_id in [%d]", bookID)); err != nil {
+		return fmt.Errorf("Milvus 删除旧书籍向量失败: %v", err)
+	}
+	return InsertBookVector(bookID, content, vector)
+}
+
 // SearchBooks 相似度检索推荐书籍ID
 func SearchBooks(queryVector []float32, topK int) ([]int64, error) {
 	ctx := context.Background()
@@ -12,10 +12,10 @@ import (
 	"github.com/ledongthuc/pdf"
 )
 
-// 下载 COS 文件到本地临时文件
-func DownloadFromCOSToTemp(cosUrl string) (string, error) {
+// 通过 HTTP 下载外部链接到本地临时文件
+func downloadURLToTemp(fileURL string) (string, error) {
 	// 1. 发起 HTTP GET 请求
-	resp, err := http.Get(cosUrl)
+	resp, err := http.Get(fileURL)
 	if err != nil {
 		return "", fmt.Errorf("HTTP请求失败: %v", err)
 	}
@@ -59,6 +59,30 @@ func ExtractTextFromPDF(filePath string) (string, error) {
 	return buf.String(), nil
 }
 
+// ExtractPagesFromPDF 按页提取 PDF 文本，返回值下标 i 对应第 i+1 页（空白页为空字符串）
+func ExtractPagesFromPDF(filePath string) ([]string, error) {
+	f, r, err := pdf.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pages := make([]string, r.NumPage())
+	for i := 1; i <= r.NumPage(); i++ {
+		page := r.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		text, err := page.GetPlainText(nil)
+		if err != nil {
+			// 单页解析失败不影响其他页
+			continue
+		}
+		pages[i-1] = text
+	}
+	return pages, nil
+}
+
 // CleanText 对原始文本进行预清洗，提高 Embedding 精准度
 var (
 	// 匹配两个及以上的换行符
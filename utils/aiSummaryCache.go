@@ -4,61 +4,38 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/antidote-kt/SSE_Library-back/config"
 	"github.com/go-redis/redis/v8"
 )
 
-const aiSummaryCacheTTL = 7 * 24 * time.Hour
-
-// AISummaryCacheValue Redis 中缓存的摘要（不写业务表，仅 KV）。
-type AISummaryCacheValue struct {
-	Summary   string `json:"summary"`
-	SummaryID uint64 `json:"summaryId"`
-	SrcHash   string `json:"srcHash"`
-}
-
-// HashSourceText 用于判断正文是否变化，避免旧缓存命中。
+// HashSourceText 用于判断正文是否变化，正文变化后已保存的摘要不再复用。
 func HashSourceText(s string) string {
 	h := sha256.Sum256([]byte(s))
 	return hex.EncodeToString(h[:])
 }
 
-func aiSummaryRedisKey(contentType string, contentID uint64) string {
-	return fmt.Sprintf("ai_summary:%s:%d", contentType, contentID)
+// aiSummaryChunkCacheTTL 分段摘要缓存时间，重新生成全文摘要时可直接复用
+const aiSummaryChunkCacheTTL = 30 * 24 * time.Hour
+
+// aiSummaryChunkRedisKey 分段摘要按「提示词版本 + 分段正文」的 hash 缓存，正文或提示词变化时自动失效
+func aiSummaryChunkRedisKey(promptVersion, chunk string) string {
+	return "ai_summary_chunk:" + HashSourceText(promptVersion+"\n"+chunk)
 }
 
-// GetAISummaryFromCache 若存在且 srcHash 一致则返回缓存；否则 nil。
-func GetAISummaryFromCache(ctx context.Context, contentType string, contentID uint64, currentSrcHash string) (*AISummaryCacheValue, error) {
+// GetChunkSummaryFromCache 获取分段摘要缓存，不存在时返回空字符串
+func GetChunkSummaryFromCache(ctx context.Context, promptVersion, chunk string) (string, error) {
 	rdb := config.GetRedisClient()
-	key := aiSummaryRedisKey(contentType, contentID)
-	raw, err := rdb.Get(ctx, key).Result()
+	summary, err := rdb.Get(ctx, aiSummaryChunkRedisKey(promptVersion, chunk)).Result()
 	if err == redis.Nil {
-		return nil, nil
+		return "", nil
 	}
-	if err != nil {
-		return nil, err
-	}
-	var v AISummaryCacheValue
-	if err := json.Unmarshal([]byte(raw), &v); err != nil {
-		return nil, err
-	}
-	if v.SrcHash != currentSrcHash {
-		return nil, nil
-	}
-	return &v, nil
+	return summary, err
 }
 
-// SetAISummaryCache 写入摘要缓存。
-func SetAISummaryCache(ctx context.Context, contentType string, contentID uint64, v *AISummaryCacheValue) error {
+// SetChunkSummaryCache 写入分段摘要缓存
+func SetChunkSummaryCache(ctx context.Context, promptVersion, chunk, summary string) error {
 	rdb := config.GetRedisClient()
-	key := aiSummaryRedisKey(contentType, contentID)
-	b, err := json.Marshal(v)
-	if err != nil {
-		return err
-	}
-	return rdb.Set(ctx, key, string(b), aiSummaryCacheTTL).Err()
+	return rdb.Set(ctx, aiSummaryChunkRedisKey(promptVersion, chunk), summary, aiSummaryChunkCacheTTL).Err()
 }
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrBlobNotFound         = errors.New("对象不存在")
+	ErrBlobSignatureInvalid = errors.New("访问地址签名无效或已过期")
+)
+
+// BlobInfo 存储中对象的基本信息
+type BlobInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// BlobStore 对象存储接口，key 为对象在存储中的路径（如 "files/数学/123.pdf"），数据库中保存的就是 key
+type BlobStore interface {
+	// Put 上传对象，size 未知时传 -1
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取对象，调用方负责关闭；对象不存在时返回 ErrBlobNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除对象，对象不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// Stat 获取对象信息，对象不存在时返回 ErrBlobNotFound
+	Stat(ctx context.Context, key string) (BlobInfo, error)
+	// URL 对象的公开访问地址
+	URL(key string) string
+	// SignedURL 带签名、在 expire 后失效的访问地址
+	SignedURL(ctx context.Context, key string, expire time.Duration) (string, error)
+	// SignedUploadURL 带签名、在 expire 后失效的上传地址，客户端用 PUT 方法直接上传到存储
+	SignedUploadURL(ctx context.Context, key string, expire time.Duration) (string, error)
+}
+
+var (
+	blobStore     BlobStore
+	blobStoreErr  error
+	blobStoreOnce sync.Once
+)
+
+// newBlobStore 根据配置文件 storage.driver 创建存储驱动，未配置时使用腾讯云 COS（兼容原有的 bucket 配置）
+func newBlobStore() (BlobStore, error) {
+	driver := viper.GetString("storage.driver")
+	switch driver {
+	case "", constant.StorageDriverCOS:
+		return newCOSBlobStore(), nil
+	case constant.StorageDriverLocal:
+		return newLocalBlobStore()
+	case constant.StorageDriverS3:
+		return newS3BlobStore()
+	}
+	return nil, fmt.Errorf("不支持的存储驱动: %s", driver)
+}
+
+// InitBlobStore 初始化对象存储，配置有误时返回错误
+func InitBlobStore() error {
+	blobStoreOnce.Do(func() {
+		blobStore, blobStoreErr = newBlobStore()
+		if blobStoreErr == nil {
+			log.Printf("对象存储初始化成功，驱动: %s", viper.GetString("storage.driver"))
+		}
+	})
+	return blobStoreErr
+}
+
+// GetBlobStore 获取对象存储实例
+func GetBlobStore() (BlobStore, error) {
+	if err := InitBlobStore(); err != nil {
+		return nil, err
+	}
+	return blobStore, nil
+}
+
+// UploadFile 上传文件到对象存储
+func UploadFile(key string, file io.Reader) error {
+	return UploadFileWithSize(key, file, -1, "")
+}
+
+// UploadFileWithSize 上传已知大小和类型的文件到对象存储
+func UploadFileWithSize(key string, file io.Reader, size int64, contentType string) error {
+	store, err := GetBlobStore()
+	if err != nil {
+		return err
+	}
+	return store.Put(context.Background(), key, file, size, contentType)
+}
+
+// DeleteFile 从对象存储删除文件，filename 为空时不做任何操作
+func DeleteFile(filename string) error {
+	if filename == "" {
+		return nil
+	}
+	store, err := GetBlobStore()
+	if err != nil {
+		return err
+	}
+	return store.Delete(context.Background(), filename)
+}
+
+// GetFileURL filename：文件在对象存储中的路径（如 "images/avatar.jpg"） 返回文件的完整 HTTP/HTTPS 访问地址
+func GetFileURL(filename string) string {
+	if filename == "" {
+		return ""
+	}
+	store, err := GetBlobStore()
+	if err != nil {
+		log.Printf("获取对象存储失败: %v", err)
+		return ""
+	}
+	return store.URL(filename)
+}
+
+// GetSignedFileURL 获取文件带签名、限时有效的访问地址
+func GetSignedFileURL(filename string, expire time.Duration) (string, error) {
+	store, err := GetBlobStore()
+	if err != nil {
+		return "", err
+	}
+	return store.SignedURL(context.Background(), filename, expire)
+}
+
+// HashBlob 计算存储中对象的 SHA-256
+func HashBlob(key string) (string, error) {
+	store, err := GetBlobStore()
+	if err != nil {
+		return "", err
+	}
+	reader, err := store.Get(context.Background(), key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// StatBlob 获取存储中对象的信息
+func StatBlob(key string) (BlobInfo, error) {
+	store, err := GetBlobStore()
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	return store.Stat(context.Background(), key)
+}
+
+// blobKeyFromURL 将对象存储的公开访问地址还原为 key；传入的本身就是 key 时原样返回，外部链接返回 false
+func blobKeyFromURL(store BlobStore, fileURL string) (string, bool) {
+	prefix := store.URL("")
+	if prefix != "" && strings.HasPrefix(fileURL, prefix) {
+		key, _, _ := strings.Cut(strings.TrimPrefix(fileURL, prefix), "?")
+		if unescaped, err := url.PathUnescape(key); err == nil {
+			key = unescaped
+		}
+		return key, true
+	}
+	if strings.HasPrefix(fileURL, "http://") || strings.HasPrefix(fileURL, "https://") {
+		return "", false
+	}
+	return fileURL, true
+}
+
+// DownloadFromCOSToTemp 下载对象存储中的文件（传入 key 或公开访问地址均可）到本地临时文件，外部链接直接通过 HTTP 下载
+func DownloadFromCOSToTemp(cosUrl string) (string, error) {
+	store, err := GetBlobStore()
+	if err != nil {
+		return "", err
+	}
+	key, ok := blobKeyFromURL(store, cosUrl)
+	if !ok {
+		return downloadURLToTemp(cosUrl)
+	}
+
+	reader, err := store.Get(context.Background(), key)
+	if err != nil {
+		return "", fmt.Errorf("文件下载失败: %v", err)
+	}
+	defer reader.Close()
+
+	tmpFile, err := os.CreateTemp("", "kb-*.pdf")
+	if err != nil {
+		return "", fmt.Errorf("创建临时文件失败: %v", err)
+	}
+	defer tmpFile.Close()
+
+	if _, err := io.Copy(tmpFile, reader); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", fmt.Errorf("写入本地文件失败: %v", err)
+	}
+	return tmpFile.Name(), nil
+}
@@ -0,0 +1,287 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+)
+
+// ErrBookNotFound 目录服务中没有该 ISBN
+var ErrBookNotFound = errors.New("未查询到该 ISBN 的书籍信息")
+
+// BookMetadata 根据 ISBN 查询到的书籍信息
+type BookMetadata struct {
+	ISBN        string   `json:"isbn"` // ISBN-13
+	Title       string   `json:"title"`
+	Authors     []string `json:"authors"`
+	Publisher   string   `json:"publisher"`
+	PublishYear string   `json:"publishYear"`
+	CoverURL    string   `json:"coverUrl"`
+	Description string   `json:"description"`
+}
+
+// AuthorNames 作者列表，多个作者以顿号分隔，截断到文档作者字段的长度
+func (m *BookMetadata) AuthorNames() string {
+	return truncateRunes(strings.Join(m.Authors, "、"), 100)
+}
+
+// BookCatalogue 图书目录服务接口
+type BookCatalogue interface {
+	// LookupISBN 查询书籍信息，isbn 为 ISBN-13，查询不到时返回 ErrBookNotFound
+	LookupISBN(ctx context.Context, isbn string) (*BookMetadata, error)
+}
+
+// noopCatalogue 未配置目录服务时使用，所有 ISBN 都查询不到
+type noopCatalogue struct{}
+
+func (noopCatalogue) LookupISBN(ctx context.Context, isbn string) (*BookMetadata, error) {
+	return nil, ErrBookNotFound
+}
+
+// openLibraryCatalogue 通过 Open Library 的 Books API（/api/books?bibkeys=ISBN:...&jscmd=details）查询，
+// baseURL 可以指向兼容该接口的本地服务
+type openLibraryCatalogue struct {
+	baseURL  string
+	coverURL string
+	client   *http.Client
+}
+
+// NewOpenLibraryCatalogue 创建 Open Library 客户端，coverURL 为封面图片地址模板（%d 为封面 ID）
+func NewOpenLibraryCatalogue(baseURL string, coverURL string, timeout time.Duration) BookCatalogue {
+	if baseURL == "" {
+		baseURL = constant.DefaultOpenLibraryBaseURL
+	}
+	if coverURL == "" {
+		coverURL = constant.DefaultOpenLibraryCoverURL
+	}
+	if timeout <= 0 {
+		timeout = catalogueTimeout()
+	}
+	return &openLibraryCatalogue{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		coverURL: coverURL,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// catalogueTimeout 请求目录服务（查询和下载封面）的超时时间
+func catalogueTimeout() time.Duration {
+	if seconds := viper.GetInt("catalogue.timeout_seconds"); seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return constant.DefaultCatalogueTimeoutSeconds * time.Second
+}
+
+// openLibraryBook Books API jscmd=details 返回的单本书
+type openLibraryBook struct {
+	Details struct {
+		Title    string `json:"title"`
+		Subtitle string `json:"subtitle"`
+		Authors  []struct {
+			Name string `json:"name"`
+		} `json:"authors"`
+		ByStatement string          `json:"by_statement"`
+		Publishers  []string        `json:"publishers"`
+		PublishDate string          `json:"publish_date"`
+		Covers      []int64         `json:"covers"`
+		Description json.RawMessage `json:"description"` // 字符串或 {"type": ..., "value": ...}
+	} `json:"details"`
+}
+
+var publishYearPattern = regexp.MustCompile(`\d{4}`)
+
+func (c *openLibraryCatalogue) LookupISBN(ctx context.Context, isbn string) (*BookMetadata, error) {
+	bibkey := "ISBN:" + isbn
+	query := url.Values{"bibkeys": {bibkey}, "format": {"json"}, "jscmd": {"details"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/books?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("目录服务返回 %d", resp.StatusCode)
+	}
+
+	var result map[string]openLibraryBook
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return nil, err
+	}
+	book, ok := result[bibkey]
+	if !ok || strings.TrimSpace(book.Details.Title) == "" {
+		return nil, ErrBookNotFound
+	}
+
+	metadata := &BookMetadata{
+		ISBN:        isbn,
+		Title:       strings.TrimSpace(book.Details.Title),
+		PublishYear: publishYearPattern.FindString(book.Details.PublishDate),
+		Description: truncateRunes(parseOpenLibraryText(book.Details.Description), constant.MaxCatalogueIntroductionRunes),
+	}
+	if subtitle := strings.TrimSpace(book.Details.Subtitle); subtitle != "" {
+		metadata.Title += "：" + subtitle
+	}
+	metadata.Title = truncateRunes(metadata.Title, 200)
+	for _, author := range book.Details.Authors {
+		if name := strings.TrimSpace(author.Name); name != "" {
+			metadata.Authors = append(metadata.Authors, name)
+		}
+	}
+	if len(metadata.Authors) == 0 && book.Details.ByStatement != "" {
+		metadata.Authors = []string{strings.TrimSpace(book.Details.ByStatement)}
+	}
+	if len(book.Details.Publishers) > 0 {
+		metadata.Publisher = truncateRunes(strings.TrimSpace(book.Details.Publishers[0]), 200)
+	}
+	if len(book.Details.Covers) > 0 && book.Details.Covers[0] > 0 {
+		metadata.CoverURL = fmt.Sprintf(c.coverURL, book.Details.Covers[0])
+	}
+	return metadata, nil
+}
+
+// parseOpenLibraryText 解析字符串或 {"value": ...} 形式的文本字段
+func parseOpenLibraryText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text)
+	}
+	var typed struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &typed); err == nil {
+		return strings.TrimSpace(typed.Value)
+	}
+	return ""
+}
+
+var (
+	bookCatalogue     BookCatalogue
+	bookCatalogueErr  error
+	bookCatalogueOnce sync.Once
+)
+
+// newBookCatalogue 根据配置文件 catalogue.driver 创建目录服务客户端，未配置时不查询
+func newBookCatalogue() (BookCatalogue, error) {
+	driver := viper.GetString("catalogue.driver")
+	switch driver {
+	case "", constant.CatalogueDriverNone:
+		return noopCatalogue{}, nil
+	case constant.CatalogueDriverOpenLibrary:
+		return NewOpenLibraryCatalogue(viper.GetString("catalogue.base_url"), viper.GetString("catalogue.cover_url"), catalogueTimeout()), nil
+	}
+	return nil, fmt.Errorf("不支持的图书目录驱动: %s", driver)
+}
+
+// InitBookCatalogue 初始化图书目录服务客户端，配置有误时返回错误
+func InitBookCatalogue() error {
+	bookCatalogueOnce.Do(func() {
+		bookCatalogue, bookCatalogueErr = newBookCatalogue()
+		if bookCatalogueErr == nil {
+			log.Printf("图书目录服务初始化成功，驱动: %s", viper.GetString("catalogue.driver"))
+		}
+	})
+	return bookCatalogueErr
+}
+
+// GetBookCatalogue 获取图书目录服务客户端
+func GetBookCatalogue() (BookCatalogue, error) {
+	if err := InitBookCatalogue(); err != nil {
+		return nil, err
+	}
+	return bookCatalogue, nil
+}
+
+// LookupBookMetadata 根据 ISBN-13 查询书籍信息，结果按 ISBN 缓存在 Redis 中（查询不到的 ISBN 也缓存一段时间）。
+// 查询不到时返回 ErrBookNotFound，目录服务出错时不缓存
+func LookupBookMetadata(isbn string) (*BookMetadata, error) {
+	rdb := config.GetRedisClient()
+	key := constant.ISBNCacheKeyPrefix + isbn
+	data, err := rdb.Get(config.Ctx, key).Bytes()
+	if err == nil {
+		var metadata *BookMetadata
+		if err := json.Unmarshal(data, &metadata); err == nil {
+			if metadata == nil {
+				return nil, ErrBookNotFound
+			}
+			return metadata, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		log.Printf("[ISBN] 读取缓存失败: %v", err)
+	}
+
+	catalogue, err := GetBookCatalogue()
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := catalogue.LookupISBN(context.Background(), isbn)
+	ttl := constant.ISBNCacheTTLHours * time.Hour
+	if errors.Is(err, ErrBookNotFound) {
+		metadata, ttl = nil, constant.ISBNNotFoundCacheTTLHours*time.Hour
+	} else if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(metadata); err == nil {
+		if err := rdb.Set(config.Ctx, key, data, ttl).Err(); err != nil {
+			log.Printf("[ISBN] 写入缓存失败: %v", err)
+		}
+	}
+	if metadata == nil {
+		return nil, ErrBookNotFound
+	}
+	return metadata, nil
+}
+
+// DownloadCatalogueCover 下载目录服务提供的封面，按上传封面的规则校验后存入 covers/分类/ 并生成缩略图，返回封面路径
+func DownloadCatalogueCover(coverURL string, category string) (string, error) {
+	client := &http.Client{Timeout: catalogueTimeout()}
+	resp, err := client.Get(coverURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("下载封面返回 %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, constant.MaxCatalogueCoverSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > constant.MaxCatalogueCoverSize {
+		return "", errors.New("封面图片过大")
+	}
+
+	checked, err := checkUpload(bytes.NewReader(data), int64(len(data)), constant.AllowedCoverMIMETypes, ErrCoverTypeNotAllowed)
+	if err != nil {
+		return "", err
+	}
+	coverPath := fmt.Sprintf("covers/%s/%s", category, generateSecureFilename(checked.Extension))
+	if err := UploadFileWithSize(coverPath, bytes.NewReader(data), int64(len(data)), checked.MIME); err != nil {
+		return "", fmt.Errorf(constant.UploadCoverImageFailed)
+	}
+	if err := GenerateThumbnails(coverPath, bytes.NewReader(data), constant.CoverVariantWidths); err != nil {
+		_ = DeleteFileWithThumbnails(coverPath)
+		return "", fmt.Errorf(constant.ImageDecodeFailed)
+	}
+	return coverPath, nil
+}
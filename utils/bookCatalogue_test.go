@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newFakeOpenLibrary 模拟 Open Library 的 Books API，只认识 books 中的 ISBN
+func newFakeOpenLibrary(t *testing.T, books map[string]string, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/books" || r.URL.Query().Get("jscmd") != "details" {
+			http.NotFound(w, r)
+			return
+		}
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		bibkey := r.URL.Query().Get("bibkeys")
+		w.Header().Set("Content-Type", "application/json")
+		if book, ok := books[bibkey]; ok {
+			_, _ = w.Write([]byte(`{"` + bibkey + `": ` + book + `}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenLibraryLookupFound(t *testing.T) {
+	server := newFakeOpenLibrary(t, map[string]string{
+		"ISBN:9780306406157": `{"details": {
+			"title": "Modern Physics",
+			"subtitle": "An Introduction",
+			"authors": [{"name": "Alice"}, {"name": " Bob "}],
+			"publishers": ["Plenum Press"],
+			"publish_date": "June 1981",
+			"covers": [12345],
+			"description": {"type": "/type/text", "value": " A textbook. "}
+		}}`,
+	}, 0)
+	catalogue := NewOpenLibraryCatalogue(server.URL, server.URL+"/covers/%d.jpg", time.Second)
+
+	metadata, err := catalogue.LookupISBN(context.Background(), "9780306406157")
+	if err != nil {
+		t.Fatalf("LookupISBN unexpected error: %v", err)
+	}
+	if metadata.ISBN != "9780306406157" {
+		t.Errorf("ISBN = %q", metadata.ISBN)
+	}
+	if metadata.Title != "Modern Physics：An Introduction" {
+		t.Errorf("Title = %q", metadata.Title)
+	}
+	if metadata.AuthorNames() != "Alice、Bob" {
+		t.Errorf("AuthorNames = %q", metadata.AuthorNames())
+	}
+	if metadata.Publisher != "Plenum Press" || metadata.PublishYear != "1981" {
+		t.Errorf("Publisher = %q, PublishYear = %q", metadata.Publisher, metadata.PublishYear)
+	}
+	if metadata.CoverURL != server.URL+"/covers/12345.jpg" {
+		t.Errorf("CoverURL = %q", metadata.CoverURL)
+	}
+	if metadata.Description != "A textbook." {
+		t.Errorf("Description = %q", metadata.Description)
+	}
+}
+
+func TestOpenLibraryLookupNotFound(t *testing.T) {
+	server := newFakeOpenLibrary(t, map[string]string{
+		"ISBN:9780804429573": `{"details": {"title": " "}}`,
+	}, 0)
+	catalogue := NewOpenLibraryCatalogue(server.URL, "", time.Second)
+
+	for _, isbn := range []string{"9780306406157", "9780804429573"} {
+		if _, err := catalogue.LookupISBN(context.Background(), isbn); !errors.Is(err, ErrBookNotFound) {
+			t.Errorf("LookupISBN(%q) error = %v, want ErrBookNotFound", isbn, err)
+		}
+	}
+}
+
+func TestOpenLibraryLookupTimeout(t *testing.T) {
+	server := newFakeOpenLibrary(t, map[string]string{
+		"ISBN:9780306406157": `{"details": {"title": "Modern Physics"}}`,
+	}, time.Second)
+	catalogue := NewOpenLibraryCatalogue(server.URL, "", 50*time.Millisecond)
+
+	start := time.Now()
+	_, err := catalogue.LookupISBN(context.Background(), "9780306406157")
+	if err == nil || errors.Is(err, ErrBookNotFound) {
+		t.Fatalf("LookupISBN error = %v, want timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("LookupISBN took %v, want it to give up after the client timeout", elapsed)
+	}
+}
@@ -0,0 +1,494 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/spf13/viper"
+)
+
+var (
+	// 作者之间的分隔符：出现分号或顿号时逗号视为西文姓名“姓, 名”中的逗号，不用于分隔作者
+	citationStrongSeparator = regexp.MustCompile(`\s*(?:[;；、&＆]|\s+and\s+)\s*`)
+	citationAuthorSeparator = regexp.MustCompile(`\s*(?:[;；、,，/&＆]|\s+and\s+)\s*`)
+	// 作者前的国别，如 [美]、（英）
+	citationNationality = regexp.MustCompile(`^[\[［(（【〔][^\]］)）】〕]{1,4}[\]］)）】〕]\s*`)
+	citationEtAl        = regexp.MustCompile(`(?i)\s*(?:et\s+al\.?|等)$`)
+)
+
+// citationAuthor 引用中的一位作者，中文姓名不拆分姓和名
+type citationAuthor struct {
+	Name   string
+	Family string // 西文姓名的姓
+	Given  string // 西文姓名的名
+	Han    bool   // 中文姓名
+}
+
+// citationEntry 生成引用所需的文档信息
+type citationEntry struct {
+	ID        uint64
+	Type      string
+	Title     string
+	Authors   []citationAuthor
+	EtAl      bool // 作者字段注明了“等”，实际作者多于列出的作者
+	Publisher string
+	Year      string
+	ISBN      string
+	URL       string
+	Chinese   bool // 中文文献，决定“等”和 et al、“佚名”和 Anon 等用语
+}
+
+// IsCitationFormat 判断是否为支持的引用格式
+func IsCitationFormat(format string) bool {
+	_, ok := constant.CitationFileTypes[format]
+	return ok
+}
+
+// FormatCitation 按指定格式生成一篇文档的引用
+func FormatCitation(document models.Document, format string) string {
+	entry := newCitationEntry(document)
+	switch format {
+	case constant.CitationFormatBibTeX:
+		return formatBibTeX(entry)
+	case constant.CitationFormatRIS:
+		return formatRIS(entry)
+	case constant.CitationFormatAPA:
+		return formatAPA(entry)
+	}
+	return formatGBT7714(entry, time.Now())
+}
+
+// FormatCitations 按指定格式生成多篇文档的引用，GB/T 7714 按顺序编号
+func FormatCitations(documents []models.Document, format string) string {
+	var b strings.Builder
+	for i, document := range documents {
+		citation := FormatCitation(document, format)
+		switch format {
+		case constant.CitationFormatBibTeX, constant.CitationFormatRIS:
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(citation)
+		case constant.CitationFormatGBT7714:
+			fmt.Fprintf(&b, "[%d] %s\n", i+1, citation)
+		default:
+			b.WriteString(citation + "\n")
+		}
+	}
+	return b.String()
+}
+
+// newCitationEntry 从文档信息构建引用条目，作者留空时使用 PDF 信息字典中的作者
+func newCitationEntry(document models.Document) citationEntry {
+	entry := citationEntry{
+		ID:        document.ID,
+		Type:      document.Type,
+		Title:     collapseSpaces(document.Name),
+		Publisher: collapseSpaces(document.Publisher),
+		Year:      publishYearPattern.FindString(document.CreateYear),
+		ISBN:      strings.TrimSpace(document.BookISBN),
+	}
+	author := strings.TrimSpace(document.Author)
+	if author == "" || author == constant.DefaultAuthor {
+		author = strings.TrimSpace(document.PDFAuthor)
+	}
+	entry.Authors, entry.EtAl = parseCitationAuthors(author)
+
+	// 书籍以出版年份为准，其他文档没有填写年份时使用 PDF 的创建时间
+	if entry.Year == "" && document.Type != constant.BookType && document.PDFCreatedAt != nil {
+		entry.Year = strconv.Itoa(document.PDFCreatedAt.Year())
+	}
+	if document.Type == constant.VideoType {
+		entry.URL = document.URL
+	} else if template := viper.GetString("citation.document_url"); template != "" {
+		entry.URL = fmt.Sprintf(template, document.ID)
+	}
+
+	entry.Chinese = strings.HasPrefix(document.Language, "zh") || containsHan(entry.Title)
+	if !entry.Chinese && document.Language == "" && len(entry.Authors) > 0 {
+		entry.Chinese = entry.Authors[0].Han
+	}
+	return entry
+}
+
+// parseCitationAuthors 拆分作者字段：支持顿号、分号、逗号、and 等分隔，
+// 去掉国别（如 [美]）和“著”“主编”等责任方式，译者不列为作者，末尾的“等”或 et al 记为 etAl
+func parseCitationAuthors(text string) (authors []citationAuthor, etAl bool) {
+	if text == "" || text == constant.DefaultAuthor {
+		return nil, false
+	}
+	var parts []string
+	switch {
+	case strings.ContainsAny(text, ";；、"):
+		parts = citationStrongSeparator.Split(text, -1)
+	case isInvertedLatinName(text):
+		parts = []string{text}
+	default:
+		parts = citationAuthorSeparator.Split(text, -1)
+	}
+
+	for _, part := range parts {
+		part = citationNationality.ReplaceAllString(strings.TrimSpace(part), "")
+		if strings.HasSuffix(part, "译") {
+			continue
+		}
+		for _, role := range constant.CitationAuthorRoles {
+			if strings.HasSuffix(part, role) {
+				part = strings.TrimSpace(strings.TrimSuffix(part, role))
+				break
+			}
+		}
+		if citationEtAl.MatchString(part) {
+			etAl = true
+			part = citationEtAl.ReplaceAllString(part, "")
+		}
+		if part == "" || part == constant.DefaultAuthor || strings.EqualFold(part, "others") {
+			continue
+		}
+		authors = append(authors, newCitationAuthor(part))
+	}
+	return authors, etAl
+}
+
+// isInvertedLatinName 判断是否为“姓, 名”形式的单个西文姓名，如 Knuth, Donald E.
+func isInvertedLatinName(text string) bool {
+	family, given, ok := strings.Cut(text, ",")
+	return ok && !strings.Contains(given, ",") && !containsHan(text) &&
+		len(strings.Fields(family)) == 1 && strings.TrimSpace(given) != ""
+}
+
+// newCitationAuthor 解析一位作者的姓名：中文姓名去掉空格后整体使用，西文姓名拆分为姓和名
+func newCitationAuthor(name string) citationAuthor {
+	if containsHan(name) {
+		return citationAuthor{Name: strings.Join(strings.Fields(name), ""), Han: true}
+	}
+	name = collapseSpaces(name)
+	if family, given, ok := strings.Cut(name, ","); ok {
+		return citationAuthor{Name: name, Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)}
+	}
+	// 姓前小写的前缀（如 de、van）属于姓
+	fields := strings.Fields(name)
+	split := len(fields) - 1
+	for split > 1 && unicode.IsLower([]rune(fields[split-1])[0]) {
+		split--
+	}
+	return citationAuthor{
+		Name:   name,
+		Family: strings.Join(fields[split:], " "),
+		Given:  strings.Join(fields[:split], " "),
+	}
+}
+
+// initials 西文名的首字母，如 Randal E. -> R. E.（dot 为 false 时为 R E），带连字符的名保留连字符，如 J.-P.
+func (a citationAuthor) initials(dot bool) string {
+	var names []string
+	for _, field := range strings.Fields(a.Given) {
+		var parts []string
+		for _, part := range strings.Split(field, "-") {
+			r := []rune(strings.Trim(part, "."))
+			if len(r) == 0 {
+				continue
+			}
+			initial := string(unicode.ToUpper(r[0]))
+			if dot {
+				initial += "."
+			}
+			parts = append(parts, initial)
+		}
+		if len(parts) > 0 {
+			names = append(names, strings.Join(parts, "-"))
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// formatBibTeX BibTeX 条目：书籍为 @book，其他文档为 @misc；中文姓名加花括号，避免被拆分为姓和名
+func formatBibTeX(entry citationEntry) string {
+	entryType := "misc"
+	if entry.Type == constant.BookType {
+		entryType = "book"
+	}
+	key := "doc"
+	if len(entry.Authors) > 0 && !entry.Authors[0].Han {
+		if family := asciiLetters(entry.Authors[0].Family); family != "" {
+			key = strings.ToLower(family)
+		}
+	}
+	key = fmt.Sprintf("%s%s_%d", key, entry.Year, entry.ID)
+
+	var authors []string
+	for _, author := range entry.Authors {
+		switch {
+		case author.Han:
+			authors = append(authors, "{"+escapeBibTeX(author.Name)+"}")
+		case author.Given != "":
+			authors = append(authors, escapeBibTeX(author.Family+", "+author.Given))
+		default:
+			authors = append(authors, escapeBibTeX(author.Family))
+		}
+	}
+	if entry.EtAl && len(authors) > 0 {
+		authors = append(authors, "others")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@%s{%s,\n", entryType, key)
+	field := func(name string, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "  %s = {%s},\n", name, value)
+		}
+	}
+	// 标题加双层花括号，保留大小写
+	field("title", "{"+escapeBibTeX(entry.Title)+"}")
+	field("author", strings.Join(authors, " and "))
+	field("publisher", escapeBibTeX(entry.Publisher))
+	field("year", entry.Year)
+	field("isbn", entry.ISBN)
+	field("url", entry.URL)
+	if entry.Chinese {
+		field("language", "zh")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// formatRIS RIS 条目：书籍为 BOOK，视频为 VIDEO，其他文档为 ELEC
+func formatRIS(entry citationEntry) string {
+	risType := "ELEC"
+	switch entry.Type {
+	case constant.BookType:
+		risType = "BOOK"
+	case constant.VideoType:
+		risType = "VIDEO"
+	}
+
+	var b strings.Builder
+	tag := func(name string, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s  - %s\n", name, value)
+		}
+	}
+	tag("TY", risType)
+	for _, author := range entry.Authors {
+		if author.Han || author.Given == "" {
+			tag("AU", author.Name)
+		} else {
+			tag("AU", author.Family+", "+author.Given)
+		}
+	}
+	tag("TI", entry.Title)
+	tag("PB", entry.Publisher)
+	tag("PY", entry.Year)
+	tag("SN", entry.ISBN)
+	tag("UR", entry.URL)
+	if entry.Chinese {
+		tag("LA", "zh")
+	}
+	tag("ID", strconv.FormatUint(entry.ID, 10))
+	b.WriteString("ER  - \n")
+	return b.String()
+}
+
+// formatGBT7714 GB/T 7714-2015 著录格式：书籍为 [M]，有访问地址的其他文档为 [EB/OL] 并注明引用日期。
+// 列出前 3 位责任者，超过时加“等”或 et al；西文姓名姓全部大写、名缩写为首字母，如 BRYANT R E
+func formatGBT7714(entry citationEntry, now time.Time) string {
+	var authors []string
+	for i, author := range entry.Authors {
+		if i == constant.CitationMaxAuthors {
+			break
+		}
+		switch {
+		case author.Han:
+			authors = append(authors, author.Name)
+		case author.Given != "":
+			authors = append(authors, strings.ToUpper(author.Family)+" "+author.initials(false))
+		default:
+			authors = append(authors, strings.ToUpper(author.Family))
+		}
+	}
+	authorText := strings.Join(authors, ", ")
+	if len(authors) > 0 && (entry.EtAl || len(entry.Authors) > constant.CitationMaxAuthors) {
+		authorText += localized(entry.Chinese, ", 等", ", et al")
+	}
+	if authorText == "" {
+		authorText = localized(entry.Chinese, "佚名", "Anon")
+	}
+
+	var publication []string
+	if entry.Publisher != "" {
+		publication = append(publication, localized(entry.Chinese, "[出版地不详]", "[S.l.]")+": "+entry.Publisher)
+	}
+	if entry.Year != "" {
+		publication = append(publication, entry.Year)
+	}
+	publicationText := strings.Join(publication, ", ")
+
+	var b strings.Builder
+	b.WriteString(endSentence(authorText) + " " + entry.Title)
+	switch {
+	case entry.Type == constant.BookType:
+		b.WriteString("[M]")
+		if publicationText != "" {
+			b.WriteString(". " + publicationText)
+		}
+		b.WriteString(".")
+	case entry.URL != "":
+		b.WriteString("[EB/OL]. ")
+		if publicationText != "" {
+			b.WriteString(publicationText)
+		}
+		fmt.Fprintf(&b, "[%s]. %s.", now.Format("2006-01-02"), entry.URL)
+	default:
+		b.WriteString("[Z]")
+		if publicationText != "" {
+			b.WriteString(". " + publicationText)
+		}
+		b.WriteString(".")
+	}
+	return b.String()
+}
+
+// formatAPA APA 第 7 版格式。西文文献作者为“姓, 名首字母”，最后一位作者前加 &；
+// 中文文献按中文 APA 的习惯使用全名、顿号和全角标点，如 周志明（2019）。深入理解Java虚拟机。机械工业出版社。
+func formatAPA(entry citationEntry) string {
+	var authors []string
+	for _, author := range entry.Authors {
+		if author.Han || author.Given == "" {
+			authors = append(authors, author.Name)
+		} else {
+			authors = append(authors, author.Family+", "+author.initials(true))
+		}
+	}
+
+	if entry.Chinese {
+		year := entry.Year
+		if year == "" {
+			year = "无日期"
+		}
+		authorText := strings.Join(authors, "、")
+		if entry.EtAl && authorText != "" {
+			authorText += "等"
+		}
+		parts := []string{authorText + "（" + year + "）", entry.Title}
+		if authors == nil {
+			parts[0], parts[1] = entry.Title+"（"+year+"）", ""
+		}
+		if entry.Publisher != "" {
+			parts = append(parts, entry.Publisher)
+		}
+		var b strings.Builder
+		for _, part := range parts {
+			if part != "" {
+				b.WriteString(part + "。")
+			}
+		}
+		if entry.URL != "" {
+			b.WriteString(entry.URL)
+		}
+		return b.String()
+	}
+
+	// APA 列出最多 20 位作者，超过时列出前 19 位、省略号和最后一位
+	if len(authors) > 20 {
+		authors = append(authors[:19], ". . . "+authors[len(authors)-1])
+	}
+	var authorText string
+	switch len(authors) {
+	case 0:
+	case 1:
+		authorText = authors[0]
+	default:
+		last := authors[len(authors)-1]
+		if !strings.HasPrefix(last, ". . . ") {
+			last = "& " + last
+		}
+		authorText = strings.Join(authors[:len(authors)-1], ", ") + ", " + last
+	}
+	year := "(n.d.)"
+	if entry.Year != "" {
+		year = "(" + entry.Year + ")"
+	}
+
+	var b strings.Builder
+	if authorText == "" {
+		b.WriteString(endSentence(entry.Title) + " " + year + ".")
+	} else {
+		b.WriteString(endSentence(authorText) + " " + year + ". " + endSentence(entry.Title))
+	}
+	if entry.Publisher != "" {
+		b.WriteString(" " + endSentence(entry.Publisher))
+	}
+	if entry.URL != "" {
+		b.WriteString(" " + entry.URL)
+	}
+	return b.String()
+}
+
+// escapeBibTeX 转义 BibTeX 中的特殊字符
+func escapeBibTeX(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\textbackslash{}`)
+		case '~':
+			b.WriteString(`\textasciitilde{}`)
+		case '^':
+			b.WriteString(`\textasciicircum{}`)
+		case '{', '}', '&', '%', '$', '#', '_':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// endSentence 在文本末尾加句点，已以句末标点结尾时不重复添加
+func endSentence(s string) string {
+	if s == "" || strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") {
+		return s
+	}
+	return s + "."
+}
+
+// localized 按文献语言选择用语
+func localized(chinese bool, zh string, en string) string {
+	if chinese {
+		return zh
+	}
+	return en
+}
+
+// containsHan 判断文本中是否含有汉字
+func containsHan(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// asciiLetters 只保留 ASCII 字母，用于生成 BibTeX 条目的键
+func asciiLetters(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// collapseSpaces 去掉首尾空白并把连续的空白（包括换行）合并为一个空格
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+)
+
+func TestParseCitationAuthors(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		authors []citationAuthor
+		etAl    bool
+	}{
+		{
+			name: "国别、著和译者",
+			text: "[美] Randal E. Bryant、David R. O'Hallaron 著；龚奕利 译",
+			authors: []citationAuthor{
+				{Name: "Randal E. Bryant", Family: "Bryant", Given: "Randal E."},
+				{Name: "David R. O'Hallaron", Family: "O'Hallaron", Given: "David R."},
+			},
+		},
+		{name: "全角括号的国别", text: "（英）乔治·奥威尔", authors: []citationAuthor{{Name: "乔治·奥威尔", Han: true}}},
+		{name: "主编", text: "周志明 主编", authors: []citationAuthor{{Name: "周志明", Han: true}}},
+		{
+			name:    "中文姓名末尾的等",
+			text:    "张三, 李四等",
+			authors: []citationAuthor{{Name: "张三", Han: true}, {Name: "李四", Han: true}},
+			etAl:    true,
+		},
+		{
+			name: "et al",
+			text: "Alice Smith, Bob Jones, et al.",
+			authors: []citationAuthor{
+				{Name: "Alice Smith", Family: "Smith", Given: "Alice"},
+				{Name: "Bob Jones", Family: "Jones", Given: "Bob"},
+			},
+			etAl: true,
+		},
+		{
+			name:    "姓, 名形式的单个西文姓名",
+			text:    "Knuth, Donald E.",
+			authors: []citationAuthor{{Name: "Knuth, Donald E.", Family: "Knuth", Given: "Donald E."}},
+		},
+		{
+			name:    "姓前的小写前缀",
+			text:    "Ludwig van Beethoven",
+			authors: []citationAuthor{{Name: "Ludwig van Beethoven", Family: "van Beethoven", Given: "Ludwig"}},
+		},
+		{name: "空字符串", text: ""},
+		{name: "默认作者", text: constant.DefaultAuthor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authors, etAl := parseCitationAuthors(tt.text)
+			if !reflect.DeepEqual(authors, tt.authors) {
+				t.Errorf("parseCitationAuthors(%q) authors = %+v, want %+v", tt.text, authors, tt.authors)
+			}
+			if etAl != tt.etAl {
+				t.Errorf("parseCitationAuthors(%q) etAl = %v, want %v", tt.text, etAl, tt.etAl)
+			}
+		})
+	}
+}
+
+func TestCitationAuthorInitials(t *testing.T) {
+	tests := []struct {
+		name  string
+		given string
+		dot   bool
+		want  string
+	}{
+		{name: "带句点", given: "Randal E.", dot: true, want: "R. E."},
+		{name: "不带句点", given: "Randal E.", dot: false, want: "R E"},
+		{name: "带连字符的名", given: "Jean-Pierre", dot: true, want: "J.-P."},
+		{name: "带连字符的名不带句点", given: "Jean-Pierre", dot: false, want: "J-P"},
+		{name: "小写的名", given: "donald ervin", dot: true, want: "D. E."},
+		{name: "空", given: "", dot: true, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := citationAuthor{Given: tt.given}.initials(tt.dot)
+			if got != tt.want {
+				t.Errorf("initials(%q, %v) = %q, want %q", tt.given, tt.dot, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeBibTeX(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "普通文本", s: "Computer Systems", want: "Computer Systems"},
+		{name: "特殊字符", s: "50% & $x_1$ #1", want: `50\% \& \$x\_1\$ \#1`},
+		{name: "花括号", s: "{C++}", want: `\{C++\}`},
+		{name: "反斜杠、波浪号和脱字符", s: `a\b~c^d`, want: `a\textbackslash{}b\textasciitilde{}c\textasciicircum{}d`},
+		{name: "中文", s: "深入理解Java虚拟机", want: "深入理解Java虚拟机"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeBibTeX(tt.s); got != tt.want {
+				t.Errorf("escapeBibTeX(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatGBT7714(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		authors string
+		entry   citationEntry
+		want    string
+	}{
+		{
+			name:    "超过 3 位作者只列出前 3 位",
+			authors: "Randal E. Bryant, David R. O'Hallaron, Alice Smith, Bob Jones",
+			entry:   citationEntry{Type: constant.BookType, Title: "Computer Systems", Publisher: "Pearson", Year: "2016"},
+			want:    "BRYANT R E, O'HALLARON D R, SMITH A, et al. Computer Systems[M]. [S.l.]: Pearson, 2016.",
+		},
+		{
+			name:    "中文文献超过 3 位作者",
+			authors: "张三、李四、王五、赵六",
+			entry:   citationEntry{Type: constant.BookType, Title: "数据结构", Publisher: "清华大学出版社", Year: "2020", Chinese: true},
+			want:    "张三, 李四, 王五, 等. 数据结构[M]. [出版地不详]: 清华大学出版社, 2020.",
+		},
+		{
+			name:    "正好 3 位作者",
+			authors: "张三、李四、王五",
+			entry:   citationEntry{Type: constant.BookType, Title: "数据结构", Year: "2020", Chinese: true},
+			want:    "张三, 李四, 王五. 数据结构[M]. 2020.",
+		},
+		{
+			name:    "作者字段注明了等",
+			authors: "周志明 等",
+			entry:   citationEntry{Type: constant.BookType, Title: "深入理解Java虚拟机", Chinese: true},
+			want:    "周志明, 等. 深入理解Java虚拟机[M].",
+		},
+		{
+			name:  "有访问地址的文档",
+			entry: citationEntry{Type: constant.FileType, Title: "课程讲义", URL: "https://example.com/document/1", Chinese: true},
+			want:  "佚名. 课程讲义[EB/OL]. [2026-03-15]. https://example.com/document/1.",
+		},
+		{
+			name:    "没有访问地址的文档",
+			authors: "Knuth, Donald E.",
+			entry:   citationEntry{Type: constant.FileType, Title: "Lecture Notes", Year: "1998"},
+			want:    "KNUTH D E. Lecture Notes[Z]. 1998.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := tt.entry
+			entry.Authors, entry.EtAl = parseCitationAuthors(tt.authors)
+			if got := formatGBT7714(entry, now); got != tt.want {
+				t.Errorf("formatGBT7714() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAPA(t *testing.T) {
+	tests := []struct {
+		name    string
+		authors string
+		entry   citationEntry
+		want    string
+	}{
+		{
+			name:    "中文文献",
+			authors: "周志明 著",
+			entry:   citationEntry{Title: "深入理解Java虚拟机", Publisher: "机械工业出版社", Year: "2019", Chinese: true},
+			want:    "周志明（2019）。深入理解Java虚拟机。机械工业出版社。",
+		},
+		{
+			name:    "中文文献多位作者和等",
+			authors: "张三、李四等",
+			entry:   citationEntry{Title: "数据结构", Year: "2020", Chinese: true},
+			want:    "张三、李四等（2020）。数据结构。",
+		},
+		{
+			name:  "中文文献没有作者和年份",
+			entry: citationEntry{Title: "课程讲义", Publisher: "软件学院", Chinese: true},
+			want:  "课程讲义（无日期）。软件学院。",
+		},
+		{
+			name:    "西文文献",
+			authors: "Randal E. Bryant, David R. O'Hallaron",
+			entry:   citationEntry{Title: "Computer Systems", Publisher: "Pearson", Year: "2016"},
+			want:    "Bryant, R. E., & O'Hallaron, D. R. (2016). Computer Systems. Pearson.",
+		},
+		{
+			name:  "西文文献没有作者和年份",
+			entry: citationEntry{Title: "Lecture Notes"},
+			want:  "Lecture Notes. (n.d.).",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := tt.entry
+			entry.Authors, entry.EtAl = parseCitationAuthors(tt.authors)
+			if got := formatAPA(entry); got != tt.want {
+				t.Errorf("formatAPA() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
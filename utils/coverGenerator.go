@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/spf13/viper"
+)
+
+var (
+	errPDFRendererDisabled = errors.New("未启用 PDF 首页渲染")
+	errSVGRendererDisabled = errors.New("未启用文字封面渲染")
+)
+
+// GenerateDocumentCover 为没有封面的文档生成封面并返回封面在存储中的路径：
+// PDF 渲染首页，渲染失败或其他类型生成带名称和作者的文字封面（渲染为 PNG，渲染器不可用时保存 SVG）
+func GenerateDocumentCover(document models.Document, category string) (string, error) {
+	if document.Type != constant.VideoType && strings.EqualFold(path.Ext(document.URL), ".pdf") {
+		coverPath, err := generatePDFCover(document.URL, category)
+		if err == nil {
+			return coverPath, nil
+		}
+		if !errors.Is(err, errPDFRendererDisabled) {
+			log.Printf("[Cover] 文档 %d 首页渲染失败，改为生成文字封面: %v", document.ID, err)
+		}
+	}
+
+	svg := buildTypographicCover(document.Name, document.Author, document.Type)
+	coverPath, err := uploadRasterCover(svg, category)
+	if err == nil {
+		return coverPath, nil
+	}
+	if !errors.Is(err, errSVGRendererDisabled) {
+		log.Printf("[Cover] 文档 %d 文字封面渲染失败，改为保存 SVG: %v", document.ID, err)
+	}
+
+	// 渲染器不可用时保存 SVG（矢量图，各规格直接使用原图）
+	coverPath = fmt.Sprintf("covers/%s/%s", category, generateSecureFilename(".svg"))
+	if err := UploadFileWithSize(coverPath, bytes.NewReader(svg), int64(len(svg)), "image/svg+xml"); err != nil {
+		return "", err
+	}
+	return coverPath, nil
+}
+
+// uploadRasterCover 把文字封面渲染为 PNG 上传并生成缩略图
+func uploadRasterCover(svg []byte, category string) (string, error) {
+	data, err := renderSVG(svg)
+	if err != nil {
+		return "", err
+	}
+	coverPath := fmt.Sprintf("covers/%s/%s", category, generateSecureFilename(".png"))
+	if err := UploadFileWithSize(coverPath, bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		return "", err
+	}
+	if err := GenerateThumbnails(coverPath, bytes.NewReader(data), constant.CoverVariantWidths); err != nil {
+		_ = DeleteFileWithThumbnails(coverPath)
+		return "", err
+	}
+	return coverPath, nil
+}
+
+// generatePDFCover 渲染 PDF 首页为 JPEG 封面并生成缩略图
+func generatePDFCover(documentURL string, category string) (string, error) {
+	tmpPath, err := DownloadFromCOSToTemp(documentURL)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpPath)
+
+	data, err := renderPDFFirstPage(tmpPath)
+	if err != nil {
+		return "", err
+	}
+	coverPath := fmt.Sprintf("covers/%s/%s", category, generateSecureFilename(".jpg"))
+	if err := UploadFileWithSize(coverPath, bytes.NewReader(data), int64(len(data)), "image/jpeg"); err != nil {
+		return "", err
+	}
+	if err := GenerateThumbnails(coverPath, bytes.NewReader(data), constant.CoverVariantWidths); err != nil {
+		_ = DeleteFileWithThumbnails(coverPath)
+		return "", err
+	}
+	return coverPath, nil
+}
+
+// renderPDFFirstPage 调用配置文件 cover.pdf_renderer 指定的 pdftoppm 渲染 PDF 首页，设为 none 时不渲染
+func renderPDFFirstPage(pdfPath string) ([]byte, error) {
+	renderer := viper.GetString("cover.pdf_renderer")
+	if renderer == "" {
+		renderer = constant.DefaultPDFRenderer
+	}
+	if renderer == "none" {
+		return nil, errPDFRendererDisabled
+	}
+	if _, err := exec.LookPath(renderer); err != nil {
+		return nil, fmt.Errorf("%w: %v", errPDFRendererDisabled, err)
+	}
+
+	outDir, err := os.MkdirTemp("", "cover-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(outDir)
+	outPrefix := filepath.Join(outDir, "cover")
+
+	ctx, cancel := context.WithTimeout(context.Background(), constant.PDFRenderTimeoutSeconds*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, renderer, "-f", "1", "-l", "1", "-singlefile", "-jpeg",
+		"-scale-to-x", strconv.Itoa(constant.PDFCoverRenderWidth), "-scale-to-y", "-1", pdfPath, outPrefix)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return os.ReadFile(outPrefix + ".jpg")
+}
+
+// renderSVG 调用配置文件 cover.svg_renderer 指定的 rsvg-convert 把文字封面渲染为 PNG，设为 none 时不渲染
+func renderSVG(svg []byte) ([]byte, error) {
+	renderer := viper.GetString("cover.svg_renderer")
+	if renderer == "" {
+		renderer = constant.DefaultSVGRenderer
+	}
+	if renderer == "none" {
+		return nil, errSVGRendererDisabled
+	}
+	if _, err := exec.LookPath(renderer); err != nil {
+		return nil, fmt.Errorf("%w: %v", errSVGRendererDisabled, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constant.SVGRenderTimeoutSeconds*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, renderer, "-f", "png", "-w", strconv.Itoa(constant.GeneratedCoverRenderWidth))
+	cmd.Stdin = bytes.NewReader(svg)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return data, nil
+}
+
+// buildTypographicCover 生成文字封面（SVG）：上方为文档类型，中间为名称，下方为作者，配色按名称选择
+func buildTypographicCover(title string, author string, docType string) []byte {
+	h := fnv.New32a()
+	h.Write([]byte(title))
+	palette := constant.GeneratedCoverPalettes[h.Sum32()%uint32(len(constant.GeneratedCoverPalettes))]
+	background, foreground := palette[0], palette[1]
+
+	const (
+		margin        = 60
+		titleFontSize = 56
+		titleLeading  = 76
+	)
+	width, height := constant.GeneratedCoverWidth, constant.GeneratedCoverHeight
+	lines := wrapCoverTitle(title, float64(width-2*margin)/titleFontSize, constant.GeneratedCoverTitleMaxLines)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	b.WriteString(`<style>text{font-family:"Noto Serif CJK SC","Source Han Serif SC","Songti SC",serif}</style>`)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`, width, height, background)
+	fmt.Fprintf(&b, `<rect x="36" y="36" width="%d" height="%d" fill="none" stroke="%s" stroke-opacity="0.5" stroke-width="2"/>`,
+		width-72, height-72, foreground)
+	if label := constant.GeneratedCoverTypeLabels[docType]; label != "" {
+		fmt.Fprintf(&b, `<text x="%d" y="120" font-size="26" letter-spacing="6" fill="%s" fill-opacity="0.8">%s</text>`,
+			margin, foreground, html.EscapeString(label))
+	}
+	y := 240
+	for _, line := range lines {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="%d" font-weight="bold" fill="%s">%s</text>`,
+			margin, y, titleFontSize, foreground, html.EscapeString(line))
+		y += titleLeading
+	}
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="80" height="4" fill="%s"/>`, margin, y-titleLeading+40, foreground)
+	if author != "" {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="30" fill="%s" fill-opacity="0.9">%s</text>`,
+			margin, height-100, foreground, html.EscapeString(author))
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+// wrapCoverTitle 按宽度折行：中日韩字符算一个字宽，其他字符算半个字宽，英文单词尽量不拆开，超过 maxLines 行时末尾加省略号
+func wrapCoverTitle(title string, lineWidth float64, maxLines int) []string {
+	var tokens []string
+	var word strings.Builder
+	for _, r := range strings.TrimSpace(title) {
+		if unicode.IsSpace(r) || runeWidth(r) == 1 {
+			if word.Len() > 0 {
+				tokens = append(tokens, word.String())
+				word.Reset()
+			}
+			tokens = append(tokens, string(r))
+			continue
+		}
+		word.WriteRune(r)
+	}
+	if word.Len() > 0 {
+		tokens = append(tokens, word.String())
+	}
+
+	var lines []string
+	var line strings.Builder
+	var used float64
+	for _, token := range tokens {
+		w := textWidth(token)
+		if used+w > lineWidth && line.Len() > 0 {
+			lines = append(lines, strings.TrimSpace(line.String()))
+			line.Reset()
+			used = 0
+			if strings.TrimSpace(token) == "" {
+				continue
+			}
+		}
+		// 单个单词超过一行时按字符拆开
+		for w > lineWidth {
+			var part strings.Builder
+			var partWidth float64
+			for _, r := range token {
+				if partWidth+runeWidth(r) > lineWidth {
+					break
+				}
+				part.WriteRune(r)
+				partWidth += runeWidth(r)
+			}
+			lines = append(lines, part.String())
+			token = strings.TrimPrefix(token, part.String())
+			w = textWidth(token)
+		}
+		line.WriteString(token)
+		used += w
+	}
+	if line.Len() > 0 {
+		lines = append(lines, strings.TrimSpace(line.String()))
+	}
+
+	if len(lines) > maxLines {
+		last := []rune(lines[maxLines-1])
+		for len(last) > 0 && textWidth(string(last))+1 > lineWidth {
+			last = last[:len(last)-1]
+		}
+		lines = append(lines[:maxLines-1], string(last)+"…")
+	}
+	return lines
+}
+
+// runeWidth 字符在封面上占的宽度（以中文字宽为 1）
+func runeWidth(r rune) float64 {
+	if r > unicode.MaxLatin1 && (unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || unicode.IsPunct(r)) {
+		return 1
+	}
+	return 0.55
+}
+
+func textWidth(s string) float64 {
+	var w float64
+	for _, r := range s {
+		w += runeWidth(r)
+	}
+	return w
+}
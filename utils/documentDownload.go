@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/spf13/viper"
+)
+
+// DownloadURLExpire 下载地址的有效期，取配置 download.url_expire_seconds，不超过签名地址的最长有效期
+func DownloadURLExpire() time.Duration {
+	seconds := viper.GetInt("download.url_expire_seconds")
+	if seconds <= 0 {
+		seconds = constant.DefaultDownloadURLExpireSeconds
+	}
+	return time.Duration(min(seconds, constant.MaxSignedURLExpireSeconds)) * time.Second
+}
+
+// WatermarkEnabled 是否为下载的 PDF 添加下载者水印（配置 download.watermark.enabled）
+func WatermarkEnabled() bool {
+	return viper.GetBool("download.watermark.enabled")
+}
+
+// IsPDFDocument 判断文档文件是否为 PDF，尚未检测文件类型的早期文档按扩展名判断
+func IsPDFDocument(document models.Document) bool {
+	if document.Type == constant.VideoType {
+		return false
+	}
+	if document.MimeType != "" {
+		return document.MimeType == constant.MIMEPDF
+	}
+	return strings.EqualFold(path.Ext(document.URL), ".pdf")
+}
+
+// SignDocumentFileURL 签发带水印文件的访问地址，地址中包含下载者和过期时间，浏览器内置的 PDF 阅读器可以直接发起 Range 请求
+func SignDocumentFileURL(documentID uint64, userID uint64, expire time.Duration) string {
+	expires := strconv.FormatInt(time.Now().Add(expire).Unix(), 10)
+	user := strconv.FormatUint(userID, 10)
+	baseURL := strings.TrimSuffix(viper.GetString("download.base_url"), "/")
+	return baseURL + fmt.Sprintf(constant.DocumentFilePath, documentID) +
+		"?user=" + user + "&expires=" + expires + "&signature=" + signDocumentFile(documentID, user, expires)
+}
+
+// VerifyDocumentFileSignature 校验带水印文件访问地址的签名和有效期，返回下载者 ID
+func VerifyDocumentFileSignature(documentID uint64, user, expires, signature string) (uint64, bool) {
+	userID, err := strconv.ParseUint(user, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return 0, false
+	}
+	return userID, hmac.Equal([]byte(signature), []byte(signDocumentFile(documentID, user, expires)))
+}
+
+// signDocumentFile 计算带水印文件访问地址的签名，未单独配置 download.secret 时使用 jwt.secret
+func signDocumentFile(documentID uint64, user, expires string) string {
+	secret := viper.GetString("download.secret")
+	if secret == "" {
+		secret = viper.GetString("jwt.secret")
+	}
+	if secret == "" {
+		secret = string(mySecret)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatUint(documentID, 10) + "\n" + user + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// PrepareWatermarkedPDF 生成带下载者水印的 PDF 并返回本地路径。
+// 同一用户在下载地址有效期内重复访问（浏览器阅读器的 Range 请求）直接使用缓存，文件替换后按新文件重新生成
+func PrepareWatermarkedPDF(document models.Document, userID uint64, username string) (string, error) {
+	dir := filepath.Join(os.TempDir(), constant.WatermarkCacheDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\n%s\n%d\n%s", document.ID, document.URL, userID, username)))
+	cachePath := filepath.Join(dir, hex.EncodeToString(sum[:16])+".pdf")
+	maxAge := 2 * DownloadURLExpire()
+	if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < maxAge {
+		return cachePath, nil
+	}
+	cleanWatermarkCache(dir, maxAge)
+
+	srcPath, err := DownloadFromCOSToTemp(document.URL)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(srcPath)
+
+	// 先写入临时文件再重命名，避免并发请求读到未写完的文件
+	tmpPath := cachePath + "." + strconv.FormatInt(time.Now().UnixNano(), 10) + ".tmp"
+	text := fmt.Sprintf("%s  %s", username, time.Now().Format("2006-01-02 15:04"))
+	if err := WatermarkPDF(srcPath, tmpPath, text); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	return cachePath, nil
+}
+
+// cleanWatermarkCache 删除超过 maxAge 的缓存文件
+func cleanWatermarkCache(dir string, maxAge time.Duration) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			log.Printf("[Download] 删除过期的水印缓存失败: %v", err)
+		}
+	}
+}
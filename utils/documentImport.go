@@ -0,0 +1,381 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+var ErrImportFileTooLarge = errors.New(constant.ImportRowFileTooLarge)
+
+// DocumentImportJob Redis 中保存的批量导入任务及进度
+type DocumentImportJob struct {
+	ID          string                `json:"id"`
+	Status      string                `json:"status"`
+	DryRun      bool                  `json:"dryRun"` // 试运行只校验清单和文件，不创建文档
+	CreatedBy   uint64                `json:"createdBy"`
+	Total       int                   `json:"total"`
+	Processed   int                   `json:"processed"`
+	Succeeded   int                   `json:"succeeded"`
+	Failed      int                   `json:"failed"`
+	DocumentIDs []uint64              `json:"documentIds"` // 成功创建的文档
+	Errors      []DocumentImportError `json:"errors"`
+	Message     string                `json:"message"` // 任务整体失败的原因
+	CreatedAt   int64                 `json:"createdAt"`
+	FinishedAt  int64                 `json:"finishedAt"`
+}
+
+// DocumentImportError 清单中一行的导入错误
+type DocumentImportError struct {
+	Row     int    `json:"row"` // 清单中的行号，CSV 含表头（数据从第 2 行开始），JSON 从 1 开始
+	File    string `json:"file"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// DocumentImportRow 清单中的一行
+type DocumentImportRow struct {
+	Row          int
+	File         string // 文件在压缩包中的路径
+	Name         string
+	Author       string
+	ISBN         string
+	Category     string // 分类路径，如 计算机/操作系统
+	Tags         []string
+	Type         string
+	Year         string
+	Introduction string
+}
+
+// AddError 记录一行导入失败
+func (j *DocumentImportJob) AddError(row DocumentImportRow, message string) {
+	j.Errors = append(j.Errors, DocumentImportError{Row: row.Row, File: row.File, Name: row.Name, Message: message})
+	j.Failed++
+}
+
+// SaveDocumentImportJob 保存批量导入任务
+func SaveDocumentImportJob(job *DocumentImportJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	rdb := config.GetRedisClient()
+	return rdb.Set(config.Ctx, constant.ImportJobKeyPrefix+job.ID, data, constant.ImportJobTTLHours*time.Hour).Err()
+}
+
+// GetDocumentImportJob 获取批量导入任务，不存在或已过期时返回 nil
+func GetDocumentImportJob(jobID string) (*DocumentImportJob, error) {
+	rdb := config.GetRedisClient()
+	data, err := rdb.Get(config.Ctx, constant.ImportJobKeyPrefix+jobID).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job DocumentImportJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FailInterruptedImportJobs 将仍处于执行中的批量导入任务标记为失败（服务重启后后台任务已不存在），返回标记的任务数
+func FailInterruptedImportJobs() (int, error) {
+	rdb := config.GetRedisClient()
+	count := 0
+	iter := rdb.Scan(config.Ctx, 0, constant.ImportJobKeyPrefix+"*", 100).Iterator()
+	for iter.Next(config.Ctx) {
+		job, err := GetDocumentImportJob(strings.TrimPrefix(iter.Val(), constant.ImportJobKeyPrefix))
+		if err != nil {
+			return count, err
+		}
+		if job == nil || job.Status != constant.ImportJobRunning {
+			continue
+		}
+		job.Status = constant.ImportJobFailed
+		job.Message = constant.ImportJobInterrupted
+		job.FinishedAt = time.Now().Unix()
+		if err := SaveDocumentImportJob(job); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, iter.Err()
+}
+
+// WriteDocumentImportReport 以 CSV 格式写出错误报告，带 UTF-8 BOM 以便 Excel 正确识别中文
+func WriteDocumentImportReport(w io.Writer, job *DocumentImportJob) error {
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"row", "file", "name", "error"}); err != nil {
+		return err
+	}
+	for _, e := range job.Errors {
+		if err := writer.Write([]string{fmt.Sprint(e.Row), e.File, e.Name, e.Message}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ParseImportManifest 解析清单，fileName 的扩展名为 .json 时按 JSON 数组解析，否则按带表头的 CSV 解析。
+// 非 UTF-8 编码的清单（如 Excel 导出的 GBK CSV）先转换为 UTF-8
+func ParseImportManifest(fileName string, data []byte) ([]DocumentImportRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+	if !utf8.Valid(data) {
+		decoded, err := simplifiedchinese.GB18030.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, errors.New(constant.ImportManifestEncodingInvalid)
+		}
+		data = decoded
+	}
+	if strings.EqualFold(path.Ext(fileName), ".json") {
+		return parseJSONManifest(data)
+	}
+	return parseCSVManifest(data)
+}
+
+// parseCSVManifest 解析 CSV 清单，第一行为表头
+func parseCSVManifest(data []byte) ([]DocumentImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", constant.ImportManifestInvalid, err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New(constant.ImportManifestEmpty)
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		if field := manifestField(header); field != "" {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["file"]; !ok {
+		return nil, errors.New(constant.ImportManifestNoFileColumn)
+	}
+
+	var rows []DocumentImportRow
+	for i, record := range records[1:] {
+		get := func(field string) string {
+			if index, ok := columns[field]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		// 跳过空行
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		rows = append(rows, DocumentImportRow{
+			Row:          i + 2,
+			File:         get("file"),
+			Name:         get("name"),
+			Author:       get("author"),
+			ISBN:         get("isbn"),
+			Category:     get("category"),
+			Tags:         splitImportTags(get("tags")),
+			Type:         strings.ToLower(get("type")),
+			Year:         get("year"),
+			Introduction: get("introduction"),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, errors.New(constant.ImportManifestEmpty)
+	}
+	return rows, nil
+}
+
+// parseJSONManifest 解析 JSON 清单：对象数组，标签可以是数组或分隔的字符串，年份可以是数字
+func parseJSONManifest(data []byte) ([]DocumentImportRow, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var items []map[string]interface{}
+	if err := decoder.Decode(&items); err != nil {
+		return nil, fmt.Errorf("%s: %v", constant.ImportManifestInvalid, err)
+	}
+	if len(items) == 0 {
+		return nil, errors.New(constant.ImportManifestEmpty)
+	}
+
+	rows := make([]DocumentImportRow, 0, len(items))
+	for i, item := range items {
+		fields := make(map[string]interface{})
+		for key, value := range item {
+			if field := manifestField(key); field != "" {
+				fields[field] = value
+			}
+		}
+		get := func(field string) string {
+			switch v := fields[field].(type) {
+			case string:
+				return strings.TrimSpace(v)
+			case json.Number:
+				return v.String()
+			}
+			return ""
+		}
+		var tags []string
+		switch v := fields["tags"].(type) {
+		case string:
+			tags = splitImportTags(v)
+		case []interface{}:
+			for _, tag := range v {
+				if s, ok := tag.(string); ok && strings.TrimSpace(s) != "" {
+					tags = append(tags, strings.TrimSpace(s))
+				}
+			}
+		}
+		rows = append(rows, DocumentImportRow{
+			Row:          i + 1,
+			File:         get("file"),
+			Name:         get("name"),
+			Author:       get("author"),
+			ISBN:         get("isbn"),
+			Category:     get("category"),
+			Tags:         tags,
+			Type:         strings.ToLower(get("type")),
+			Year:         get("year"),
+			Introduction: get("introduction"),
+		})
+	}
+	return rows, nil
+}
+
+// manifestField 将清单的列名映射为字段名，不认识的列返回空字符串
+func manifestField(column string) string {
+	column = strings.ToLower(strings.TrimSpace(column))
+	for field, aliases := range constant.ImportManifestColumns {
+		for _, alias := range aliases {
+			if column == alias {
+				return field
+			}
+		}
+	}
+	return ""
+}
+
+// splitImportTags 按分隔符拆分标签并去掉空白和重复
+func splitImportTags(value string) []string {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return strings.ContainsRune(constant.ImportManifestTagSeparators, r)
+	})
+	var tags []string
+	seen := make(map[string]bool)
+	for _, part := range parts {
+		tag := strings.TrimSpace(part)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ImportArchiveEntryName 压缩包中文件的路径：未标记 UTF-8 的文件名（Windows 自带压缩工具生成）按 GB18030 解码
+func ImportArchiveEntryName(f *zip.File) string {
+	if f.NonUTF8 || !utf8.ValidString(f.Name) {
+		if decoded, err := simplifiedchinese.GB18030.NewDecoder().String(f.Name); err == nil {
+			return decoded
+		}
+	}
+	return f.Name
+}
+
+// FindImportArchiveEntry 按清单中的路径查找压缩包中的文件。
+// 找不到完全相同的路径时，按路径结尾唯一匹配（压缩整个文件夹时文件会多一层目录）
+func FindImportArchiveEntry(archive *zip.Reader, name string) *zip.File {
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+	var matched *zip.File
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		entry := strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(ImportArchiveEntryName(f), "\\", "/")), "/")
+		if entry == name {
+			return f
+		}
+		if strings.HasSuffix(entry, "/"+name) {
+			if matched != nil {
+				return nil
+			}
+			matched = f
+		}
+	}
+	return matched
+}
+
+// FindImportManifest 在压缩包根目录查找清单文件并读取内容，没有清单时返回 ok 为 false
+func FindImportManifest(archive *zip.Reader) (name string, data []byte, ok bool, err error) {
+	for _, manifestName := range constant.ImportManifestNames {
+		for _, f := range archive.File {
+			if !strings.EqualFold(ImportArchiveEntryName(f), manifestName) {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return "", nil, false, err
+			}
+			data, err := io.ReadAll(io.LimitReader(rc, constant.MaxImportManifestSize))
+			rc.Close()
+			if err != nil {
+				return "", nil, false, err
+			}
+			return manifestName, data, true, nil
+		}
+	}
+	return "", nil, false, nil
+}
+
+// ExtractImportArchiveEntry 将压缩包中的文件解压到临时文件，同时计算大小和 SHA-256。
+// 按实际解压的字节数限制大小，不信任压缩包中记录的大小，超过 maxSize 时返回 ErrImportFileTooLarge
+func ExtractImportArchiveEntry(f *zip.File, maxSize int64) (tmpPath string, size int64, hash string, err error) {
+	if f.UncompressedSize64 > uint64(maxSize) {
+		return "", 0, "", ErrImportFileTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", 0, "", err
+	}
+	defer rc.Close()
+
+	tmpFile, err := os.CreateTemp("", "import-*"+path.Ext(f.Name))
+	if err != nil {
+		return "", 0, "", err
+	}
+	defer tmpFile.Close()
+
+	h := sha256.New()
+	size, err = io.Copy(io.MultiWriter(tmpFile, h), io.LimitReader(rc, maxSize+1))
+	if err == nil && size > maxSize {
+		err = ErrImportFileTooLarge
+	}
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return "", 0, "", err
+	}
+	return tmpFile.Name(), size, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/antidote-kt/SSE_Library-back/models"
+	"github.com/ledongthuc/pdf"
+)
+
+// DocumentFileMetadata 从文档文件中提取的技术元数据
+type DocumentFileMetadata struct {
+	FileSize     int64
+	MimeType     string
+	ContentHash  string
+	PageCount    int
+	WordCount    int
+	Language     string
+	PDFTitle     string
+	PDFAuthor    string
+	PDFCreatedAt *time.Time
+}
+
+// ApplyTo 将元数据写入文档对应的字段
+func (m DocumentFileMetadata) ApplyTo(document *models.Document) {
+	document.FileSize = m.FileSize
+	document.MimeType = m.MimeType
+	document.ContentHash = m.ContentHash
+	document.PageCount = m.PageCount
+	document.WordCount = m.WordCount
+	document.Language = m.Language
+	document.PDFTitle = m.PDFTitle
+	document.PDFAuthor = m.PDFAuthor
+	document.PDFCreatedAt = m.PDFCreatedAt
+}
+
+// ExtractDocumentFileMetadata 提取本地文件的大小、类型、SHA-256，PDF 还提取页数和信息字典中的标题、作者、创建时间；
+// text 为已提取的正文，用于统计字数和检测语言，为空时不统计
+func ExtractDocumentFileMetadata(filePath string, text string) (DocumentFileMetadata, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return DocumentFileMetadata{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return DocumentFileMetadata{}, err
+	}
+
+	metadata := DocumentFileMetadata{FileSize: info.Size()}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return DocumentFileMetadata{}, err
+	}
+	metadata.ContentHash = hex.EncodeToString(hash.Sum(nil))
+	if detected, err := detectUploadType(f, info.Size()); err == nil {
+		metadata.MimeType = detected.MIME
+	}
+	if metadata.MimeType == constant.MIMEPDF {
+		readPDFInfo(f, info.Size(), &metadata)
+	}
+	if strings.TrimSpace(text) != "" {
+		metadata.WordCount = CountWords(text)
+		metadata.Language = DetectLanguage(text)
+	}
+	return metadata, nil
+}
+
+// readPDFInfo 读取 PDF 的页数和信息字典，解析失败时保留已有字段
+func readPDFInfo(r io.ReaderAt, size int64, metadata *DocumentFileMetadata) {
+	// 解析库遇到损坏的文件可能 panic
+	defer func() {
+		_ = recover()
+	}()
+	reader, err := pdf.NewReader(r, size)
+	if err != nil {
+		return
+	}
+	metadata.PageCount = reader.NumPage()
+	info := reader.Trailer().Key("Info")
+	metadata.PDFTitle = truncateRunes(strings.TrimSpace(info.Key("Title").Text()), 500)
+	metadata.PDFAuthor = truncateRunes(strings.TrimSpace(info.Key("Author").Text()), 200)
+	metadata.PDFCreatedAt = parsePDFDate(info.Key("CreationDate").Text())
+}
+
+// parsePDFDate 解析 PDF 日期格式 D:YYYYMMDDHHmmSSOHH'mm'，月份之后的部分均可省略，无法解析时返回 nil
+func parsePDFDate(value string) *time.Time {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "D:")
+	digits := 0
+	for digits < len(value) && digits < 14 && value[digits] >= '0' && value[digits] <= '9' {
+		digits++
+	}
+	if digits < 4 || digits%2 != 0 {
+		return nil
+	}
+	// 省略的月、日补 01，时、分、秒补 00
+	stamp := value[:digits] + "0101000000"[digits-4:]
+	location := time.UTC
+	if zone := value[digits:]; zone != "" && zone[0] != 'Z' {
+		parts := strings.FieldsFunc(zone[1:], func(r rune) bool { return r == '\'' })
+		hours, _ := strconv.Atoi(firstOrEmpty(parts, 0))
+		minutes, _ := strconv.Atoi(firstOrEmpty(parts, 1))
+		offset := hours*3600 + minutes*60
+		if zone[0] == '-' {
+			offset = -offset
+		}
+		location = time.FixedZone("", offset)
+	}
+	t, err := time.ParseInLocation("20060102150405", stamp, location)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func firstOrEmpty(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return ""
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// CountWords 统计字数：中日韩文字每个字算一个，其他语言按连续的字母、数字算一个词
+func CountWords(text string) int {
+	count := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			count++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				count++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
+
+// DetectLanguage 根据文字的书写系统检测正文语言，拉丁字母再按常见词区分英、法、德、西；文字太少时返回 und
+func DetectLanguage(text string) string {
+	var han, kana, hangul, cyrillic, latin int
+	var words []string
+	var word strings.Builder
+	sampled := 0
+	for _, r := range text {
+		if sampled >= constant.LanguageDetectSampleRunes {
+			break
+		}
+		sampled++
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+			word.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+
+	total := han + kana + hangul + cyrillic + latin
+	if total < constant.LanguageDetectMinLetters {
+		return constant.LanguageUnknown
+	}
+	switch {
+	// 日文混用汉字和假名，假名占一定比例即判断为日文
+	case kana > 0 && kana*5 >= han+kana && kana+han >= latin:
+		return constant.LanguageJapanese
+	case han >= hangul && han >= cyrillic && han >= latin:
+		return constant.LanguageChinese
+	case hangul >= cyrillic && hangul >= latin:
+		return constant.LanguageKorean
+	case cyrillic >= latin:
+		return constant.LanguageRussian
+	}
+	return detectLatinLanguage(words)
+}
+
+// detectLatinLanguage 统计各语言常见词出现的次数，取最多的语言
+func detectLatinLanguage(words []string) string {
+	stopWords := make(map[string][]string)
+	for language, list := range constant.LanguageStopWords {
+		for _, w := range list {
+			stopWords[w] = append(stopWords[w], language)
+		}
+	}
+	hits := make(map[string]int)
+	for _, w := range words {
+		for _, language := range stopWords[w] {
+			hits[language]++
+		}
+	}
+	best, bestHits := constant.LanguageUnknown, 0
+	for _, language := range []string{constant.LanguageEnglish, constant.LanguageFrench, constant.LanguageGerman, constant.LanguageSpanish} {
+		if hits[language] > bestHits {
+			best, bestHits = language, hits[language]
+		}
+	}
+	return best
+}
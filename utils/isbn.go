@@ -0,0 +1,77 @@
+package utils
+
+import "strings"
+
+// NormalizeISBN 校验 ISBN-10/13 并统一为不带连字符的 ISBN-13（ISBN-10 加 978 前缀并重新计算校验位），
+// 格式或校验位不正确时返回 false
+func NormalizeISBN(isbn string) (string, bool) {
+	normalized, ok := validateISBN(isbn)
+	if !ok {
+		return "", false
+	}
+	if len(normalized) == 10 {
+		return isbn10To13(normalized), true
+	}
+	return normalized, true
+}
+
+// validateISBN 去掉 ISBN 中的连字符和空格并校验校验位，ISBN-10 末位的 x 统一为大写
+func validateISBN(isbn string) (string, bool) {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(isbn) {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == 'x' || r == 'X':
+			b.WriteRune('X')
+		default:
+			return "", false
+		}
+	}
+	normalized := b.String()
+	switch len(normalized) {
+	case 10:
+		sum := 0
+		for i, r := range normalized {
+			digit := int(r - '0')
+			if r == 'X' {
+				if i != 9 {
+					return "", false
+				}
+				digit = 10
+			}
+			sum += digit * (10 - i)
+		}
+		return normalized, sum%11 == 0
+	case 13:
+		if strings.Contains(normalized, "X") {
+			return "", false
+		}
+		sum := 0
+		for i, r := range normalized {
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += int(r-'0') * weight
+		}
+		return normalized, sum%10 == 0
+	}
+	return "", false
+}
+
+// isbn10To13 将已校验的 ISBN-10 转换为 ISBN-13
+func isbn10To13(isbn10 string) string {
+	digits := "978" + isbn10[:9]
+	sum := 0
+	for i, r := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(r-'0') * weight
+	}
+	return digits + string(rune('0'+(10-sum%10)%10))
+}
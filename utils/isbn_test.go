@@ -0,0 +1,38 @@
+package utils
+
+import "testing"
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name  string
+		isbn  string
+		want  string
+		valid bool
+	}{
+		{name: "ISBN-10", isbn: "0306406152", want: "9780306406157", valid: true},
+		{name: "ISBN-10 带连字符和空格", isbn: " 0-306-40615-2 ", want: "9780306406157", valid: true},
+		{name: "ISBN-10 末位为 X", isbn: "0-8044-2957-X", want: "9780804429573", valid: true},
+		{name: "ISBN-10 末位为小写 x", isbn: "080442957x", want: "9780804429573", valid: true},
+		{name: "ISBN-10 校验位错误", isbn: "0-306-40615-3", valid: false},
+		{name: "ISBN-10 X 不在末位", isbn: "X306406152", valid: false},
+		{name: "ISBN-10 末位应为 X", isbn: "0804429570", valid: false},
+		{name: "ISBN-13", isbn: "978-0-306-40615-7", want: "9780306406157", valid: true},
+		{name: "ISBN-13 979 前缀", isbn: "979-10-90636-07-1", want: "9791090636071", valid: true},
+		{name: "ISBN-13 校验位错误", isbn: "9780306406158", valid: false},
+		{name: "ISBN-13 含 X", isbn: "978030640615X", valid: false},
+		{name: "长度错误", isbn: "12345", valid: false},
+		{name: "非法字符", isbn: "0-306-4O615-2", valid: false},
+		{name: "空字符串", isbn: "", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeISBN(tt.isbn)
+			if ok != tt.valid {
+				t.Fatalf("NormalizeISBN(%q) ok = %v, want %v", tt.isbn, ok, tt.valid)
+			}
+			if ok && got != tt.want {
+				t.Errorf("NormalizeISBN(%q) = %q, want %q", tt.isbn, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/spf13/viper"
+)
+
+// localBlobStore 本地磁盘存储驱动，文件通过 /storage/*key 路由访问，可在没有云存储的环境下运行
+type localBlobStore struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+// newLocalBlobStore 根据配置 storage.local.root、storage.local.base_url、storage.local.secret 创建本地存储
+func newLocalBlobStore() (*localBlobStore, error) {
+	root := viper.GetString("storage.local.root")
+	if root == "" {
+		root = constant.DefaultLocalStorageRoot
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("创建本地存储目录失败: %v", err)
+	}
+
+	baseURL := strings.TrimSuffix(viper.GetString("storage.local.base_url"), "/")
+	if baseURL == "" {
+		baseURL = constant.LocalStorageRoutePrefix
+	}
+	// 未单独配置签名密钥时使用 JWT 密钥
+	secret := viper.GetString("storage.local.secret")
+	if secret == "" {
+		secret = viper.GetString("jwt.secret")
+	}
+	return &localBlobStore{root: root, baseURL: baseURL, secret: []byte(secret)}, nil
+}
+
+// path 将 key 转换为本地路径，拒绝跳出根目录的 key（如包含 ..）
+func (s *localBlobStore) path(key string) (string, error) {
+	p := filepath.Join(s.root, filepath.FromSlash(key))
+	if p != s.root && !strings.HasPrefix(p, s.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("非法的对象路径: %s", key)
+	}
+	return p, nil
+}
+
+func (s *localBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	// 先写入临时文件再重命名，避免上传中断时留下不完整的文件
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *localBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return f, err
+}
+
+func (s *localBlobStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *localBlobStore) Stat(ctx context.Context, key string) (BlobInfo, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	info, err := os.Stat(p)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && info.IsDir()) {
+		return BlobInfo{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	return BlobInfo{
+		Key:          key,
+		Size:         info.Size(),
+		ContentType:  mime.TypeByExtension(filepath.Ext(p)),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (s *localBlobStore) URL(key string) string {
+	return s.baseURL + "/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+func (s *localBlobStore) SignedURL(ctx context.Context, key string, expire time.Duration) (string, error) {
+	return s.signedURL(http.MethodGet, key, expire), nil
+}
+
+func (s *localBlobStore) SignedUploadURL(ctx context.Context, key string, expire time.Duration) (string, error) {
+	return s.signedURL(http.MethodPut, key, expire), nil
+}
+
+func (s *localBlobStore) signedURL(method, key string, expire time.Duration) string {
+	expires := strconv.FormatInt(time.Now().Add(expire).Unix(), 10)
+	return s.URL(key) + "?expires=" + expires + "&signature=" + s.sign(method, key, expires)
+}
+
+// sign 计算本地存储签名地址的签名，签名包含请求方法，下载地址不能用于上传
+func (s *localBlobStore) sign(method, key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify 校验签名和有效期
+func (s *localBlobStore) verify(method, key, expires, signature string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	return err == nil && time.Now().Unix() <= expiresAt &&
+		hmac.Equal([]byte(signature), []byte(s.sign(method, key, expires)))
+}
+
+// getLocalBlobStore 获取本地存储驱动，未使用本地存储时返回 ErrBlobNotFound
+func getLocalBlobStore() (*localBlobStore, error) {
+	store, err := GetBlobStore()
+	if err != nil {
+		return nil, err
+	}
+	local, ok := store.(*localBlobStore)
+	if !ok {
+		return nil, ErrBlobNotFound
+	}
+	return local, nil
+}
+
+// OpenLocalBlob 打开本地存储中的文件，供 /storage/*key 路由使用：带签名参数时校验签名和有效期，
+// 文档主文件等私有路径必须带签名。未使用本地存储驱动时返回 ErrBlobNotFound
+func OpenLocalBlob(key, expires, signature string) (*os.File, error) {
+	local, err := getLocalBlobStore()
+	if err != nil {
+		return nil, err
+	}
+	if signature == "" && isPrivateBlob(key) {
+		return nil, ErrBlobSignatureInvalid
+	}
+	if signature != "" && !local.verify(http.MethodGet, key, expires, signature) {
+		return nil, ErrBlobSignatureInvalid
+	}
+	reader, err := local.Get(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+	f := reader.(*os.File)
+	if info, err := f.Stat(); err != nil || info.IsDir() {
+		f.Close()
+		return nil, ErrBlobNotFound
+	}
+	return f, nil
+}
+
+// isPrivateBlob 判断 key 是否在必须使用签名地址访问的路径下
+func isPrivateBlob(key string) bool {
+	key = path.Clean("/" + key)[1:]
+	for _, prefix := range constant.PrivateBlobPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PutLocalBlob 通过签名上传地址写入本地存储（PUT /storage/*key），必须带有效的上传签名
+func PutLocalBlob(key, expires, signature string, r io.Reader, size int64, contentType string) error {
+	local, err := getLocalBlobStore()
+	if err != nil {
+		return err
+	}
+	if !local.verify(http.MethodPut, key, expires, signature) {
+		return ErrBlobSignatureInvalid
+	}
+	return local.Put(context.Background(), key, r, size, contentType)
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/spf13/viper"
+)
+
+// ErrScanUnavailable 扫描服务不可用或扫描出错，此时拒绝上传（不放行未扫描的文件）
+var ErrScanUnavailable = errors.New(constant.UploadScanUnavailable)
+
+// ScanResult 恶意文件扫描结果
+type ScanResult struct {
+	Infected  bool
+	Signature string // 命中的病毒特征名称
+}
+
+// MalwareScanner 恶意文件扫描接口
+type MalwareScanner interface {
+	// Scan 扫描 r 中的全部内容，扫描服务出错时返回 ErrScanUnavailable
+	Scan(ctx context.Context, r io.Reader) (ScanResult, error)
+}
+
+// noopScanner 未配置扫描服务时使用，不做任何检查
+type noopScanner struct{}
+
+func (noopScanner) Scan(ctx context.Context, r io.Reader) (ScanResult, error) {
+	return ScanResult{}, nil
+}
+
+// clamAVScanner 通过 clamd 的 INSTREAM 命令扫描文件
+type clamAVScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVScanner 创建 ClamAV 客户端，address 形如 tcp://127.0.0.1:3310 或 unix:///var/run/clamav/clamd.ctl，
+// 不带协议前缀时按 TCP 处理
+func NewClamAVScanner(address string, timeout time.Duration) MalwareScanner {
+	network, addr, found := strings.Cut(address, "://")
+	if !found {
+		network, addr = "tcp", address
+	}
+	if timeout <= 0 {
+		timeout = constant.DefaultClamAVTimeoutSeconds * time.Second
+	}
+	return &clamAVScanner{network: network, address: addr, timeout: timeout}
+}
+
+func (s *clamAVScanner) Scan(ctx context.Context, r io.Reader) (ScanResult, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("%w: %v", ErrScanUnavailable, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(s.timeout))
+
+	if err := s.stream(conn, r); err != nil {
+		return ScanResult{}, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return ScanResult{}, fmt.Errorf("%w: %v", ErrScanUnavailable, err)
+	}
+	return parseClamAVReply(reply)
+}
+
+// stream 发送 zINSTREAM 命令和文件内容：每个数据块前是 4 字节大端序长度，以长度为 0 的块结束
+func (s *clamAVScanner) stream(conn net.Conn, r io.Reader) error {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("%w: %v", ErrScanUnavailable, err)
+	}
+	buf := make([]byte, 4+constant.ClamAVChunkSize)
+	for {
+		n, readErr := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				return fmt.Errorf("%w: %v", ErrScanUnavailable, err)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("%w: %v", ErrScanUnavailable, err)
+	}
+	return nil
+}
+
+// parseClamAVReply 解析 clamd 的回复：stream: OK、stream: <特征名> FOUND 或 <原因> ERROR
+func parseClamAVReply(reply string) (ScanResult, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return ScanResult{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return ScanResult{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	}
+	return ScanResult{}, fmt.Errorf("%w: clamd 返回 %q", ErrScanUnavailable, reply)
+}
+
+var (
+	malwareScanner     MalwareScanner
+	malwareScannerErr  error
+	malwareScannerOnce sync.Once
+)
+
+// newMalwareScanner 根据配置文件 scanner.driver 创建扫描器，未配置时不扫描
+func newMalwareScanner() (MalwareScanner, error) {
+	driver := viper.GetString("scanner.driver")
+	switch driver {
+	case "", constant.ScannerDriverNone:
+		return noopScanner{}, nil
+	case constant.ScannerDriverClamAV:
+		address := viper.GetString("scanner.clamav.address")
+		if address == "" {
+			address = constant.DefaultClamAVAddress
+		}
+		timeout := time.Duration(viper.GetInt("scanner.clamav.timeout_seconds")) * time.Second
+		return NewClamAVScanner(address, timeout), nil
+	}
+	return nil, fmt.Errorf("不支持的扫描驱动: %s", driver)
+}
+
+// InitMalwareScanner 初始化恶意文件扫描器，配置有误时返回错误
+func InitMalwareScanner() error {
+	malwareScannerOnce.Do(func() {
+		malwareScanner, malwareScannerErr = newMalwareScanner()
+		if malwareScannerErr == nil {
+			log.Printf("文件扫描器初始化成功，驱动: %s", viper.GetString("scanner.driver"))
+		}
+	})
+	return malwareScannerErr
+}
+
+// GetMalwareScanner 获取恶意文件扫描器实例
+func GetMalwareScanner() (MalwareScanner, error) {
+	if err := InitMalwareScanner(); err != nil {
+		return nil, err
+	}
+	return malwareScanner, nil
+}
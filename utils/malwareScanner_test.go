@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+)
+
+func TestParseClamAVReply(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		infected  bool
+		signature string
+		wantErr   bool
+	}{
+		{name: "干净文件", reply: "stream: OK\x00", infected: false},
+		{name: "命中病毒", reply: "stream: Eicar-Test-Signature FOUND\x00", infected: true, signature: "Eicar-Test-Signature"},
+		{name: "无结束符", reply: "stream: Win.Trojan.Agent-1 FOUND\n", infected: true, signature: "Win.Trojan.Agent-1"},
+		{name: "扫描出错", reply: "INSTREAM size limit exceeded. ERROR\x00", wantErr: true},
+		{name: "空回复", reply: "", wantErr: true},
+		{name: "无法识别", reply: "stream: UNKNOWN\x00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseClamAVReply(tt.reply)
+			if tt.wantErr {
+				if !errors.Is(err, ErrScanUnavailable) {
+					t.Fatalf("parseClamAVReply(%q) error = %v, want ErrScanUnavailable", tt.reply, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseClamAVReply(%q) unexpected error: %v", tt.reply, err)
+			}
+			if result.Infected != tt.infected || result.Signature != tt.signature {
+				t.Errorf("parseClamAVReply(%q) = %+v, want infected=%v signature=%q", tt.reply, result, tt.infected, tt.signature)
+			}
+		})
+	}
+}
+
+// startFakeClamd 启动一个模拟 clamd 的 TCP 服务：读取 zINSTREAM 命令和分块数据，
+// 把收到的内容发到 received，然后回复 reply
+func startFakeClamd(t *testing.T, reply string) (string, <-chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		command, err := r.ReadString(0)
+		if err != nil || command != "zINSTREAM\x00" {
+			received <- nil
+			return
+		}
+		var data bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				received <- nil
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if size > constant.ClamAVChunkSize {
+				received <- nil
+				return
+			}
+			if _, err := io.CopyN(&data, r, int64(size)); err != nil {
+				received <- nil
+				return
+			}
+		}
+		received <- data.Bytes()
+		_, _ = conn.Write([]byte(reply))
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestClamAVScannerInstream(t *testing.T) {
+	// 超过一个数据块，确保分块和结束块都正确
+	payload := bytes.Repeat([]byte("0123456789abcdef"), constant.ClamAVChunkSize/16*2+7)
+
+	tests := []struct {
+		name      string
+		reply     string
+		infected  bool
+		signature string
+	}{
+		{name: "干净文件", reply: "stream: OK\x00"},
+		{name: "命中病毒", reply: "stream: Eicar-Test-Signature FOUND\x00", infected: true, signature: "Eicar-Test-Signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, received := startFakeClamd(t, tt.reply)
+			scanner := NewClamAVScanner("tcp://"+address, 5*time.Second)
+
+			result, err := scanner.Scan(context.Background(), bytes.NewReader(payload))
+			if err != nil {
+				t.Fatalf("Scan unexpected error: %v", err)
+			}
+			if result.Infected != tt.infected || result.Signature != tt.signature {
+				t.Errorf("Scan = %+v, want infected=%v signature=%q", result, tt.infected, tt.signature)
+			}
+			if data := <-received; !bytes.Equal(data, payload) {
+				t.Errorf("fake clamd received %d bytes, want %d", len(data), len(payload))
+			}
+		})
+	}
+}
+
+func TestClamAVScannerUnavailable(t *testing.T) {
+	// 先占用一个端口再关闭，保证连接被拒绝
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	address := ln.Addr().String()
+	ln.Close()
+
+	scanner := NewClamAVScanner(address, time.Second)
+	if _, err := scanner.Scan(context.Background(), bytes.NewReader([]byte("data"))); !errors.Is(err, ErrScanUnavailable) {
+		t.Fatalf("Scan error = %v, want ErrScanUnavailable", err)
+	}
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/spf13/viper"
+)
+
+// ModerationRule 本地审核规则：正文匹配 Pattern（正则）时计入对应类别和风险分
+type ModerationRule struct {
+	Pattern  string  `mapstructure:"pattern"`
+	Category string  `mapstructure:"category"`
+	Score    float64 `mapstructure:"score"`
+}
+
+// ModerationResult 内容审核结果
+type ModerationResult struct {
+	RiskScore  float64  // 0~1，取本地规则与模型判断中的较大值
+	Categories []string // 命中的风险类别
+	Reason     string   // 模型给出的判断理由
+	RuleHits   []string // 命中的本地规则
+	AIChecked  bool     // 是否成功经过模型审核（模型调用失败时仅依据本地规则）
+}
+
+type compiledModerationRule struct {
+	ModerationRule
+	re *regexp.Regexp
+}
+
+// defaultModerationRules 内置的本地规则，可在配置文件 moderation.rules 中追加
+var defaultModerationRules = []ModerationRule{
+	{Pattern: `(?i)(加|\+|➕)\s*(微信|威信|vx|wx|qq|扣扣|v信)`, Category: constant.ModerationCategorySpam, Score: 0.6},
+	{Pattern: `(代写|代考|代做作业|刷单|兼职日结|博彩|网赚|贷款秒批)`, Category: constant.ModerationCategorySpam, Score: 0.9},
+	{Pattern: `(?i)(https?://\S+[\s\S]*){3,}`, Category: constant.ModerationCategorySpam, Score: 0.5},
+	{Pattern: `(?i)(傻[逼比屄]|\bsb\b|脑残|去死|废物东西|滚犊子)`, Category: constant.ModerationCategoryAbuse, Score: 0.8},
+}
+
+var (
+	moderationRulesOnce sync.Once
+	moderationRules     []compiledModerationRule
+)
+
+// loadModerationRules 编译内置规则和配置文件中的规则（只在首次使用时加载），非法的正则会被跳过
+func loadModerationRules() []compiledModerationRule {
+	moderationRulesOnce.Do(func() {
+		rules := slices.Clone(defaultModerationRules)
+		var extra []ModerationRule
+		if err := viper.UnmarshalKey("moderation.rules", &extra); err != nil {
+			log.Printf("[Moderation] 读取配置中的审核规则失败: %v", err)
+		}
+		rules = append(rules, extra...)
+
+		for _, rule := range rules {
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				log.Printf("[Moderation] 忽略非法的审核规则 %q: %v", rule.Pattern, err)
+				continue
+			}
+			moderationRules = append(moderationRules, compiledModerationRule{ModerationRule: rule, re: re})
+		}
+	})
+	return moderationRules
+}
+
+// ModerationThresholds 获取自动隐藏和进入人工复核的风险分阈值（可在配置文件 moderation 下覆盖）
+func ModerationThresholds() (hide float64, review float64) {
+	hide = viper.GetFloat64("moderation.hide_threshold")
+	if hide <= 0 {
+		hide = constant.DefaultModerationHideThreshold
+	}
+	review = viper.GetFloat64("moderation.review_threshold")
+	if review <= 0 {
+		review = constant.DefaultModerationReviewThreshold
+	}
+	return hide, review
+}
+
+// truncateRunes 按 rune 截断文本
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// isModerationCategory 判断是否为已知的风险类别
+func isModerationCategory(category string) bool {
+	switch category {
+	case constant.ModerationCategorySpam, constant.ModerationCategoryAbuse, constant.ModerationCategoryOffTopic:
+		return true
+	}
+	return false
+}
+
+// MatchModerationRules 使用本地规则和敏感词库检查文本
+func MatchModerationRules(text string) ModerationResult {
+	result := ModerationResult{Categories: []string{}, RuleHits: []string{}}
+	for _, rule := range loadModerationRules() {
+		match := rule.re.FindString(text)
+		if match == "" {
+			continue
+		}
+		result.RuleHits = append(result.RuleHits, rule.Category+":"+truncateRunes(match, 20))
+		if rule.Score > result.RiskScore {
+			result.RiskScore = rule.Score
+		}
+		if !slices.Contains(result.Categories, rule.Category) {
+			result.Categories = append(result.Categories, rule.Category)
+		}
+	}
+
+	// 命中 flag 类敏感词的内容至少进入人工复核
+	_, reviewThreshold := ModerationThresholds()
+	for _, match := range CheckSensitiveText(text).Matches {
+		if match.Action != constant.SensitiveActionFlag {
+			continue
+		}
+		result.RuleHits = append(result.RuleHits, "sensitive:"+match.Word)
+		if reviewThreshold > result.RiskScore {
+			result.RiskScore = reviewThreshold
+		}
+	}
+	return result
+}
+
+// ModerateText 先用本地规则检查文本，再由模型判断垃圾广告、辱骂和无关内容，合并两者结果（超长文本只审核开头部分）
+// 模型调用或解析失败时返回仅依据本地规则的结果和错误，调用方可按需继续使用该结果
+func ModerateText(systemPrompt, text string) (ModerationResult, error) {
+	text = truncateRunes(text, constant.ModerationSourceMaxRunes)
+	result := MatchModerationRules(text)
+
+	output, err := Chat([]Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: text},
+	})
+	if err != nil {
+		return result, err
+	}
+	var judged struct {
+		RiskScore  float64  `json:"riskScore"`
+		Categories []string `json:"categories"`
+		Reason     string   `json:"reason"`
+	}
+	if err := ParseModelJSON(output, &judged, false); err != nil {
+		return result, err
+	}
+
+	result.AIChecked = true
+	result.Reason = strings.TrimSpace(judged.Reason)
+	score := min(max(judged.RiskScore, 0), 1)
+	if score > result.RiskScore {
+		result.RiskScore = score
+	}
+	for _, category := range judged.Categories {
+		if isModerationCategory(category) && !slices.Contains(result.Categories, category) {
+			result.Categories = append(result.Categories, category)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/spf13/viper"
+)
+
+// WatermarkPDF 为 srcPath 的每一页叠加包含 text 的半透明斜向水印，结果写入 dstPath。
+// 水印页由程序生成，叠加调用配置 download.watermark.command 指定的 qpdf
+func WatermarkPDF(srcPath string, dstPath string, text string) error {
+	command := viper.GetString("download.watermark.command")
+	if command == "" {
+		command = constant.DefaultWatermarkCommand
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return fmt.Errorf("水印命令不可用: %v", err)
+	}
+
+	overlay, err := os.CreateTemp("", "watermark-*.pdf")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(overlay.Name())
+	_, err = overlay.Write(buildWatermarkPDF(text))
+	if closeErr := overlay.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constant.WatermarkTimeoutSeconds*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command, srcPath, "--overlay", overlay.Name(), "--repeat=1", "--", dstPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		// qpdf 退出码 3 表示成功但有警告（如文件结构有小问题已自动修复）
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 3 {
+			return nil
+		}
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// buildWatermarkPDF 生成单页水印 PDF：文字按 30 度斜向平铺，灰色、15% 不透明度。
+// 使用阅读器内置的 STSong-Light 字体（Adobe-GB1），不嵌入字体即可显示中文用户名
+func buildWatermarkPDF(text string) []byte {
+	const angle = 30 * math.Pi / 180
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	encoded := encodeWatermarkText(text)
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "q /GS1 gs 0.5 g BT /F1 %d Tf\n", constant.WatermarkFontSize)
+	for y := -constant.WatermarkPageHeight / 2; y < constant.WatermarkPageHeight; y += 140 {
+		for x := -100; x < constant.WatermarkPageWidth; x += 300 {
+			// 相邻两行错开半个间距
+			offset := 0
+			if (y/140)%2 != 0 {
+				offset = 150
+			}
+			fmt.Fprintf(&content, "%.4f %.4f %.4f %.4f %d %d Tm <%s> Tj\n", cos, sin, -sin, cos, x+offset, y, encoded)
+		}
+	}
+	content.WriteString("ET Q\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 4 0 R >> /ExtGState << /GS1 7 0 R >> >> /Contents 8 0 R >>",
+			constant.WatermarkPageWidth, constant.WatermarkPageHeight),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [5 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 4 >> /FontDescriptor 6 0 R /DW 1000 /W [1 95 500] >>",
+		"<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>",
+		"<< /Type /ExtGState /ca 0.15 /CA 0.15 >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return pdf.Bytes()
+}
+
+// encodeWatermarkText 将文字编码为 UCS-2 大端序的十六进制串，基本多文种平面以外的字符替换为问号
+func encodeWatermarkText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r > 0xFFFF || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// rePromptVariable 匹配模板中的变量引用，如 {{.Question}}、{{if .RecentBooks}}
+var rePromptVariable = regexp.MustCompile(`\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// reTemplateAction 匹配模板中的 {{ ... }} 动作
+var reTemplateAction = regexp.MustCompile(`\{\{[^}]*\}\}`)
+
+// ParsePromptTemplate 校验提示词模板语法（text/template 语法，变量写作 {{.Name}}）
+func ParsePromptTemplate(content string) (*template.Template, error) {
+	return template.New("prompt").Option("missingkey=zero").Parse(content)
+}
+
+// RenderPromptTemplate 使用给定变量渲染提示词模板，未提供的变量渲染为空字符串
+func RenderPromptTemplate(content string, vars map[string]string) (string, error) {
+	tmpl, err := ParsePromptTemplate(content)
+	if err != nil {
+		return "", err
+	}
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, vars); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// ExtractPromptVariables 列出模板中引用到的变量名（去重并排序），用于后台展示与预览
+func ExtractPromptVariables(content string) []string {
+	seen := make(map[string]bool)
+	variables := make([]string, 0)
+	for _, action := range reTemplateAction.FindAllString(content, -1) {
+		for _, m := range rePromptVariable.FindAllStringSubmatch(action, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				variables = append(variables, m[1])
+			}
+		}
+	}
+	sort.Strings(variables)
+	return variables
+}
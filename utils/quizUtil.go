@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 题目类型
+const (
+	QuizQuestionChoice      = "choice"       // 单项选择题
+	QuizQuestionShortAnswer = "short_answer" // 简答题
+)
+
+// ErrQuizMalformed 多次重试后模型输出仍不符合测验格式
+var ErrQuizMalformed = errors.New("模型输出的测验格式不合法")
+
+// quizGenerateMaxAttempts 模型输出不符合格式时最多请求的次数（含第一次）
+const quizGenerateMaxAttempts = 3
+
+// quizChoiceOptionCount 选择题的选项数
+const quizChoiceOptionCount = 4
+
+// QuizQuestion 测验题目
+type QuizQuestion struct {
+	Type        string   `json:"type"`              // choice 或 short_answer
+	Question    string   `json:"question"`          // 题干
+	Options     []string `json:"options,omitempty"` // 选择题选项（固定4个，对应 A-D）
+	Answer      string   `json:"answer"`            // 选择题为选项字母，简答题为参考答案
+	Explanation string   `json:"explanation"`       // 解析
+	SourcePages []int    `json:"sourcePages"`       // 出处页码
+}
+
+// Flashcard 记忆卡片
+type Flashcard struct {
+	Front       string `json:"front"` // 正面：概念或问题
+	Back        string `json:"back"`  // 背面：解释或答案
+	SourcePages []int  `json:"sourcePages"`
+}
+
+// QuizContent 模型生成的测验内容
+type QuizContent struct {
+	Questions  []QuizQuestion `json:"questions"`
+	Flashcards []Flashcard    `json:"flashcards"`
+}
+
+// QuizSpec 生成测验的要求，用于校验模型输出
+type QuizSpec struct {
+	ChoiceCount      int
+	ShortAnswerCount int
+	FlashcardCount   int
+	StartPage        int // 素材覆盖的页码范围，出处页码必须落在其中
+	EndPage          int
+}
+
+// ValidateQuizContent 按 QuizSpec 校验测验内容的结构，返回的错误信息会反馈给模型用于修正
+func ValidateQuizContent(content QuizContent, spec QuizSpec) error {
+	var choiceCount, shortAnswerCount int
+	for i, q := range content.Questions {
+		label := fmt.Sprintf("questions[%d]", i)
+		if strings.TrimSpace(q.Question) == "" {
+			return fmt.Errorf("%s.question 不能为空", label)
+		}
+		switch q.Type {
+		case QuizQuestionChoice:
+			choiceCount++
+			if len(q.Options) != quizChoiceOptionCount {
+				return fmt.Errorf("%s.options 必须恰好包含%d个选项", label, quizChoiceOptionCount)
+			}
+			for j, option := range q.Options {
+				if strings.TrimSpace(option) == "" {
+					return fmt.Errorf("%s.options[%d] 不能为空", label, j)
+				}
+			}
+			if QuizChoiceAnswerIndex(q.Answer) < 0 {
+				return fmt.Errorf("%s.answer 必须是 A、B、C、D 之一", label)
+			}
+		case QuizQuestionShortAnswer:
+			shortAnswerCount++
+			if strings.TrimSpace(q.Answer) == "" {
+				return fmt.Errorf("%s.answer 不能为空", label)
+			}
+		default:
+			return fmt.Errorf("%s.type 必须是 %s 或 %s", label, QuizQuestionChoice, QuizQuestionShortAnswer)
+		}
+		if err := validateSourcePages(q.SourcePages, spec, label); err != nil {
+			return err
+		}
+	}
+	if choiceCount != spec.ChoiceCount {
+		return fmt.Errorf("选择题数量应为%d，实际为%d", spec.ChoiceCount, choiceCount)
+	}
+	if shortAnswerCount != spec.ShortAnswerCount {
+		return fmt.Errorf("简答题数量应为%d，实际为%d", spec.ShortAnswerCount, shortAnswerCount)
+	}
+
+	if len(content.Flashcards) != spec.FlashcardCount {
+		return fmt.Errorf("记忆卡片数量应为%d，实际为%d", spec.FlashcardCount, len(content.Flashcards))
+	}
+	for i, card := range content.Flashcards {
+		label := fmt.Sprintf("flashcards[%d]", i)
+		if strings.TrimSpace(card.Front) == "" || strings.TrimSpace(card.Back) == "" {
+			return fmt.Errorf("%s.front 和 back 不能为空", label)
+		}
+		if err := validateSourcePages(card.SourcePages, spec, label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSourcePages 出处页码不能为空且必须落在素材的页码范围内
+func validateSourcePages(pages []int, spec QuizSpec, label string) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("%s.sourcePages 不能为空", label)
+	}
+	for _, page := range pages {
+		if page < spec.StartPage || page > spec.EndPage {
+			return fmt.Errorf("%s.sourcePages 中的页码 %d 超出素材范围 %d-%d", label, page, spec.StartPage, spec.EndPage)
+		}
+	}
+	return nil
+}
+
+// QuizChoiceAnswerIndex 将选项字母（A-D，不区分大小写）转换为下标，非法时返回 -1
+func QuizChoiceAnswerIndex(answer string) int {
+	answer = strings.ToUpper(strings.TrimSpace(answer))
+	if len(answer) != 1 || answer[0] < 'A' || int(answer[0]-'A') >= quizChoiceOptionCount {
+		return -1
+	}
+	return int(answer[0] - 'A')
+}
+
+// GenerateQuiz 调用模型生成测验并按 spec 校验，输出格式不合法时把错误反馈给模型重试
+func GenerateQuiz(systemPrompt, userMsg string, spec QuizSpec) (QuizContent, error) {
+	messages := []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userMsg},
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= quizGenerateMaxAttempts; attempt++ {
+		output, err := Chat(messages)
+		if err != nil {
+			return QuizContent{}, err
+		}
+
+		var content QuizContent
+		err = ParseModelJSON(output, &content, true)
+		if err == nil {
+			err = ValidateQuizContent(content, spec)
+		}
+		if err == nil {
+			return content, nil
+		}
+
+		lastErr = err
+		messages = append(messages,
+			Message{Role: "assistant", Content: output},
+			Message{Role: "user", Content: "上面的输出不符合要求：" + err.Error() + "。请修正后重新输出完整的 JSON，不要输出其他内容。"},
+		)
+	}
+	return QuizContent{}, errors.Join(ErrQuizMalformed, lastErr)
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+)
+
+// RecommendCacheEntry Redis 中缓存的一份推荐结果
+type RecommendCacheEntry struct {
+	GeneratedAt int64           `json:"generatedAt"` // 生成时间（Unix 秒）
+	Items       json.RawMessage `json:"items"`
+}
+
+// recommendCacheKey 推荐缓存 key，格式为 recommend:类型:用户ID
+func recommendCacheKey(userID uint64, kind string) string {
+	return fmt.Sprintf("%s%s:%d", constant.RecommendCacheKeyPrefix, kind, userID)
+}
+
+// GetRecommendCache 获取用户某类推荐的缓存，不存在时返回 nil
+func GetRecommendCache(userID uint64, kind string) (*RecommendCacheEntry, error) {
+	rdb := config.GetRedisClient()
+	data, err := rdb.Get(config.Ctx, recommendCacheKey(userID, kind)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry RecommendCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// SetRecommendCache 写入用户某类推荐的缓存
+func SetRecommendCache(userID uint64, kind string, items any) error {
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(RecommendCacheEntry{GeneratedAt: time.Now().Unix(), Items: itemsJSON})
+	if err != nil {
+		return err
+	}
+	rdb := config.GetRedisClient()
+	return rdb.Set(config.Ctx, recommendCacheKey(userID, kind), data, constant.RecommendCacheTTLHours*time.Hour).Err()
+}
+
+// IsRecommendCacheStale 判断推荐缓存是否过期：超过新鲜期（可在配置文件 recommend.fresh_minutes 中覆盖），
+// 或生成时间早于全局失效时间戳
+func IsRecommendCacheStale(entry *RecommendCacheEntry) bool {
+	freshMinutes := viper.GetInt("recommend.fresh_minutes")
+	if freshMinutes <= 0 {
+		freshMinutes = constant.DefaultRecommendFreshMinutes
+	}
+	if time.Since(time.Unix(entry.GeneratedAt, 0)) > time.Duration(freshMinutes)*time.Minute {
+		return true
+	}
+
+	staleAt, err := config.GetRedisClient().Get(config.Ctx, constant.RecommendStaleAtKey).Result()
+	if err != nil {
+		return false
+	}
+	staleAtUnix, err := strconv.ParseInt(staleAt, 10, 64)
+	return err == nil && entry.GeneratedAt < staleAtUnix
+}
+
+// MarkAllRecommendationsStale 将当前所有用户的推荐缓存标记为过期，下次访问时在后台刷新（如有新资源审核通过时）
+func MarkAllRecommendationsStale() error {
+	rdb := config.GetRedisClient()
+	return rdb.Set(config.Ctx, constant.RecommendStaleAtKey, time.Now().Unix(), 0).Err()
+}
+
+// TryLockRecommend 尝试获取推荐相关的分布式锁，已被占用时返回 false
+func TryLockRecommend(key string, ttl time.Duration) bool {
+	ok, err := config.GetRedisClient().SetNX(config.Ctx, key, 1, ttl).Result()
+	return err == nil && ok
+}
+
+// UnlockRecommend 释放推荐相关的分布式锁
+func UnlockRecommend(key string) {
+	config.GetRedisClient().Del(config.Ctx, key)
+}
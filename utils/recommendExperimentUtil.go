@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"strconv"
+)
+
+// RecommendVariant 推荐实验的一个变体
+type RecommendVariant struct {
+	Name     string `json:"name"`
+	Strategy string `json:"strategy"` // vector_only、llm_rerank、popularity、hybrid
+	Weight   int    `json:"weight"`   // 流量权重
+}
+
+// ParseRecommendVariants 解析实验中保存的变体列表
+func ParseRecommendVariants(data string) ([]RecommendVariant, error) {
+	var variants []RecommendVariant
+	if err := json.Unmarshal([]byte(data), &variants); err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// AssignRecommendVariant 按“实验名:用户ID”的哈希值将用户稳定地分配到某个变体（同一实验中同一用户始终落在同一变体），
+// 各变体分到的用户比例与其权重成正比
+func AssignRecommendVariant(experimentName string, userID uint64, variants []RecommendVariant) (RecommendVariant, bool) {
+	total := 0
+	for _, variant := range variants {
+		total += max(variant.Weight, 0)
+	}
+	if total == 0 {
+		return RecommendVariant{}, false
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(experimentName + ":" + strconv.FormatUint(userID, 10)))
+	bucket := int(h.Sum32() % uint32(total))
+	for _, variant := range variants {
+		if bucket < max(variant.Weight, 0) {
+			return variant, true
+		}
+		bucket -= max(variant.Weight, 0)
+	}
+	return RecommendVariant{}, false
+}
+
+// WilsonInterval 计算比例的 Wilson 置信区间（样本少或比例接近 0、1 时比正态近似更稳定）
+func WilsonInterval(successes, trials int64, z float64) (low, high float64) {
+	if trials <= 0 {
+		return 0, 0
+	}
+	n := float64(trials)
+	p := float64(successes) / n
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+	return max(center-margin, 0), min(center+margin, 1)
+}
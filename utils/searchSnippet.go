@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"html"
+	"unicode"
+)
+
+const (
+	HighlightPreTag  = "<em>"
+	HighlightPostTag = "</em>"
+)
+
+// BuildHighlightSnippet 截取正文中关键词首次出现位置前后 radius 个字符作为摘要片段，并用 <em></em> 包裹关键词。
+// 匹配不区分大小写，按 rune 处理，保证中文不会被截断成乱码；未命中时返回正文开头部分。
+// 前端按 HTML 渲染片段，因此正文各段都先做 HTML 转义，只有高亮标签是真正的标签。
+func BuildHighlightSnippet(content, keyword string, radius int) string {
+	runes := []rune(content)
+	keyRunes := []rune(keyword)
+
+	index := indexRunesFold(runes, keyRunes)
+	if index < 0 || len(keyRunes) == 0 {
+		if len(runes) > radius*2 {
+			return html.EscapeString(string(runes[:radius*2])) + "..."
+		}
+		return html.EscapeString(content)
+	}
+
+	start := index - radius
+	if start < 0 {
+		start = 0
+	}
+	end := index + len(keyRunes) + radius
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	snippet := ""
+	if start > 0 {
+		snippet += "..."
+	}
+	snippet += html.EscapeString(string(runes[start:index])) +
+		HighlightPreTag + html.EscapeString(string(runes[index:index+len(keyRunes)])) + HighlightPostTag +
+		html.EscapeString(string(runes[index+len(keyRunes):end]))
+	if end < len(runes) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// indexRunesFold 返回 sub 在 s 中首次出现的位置（不区分大小写），未找到返回 -1
+func indexRunesFold(s, sub []rune) int {
+	if len(sub) == 0 || len(sub) > len(s) {
+		return -1
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		matched := true
+		for j := range sub {
+			if unicode.ToLower(s[i+j]) != unicode.ToLower(sub[j]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
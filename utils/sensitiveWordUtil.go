@@ -0,0 +1,299 @@
+package utils
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+)
+
+// SensitiveWordEntry 词库中的一个敏感词
+type SensitiveWordEntry struct {
+	Word     string
+	Category string
+}
+
+// SensitiveMatch 文本中命中的一个敏感词，Start、End 为原文中的 rune 下标（左闭右开）
+type SensitiveMatch struct {
+	Word     string `json:"word"`
+	Category string `json:"category"`
+	Action   string `json:"action"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+}
+
+// SensitiveCheckResult 敏感词检查结果
+type SensitiveCheckResult struct {
+	Action  string           `json:"action"` // 命中敏感词中最严格的处理方式，未命中时为空
+	Matches []SensitiveMatch `json:"matches"`
+	Masked  string           `json:"masked"` // 将 mask、reject 类敏感词替换为 * 后的文本
+}
+
+// SensitiveWordLoader 从数据库加载词库和各分类的处理方式
+type SensitiveWordLoader func() ([]SensitiveWordEntry, map[string]string, error)
+
+// acNode Aho-Corasick 自动机节点
+type acNode struct {
+	children map[rune]*acNode
+	fail     *acNode
+	outputs  []int // 以该节点结尾的敏感词下标（含失败链上的）
+}
+
+// SensitiveFilter 基于 Aho-Corasick 自动机的敏感词过滤器，一次扫描即可匹配全部敏感词
+// 匹配时忽略大小写，中日韩文字之间夹杂的空白和标点也会忽略（如“傻 逼”）；
+// 英文等拉丁字母、数字开头或结尾的敏感词必须在原文中按整词出现，不匹配单词内部（如 class 中的 ass）和跨越空格的字母（如 this book 中的 s b），
+// 敏感词中的空白和标点可以匹配原文中任意连续的空白和标点（如 "bad word" 匹配 "bad-word"）
+type SensitiveFilter struct {
+	root    *acNode
+	words   []SensitiveWordEntry
+	lengths []int
+	actions map[string]string
+
+	// 敏感词首、尾是否需要落在单词边界上（拉丁字母、数字开头或结尾的敏感词）
+	boundaries [][2]bool
+}
+
+var sensitiveActionLevels = map[string]int{
+	constant.SensitiveActionFlag:   1,
+	constant.SensitiveActionMask:   2,
+	constant.SensitiveActionReject: 3,
+}
+
+var (
+	sensitiveFilterMu sync.RWMutex
+	sensitiveFilter   = NewSensitiveFilter(nil, nil)
+)
+
+// isSensitiveSeparator 判断是否为匹配时忽略的分隔字符
+func isSensitiveSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// isCJKRune 判断是否为中日韩文字，这些文字之间没有空格分词，匹配时跳过其间的分隔字符
+func isCJKRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// isWordRune 判断是否为需要整词匹配的字符（拉丁字母等非中日韩文字的字母和数字）
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r)) && !isCJKRune(r)
+}
+
+// NormalizeSensitiveWord 规范化敏感词，与匹配时的处理一致：转为小写，去掉首尾和中日韩文字之间的空白、标点，
+// 其他位置连续的空白和标点合并为一个空格（如 "Bad-Word" 规范化为 "bad word"）
+func NormalizeSensitiveWord(word string) string {
+	var builder strings.Builder
+	var last rune
+	separated := false
+	for _, r := range word {
+		if isSensitiveSeparator(r) {
+			separated = builder.Len() > 0
+			continue
+		}
+		if separated && !(isCJKRune(last) && isCJKRune(r)) {
+			builder.WriteRune(' ')
+		}
+		separated = false
+		builder.WriteRune(unicode.ToLower(r))
+		last = r
+	}
+	return builder.String()
+}
+
+// IsValidSensitiveAction 判断是否为合法的敏感词处理方式
+func IsValidSensitiveAction(action string) bool {
+	_, ok := sensitiveActionLevels[action]
+	return ok
+}
+
+// NewSensitiveFilter 根据词库和各分类的处理方式构建过滤器
+func NewSensitiveFilter(entries []SensitiveWordEntry, actions map[string]string) *SensitiveFilter {
+	f := &SensitiveFilter{root: &acNode{children: map[rune]*acNode{}}, actions: actions}
+
+	for _, entry := range entries {
+		word := []rune(NormalizeSensitiveWord(entry.Word))
+		if len(word) == 0 {
+			continue
+		}
+		node := f.root
+		for _, r := range word {
+			next, ok := node.children[r]
+			if !ok {
+				next = &acNode{children: map[rune]*acNode{}}
+				node.children[r] = next
+			}
+			node = next
+		}
+		node.outputs = append(node.outputs, len(f.words))
+		f.words = append(f.words, entry)
+		f.lengths = append(f.lengths, len(word))
+		f.boundaries = append(f.boundaries, [2]bool{isWordRune(word[0]), isWordRune(word[len(word)-1])})
+	}
+
+	// 按层次遍历构建失败指针，子节点继承失败节点的输出
+	queue := make([]*acNode, 0, len(f.root.children))
+	for _, child := range f.root.children {
+		child.fail = f.root
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for r, child := range node.children {
+			fail := node.fail
+			for fail != nil && fail.children[r] == nil {
+				fail = fail.fail
+			}
+			if fail == nil {
+				child.fail = f.root
+			} else {
+				child.fail = fail.children[r]
+			}
+			child.outputs = append(child.outputs, child.fail.outputs...)
+			queue = append(queue, child)
+		}
+	}
+	return f
+}
+
+// next 自动机读入一个字符后转移到的节点
+func (f *SensitiveFilter) next(node *acNode, r rune) *acNode {
+	for node != f.root && node.children[r] == nil {
+		node = node.fail
+	}
+	if child, ok := node.children[r]; ok {
+		return child
+	}
+	return node
+}
+
+// actionOf 获取敏感词分类对应的处理方式
+func (f *SensitiveFilter) actionOf(category string) string {
+	if action, ok := f.actions[category]; ok && IsValidSensitiveAction(action) {
+		return action
+	}
+	return constant.DefaultSensitiveAction
+}
+
+// Check 检查文本中的敏感词
+func (f *SensitiveFilter) Check(text string) SensitiveCheckResult {
+	original := []rune(text)
+	result := SensitiveCheckResult{Matches: []SensitiveMatch{}, Masked: text}
+
+	// 中日韩文字之间的分隔字符直接跳过，其他位置连续的分隔字符作为一个空格参与匹配，
+	// 因此英文敏感词不能跨越空格、标点匹配；positions 记录参与匹配的每个字符在原文中的位置
+	positions := make([]int, 0, len(original))
+	node := f.root
+	separator := -1 // 上一个参与匹配的字符之后第一个分隔字符的位置
+	for i, r := range original {
+		if isSensitiveSeparator(r) {
+			if len(positions) > 0 && separator < 0 {
+				separator = i
+			}
+			continue
+		}
+		if separator >= 0 {
+			if !isCJKRune(original[positions[len(positions)-1]]) || !isCJKRune(r) {
+				positions = append(positions, separator)
+				node = f.next(node, ' ')
+			}
+			separator = -1
+		}
+		positions = append(positions, i)
+		node = f.next(node, unicode.ToLower(r))
+		for _, idx := range node.outputs {
+			start := positions[len(positions)-f.lengths[idx]]
+			if !f.onWordBoundary(idx, original, start, i+1) {
+				continue
+			}
+			entry := f.words[idx]
+			action := f.actionOf(entry.Category)
+			result.Matches = append(result.Matches, SensitiveMatch{
+				Word:     entry.Word,
+				Category: entry.Category,
+				Action:   action,
+				Start:    start,
+				End:      i + 1,
+			})
+			if sensitiveActionLevels[action] > sensitiveActionLevels[result.Action] {
+				result.Action = action
+			}
+		}
+	}
+
+	if len(result.Matches) == 0 {
+		return result
+	}
+	masked := make([]rune, len(original))
+	copy(masked, original)
+	for _, match := range result.Matches {
+		if match.Action == constant.SensitiveActionFlag {
+			continue
+		}
+		for i := match.Start; i < match.End; i++ {
+			if !isSensitiveSeparator(masked[i]) {
+				masked[i] = '*'
+			}
+		}
+	}
+	result.Masked = string(masked)
+	return result
+}
+
+// onWordBoundary 拉丁字母、数字开头或结尾的敏感词，命中位置前后不能紧接其他字母或数字（即不在单词内部）
+func (f *SensitiveFilter) onWordBoundary(idx int, text []rune, start, end int) bool {
+	if f.boundaries[idx][0] && start > 0 && isWordRune(text[start-1]) {
+		return false
+	}
+	if f.boundaries[idx][1] && end < len(text) && isWordRune(text[end]) {
+		return false
+	}
+	return true
+}
+
+// CheckSensitiveText 使用当前加载的词库检查文本
+func CheckSensitiveText(text string) SensitiveCheckResult {
+	sensitiveFilterMu.RLock()
+	f := sensitiveFilter
+	sensitiveFilterMu.RUnlock()
+	return f.Check(text)
+}
+
+// ReloadSensitiveWords 重新加载词库并替换当前过滤器，加载失败时保留原过滤器
+func ReloadSensitiveWords(loader SensitiveWordLoader) error {
+	entries, actions, err := loader()
+	if err != nil {
+		return err
+	}
+	f := NewSensitiveFilter(entries, actions)
+	sensitiveFilterMu.Lock()
+	sensitiveFilter = f
+	sensitiveFilterMu.Unlock()
+	log.Printf("[SensitiveWord] 词库加载完成，共 %d 个敏感词", len(f.words))
+	return nil
+}
+
+// InitSensitiveWordFilter 启动时加载词库，并订阅 Redis 频道，收到词库变更通知后重新加载（支持多实例热更新）
+func InitSensitiveWordFilter(loader SensitiveWordLoader) {
+	if err := ReloadSensitiveWords(loader); err != nil {
+		log.Printf("[SensitiveWord] 词库加载失败: %v", err)
+	}
+
+	pubsub := config.GetRedisClient().Subscribe(config.Ctx, constant.SensitiveWordReloadChannel)
+	go func() {
+		defer pubsub.Close()
+		for range pubsub.Channel() {
+			if err := ReloadSensitiveWords(loader); err != nil {
+				log.Printf("[SensitiveWord] 词库热更新失败: %v", err)
+			}
+		}
+	}()
+}
+
+// PublishSensitiveWordReload 词库变更后通知所有实例重新加载
+func PublishSensitiveWordReload() error {
+	return config.GetRedisClient().Publish(config.Ctx, constant.SensitiveWordReloadChannel, "reload").Err()
+}
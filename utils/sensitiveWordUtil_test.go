@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+)
+
+func newTestSensitiveFilter() *SensitiveFilter {
+	entries := []SensitiveWordEntry{
+		{Word: "sb", Category: "abuse"},
+		{Word: "ass", Category: "abuse"},
+		{Word: "傻逼", Category: "abuse"},
+		{Word: "法轮", Category: "politics"},
+		{Word: "bad word", Category: "abuse"},
+	}
+	return NewSensitiveFilter(entries, map[string]string{
+		"abuse":    constant.SensitiveActionMask,
+		"politics": constant.SensitiveActionReject,
+	})
+}
+
+func TestSensitiveFilterCheck(t *testing.T) {
+	f := newTestSensitiveFilter()
+	tests := []struct {
+		name   string
+		text   string
+		action string
+		masked string
+	}{
+		// 英文敏感词不能跨越空格、标点匹配，也不能匹配单词内部
+		{"跨越空格", "this book is great", "", "this book is great"},
+		{"跨越标点", "Thanks. Bob", "", "Thanks. Bob"},
+		{"单词内部", "a class on assessment", "", "a class on assessment"},
+		{"单词前缀", "sbt build", "", "sbt build"},
+		{"数字相连", "ass1", "", "ass1"},
+		{"普通英文", "The first class passes the test.", "", "The first class passes the test."},
+		// 整词命中
+		{"整词", "you sb", constant.SensitiveActionMask, "you **"},
+		{"大小写", "SB!", constant.SensitiveActionMask, "**!"},
+		{"标点包围", "(ass)", constant.SensitiveActionMask, "(***)"},
+		{"与中文相邻", "你是sb吧", constant.SensitiveActionMask, "你是**吧"},
+		{"含空格的词", "a bad word here", constant.SensitiveActionMask, "a *** **** here"},
+		{"含空格的词匹配标点", "bad-word", constant.SensitiveActionMask, "***-****"},
+		{"含空格的词不匹配连写", "badword", "", "badword"},
+		// 中文之间的分隔字符仍然忽略
+		{"中文", "你是傻逼", constant.SensitiveActionMask, "你是**"},
+		{"中文夹空格", "傻 逼", constant.SensitiveActionMask, "* *"},
+		{"中文夹标点", "傻.逼", constant.SensitiveActionMask, "*.*"},
+		{"中文不跨越英文", "傻 a 逼", "", "傻 a 逼"},
+		{"reject", "法 轮", constant.SensitiveActionReject, "* *"},
+		{"无命中", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := f.Check(tt.text)
+			if result.Action != tt.action {
+				t.Errorf("Check(%q).Action = %q, want %q (matches %+v)", tt.text, result.Action, tt.action, result.Matches)
+			}
+			if result.Masked != tt.masked {
+				t.Errorf("Check(%q).Masked = %q, want %q", tt.text, result.Masked, tt.masked)
+			}
+		})
+	}
+}
+
+func TestNormalizeSensitiveWord(t *testing.T) {
+	tests := map[string]string{
+		"SB":        "sb",
+		"bad word":  "bad word",
+		"Bad--Word": "bad word",
+		" s.b ":     "s b",
+		"傻 逼":       "傻逼",
+	}
+	for word, want := range tests {
+		if got := NormalizeSensitiveWord(word); got != want {
+			t.Errorf("NormalizeSensitiveWord(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	summaryChunkRunes          = 12000 // 分段摘要时每段的最大字符数
+	summaryChunkOverlap        = 200   // 相邻分段的重叠字符数，避免句子被截断后丢失上下文
+	summaryMaxLevels           = 4     // 最多逐层合并的次数，防止摘要无法收敛时无限循环
+	defaultSummaryConcurrency  = 4     // 默认同时请求模型的分段数
+	summaryProgressStageMap    = "map"
+	summaryProgressStageReduce = "reduce"
+)
+
+// SummaryProgress 分段摘要的进度，流式接口会以 progress 事件推送给前端
+type SummaryProgress struct {
+	Stage string `json:"stage"` // map-分段摘要中，reduce-正在生成最终摘要
+	Level int    `json:"level"` // 当前合并层级，从 1 开始
+	Done  int    `json:"done"`  // 当前层级已完成的分段数
+	Total int    `json:"total"` // 当前层级的分段总数
+}
+
+// getSummaryConcurrency 获取分段摘要的并发数（dashscope.summary_concurrency）
+func getSummaryConcurrency() int {
+	concurrency := viper.GetInt("dashscope.summary_concurrency")
+	if concurrency <= 0 {
+		concurrency = defaultSummaryConcurrency
+	}
+	return concurrency
+}
+
+// SummarizeLongText 对超长正文做分层摘要（map-reduce）：
+// 正文不超过 maxRunes 时原样返回；否则切分为若干段并发生成分段摘要，拼接后若仍超长则继续逐层合并，
+// 返回的内容可直接作为最终摘要的素材。分段摘要按提示词版本缓存在 Redis 中，重新生成时只需请求变化的分段。
+// chunkPrompt 为分段摘要的系统提示词，promptVersion 为其版本标签；onProgress 可为 nil，调用是串行的。
+func SummarizeLongText(ctx context.Context, text string, maxRunes int, chunkPrompt, promptVersion string, onProgress func(SummaryProgress)) (string, error) {
+	current := text
+	for level := 1; utf8.RuneCountInString(current) > maxRunes; level++ {
+		if level > summaryMaxLevels {
+			// 多次合并后仍然超长，截断兜底
+			return TruncateRunesForSummary(current, maxRunes), nil
+		}
+
+		chunks := ChunkText(current, summaryChunkRunes, summaryChunkOverlap)
+		summaries, err := summarizeChunks(ctx, chunks, level, chunkPrompt, promptVersion, onProgress)
+		if err != nil {
+			return "", err
+		}
+
+		var builder strings.Builder
+		for i, summary := range summaries {
+			builder.WriteString(fmt.Sprintf("【第%d部分】\n%s\n\n", i+1, strings.TrimSpace(summary)))
+		}
+		current = strings.TrimSpace(builder.String())
+	}
+
+	if onProgress != nil {
+		onProgress(SummaryProgress{Stage: summaryProgressStageReduce})
+	}
+	return current, nil
+}
+
+// summarizeChunks 以有限并发为每个分段生成摘要，结果与分段顺序一致
+func summarizeChunks(ctx context.Context, chunks []string, level int, chunkPrompt, promptVersion string, onProgress func(SummaryProgress)) ([]string, error) {
+	summaries := make([]string, len(chunks))
+	sem := make(chan struct{}, getSummaryConcurrency())
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int
+		firstErr error
+	)
+	report := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		done++
+		if onProgress != nil {
+			onProgress(SummaryProgress{Stage: summaryProgressStageMap, Level: level, Done: done, Total: len(chunks)})
+		}
+	}
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// 请求已取消（如客户端断开）时不再调用模型
+			if err := ctx.Err(); err != nil {
+				report(err)
+				return
+			}
+			summary, err := summarizeChunk(ctx, chunk, chunkPrompt, promptVersion)
+			if err != nil {
+				report(fmt.Errorf("第%d部分摘要失败: %w", i+1, err))
+				return
+			}
+			summaries[i] = summary
+			report(nil)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return summaries, nil
+}
+
+// summarizeChunk 生成单个分段的摘要，优先使用缓存
+func summarizeChunk(ctx context.Context, chunk, chunkPrompt, promptVersion string) (string, error) {
+	cached, err := GetChunkSummaryFromCache(ctx, promptVersion, chunk)
+	if err != nil {
+		log.Printf("[AISummary] 读取分段摘要缓存失败: %v", err)
+	} else if cached != "" {
+		return cached, nil
+	}
+
+	summary, err := Chat([]Message{
+		{Role: "system", Content: chunkPrompt},
+		{Role: "user", Content: chunk},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := SetChunkSummaryCache(ctx, promptVersion, chunk, summary); err != nil {
+		log.Printf("[AISummary] 写入分段摘要缓存失败: %v", err)
+	}
+	return summary, nil
+}
@@ -32,8 +32,9 @@ func TruncateRunesForSummary(s string, max int) string {
 	return string(runes[:max]) + "\n\n【说明：正文过长，已截断后续部分；摘要仅基于以上片段。】"
 }
 
-// ExtractDocumentPDFPlainText 下载 PDF、抽取并清洗正文（与 RAG 学习流程一致），返回截断后的纯文本。
-func ExtractDocumentPDFPlainText(documentURL string, maxRunes int) (string, error) {
+// ExtractDocumentPDFPlainText 下载 PDF、抽取并清洗正文（与 RAG 学习流程一致），返回完整纯文本。
+// 正文可能远超模型上下文，调用方需通过 SummarizeLongText 分段摘要后再生成最终摘要。
+func ExtractDocumentPDFPlainText(documentURL string) (string, error) {
 	if !DocumentURLPathLooksLikePDF(documentURL) {
 		return "", ErrSummaryNotPDF
 	}
@@ -51,5 +52,40 @@ func ExtractDocumentPDFPlainText(documentURL string, maxRunes int) (string, erro
 	if strings.TrimSpace(cleaned) == "" {
 		return "", ErrSummaryEmptyText
 	}
-	return TruncateRunesForSummary(cleaned, maxRunes), nil
+	return cleaned, nil
+}
+
+// PDFPageText PDF 单页清洗后的正文
+type PDFPageText struct {
+	Page int
+	Text string
+}
+
+// ExtractDocumentPDFPages 下载 PDF 并按页抽取、清洗正文（跳过空白页），用于需要标注来源页码的场景（如生成测验）。
+func ExtractDocumentPDFPages(documentURL string) ([]PDFPageText, error) {
+	if !DocumentURLPathLooksLikePDF(documentURL) {
+		return nil, ErrSummaryNotPDF
+	}
+	tmpPath, err := DownloadFromCOSToTemp(documentURL)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpPath)
+
+	rawPages, err := ExtractPagesFromPDF(tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	var pages []PDFPageText
+	for i, raw := range rawPages {
+		cleaned := CleanText(raw)
+		if strings.TrimSpace(cleaned) == "" {
+			continue
+		}
+		pages = append(pages, PDFPageText{Page: i + 1, Text: cleaned})
+	}
+	if len(pages) == 0 {
+		return nil, ErrSummaryEmptyText
+	}
+	return pages, nil
 }
@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	_ "golang.org/x/image/webp" // 注册 WebP 解码器
+)
+
+// ThumbnailKey 缩略图在存储中的路径，如 covers/数学/123.jpg 的 list 规格为 covers/数学/123_list.jpg；
+// 没有 WebP 编码器，WebP 原图的缩略图保存为 JPEG，如 123.webp 的 list 规格为 123_list.jpg
+func ThumbnailKey(key string, variant string) string {
+	ext := path.Ext(key)
+	base := strings.TrimSuffix(key, ext)
+	if strings.EqualFold(ext, ".webp") {
+		ext = ".jpg"
+	}
+	return base + "_" + variant + ext
+}
+
+// HasThumbnails 为 JPEG、PNG、WebP 生成缩略图；SVG 文字封面是矢量图（渲染器不可用时才会保存为 SVG），各规格直接使用原图
+func HasThumbnails(key string) bool {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".jpg", ".jpeg", ".png", ".webp":
+		return true
+	}
+	return false
+}
+
+// GetThumbnailURL 获取图片某个规格缩略图的访问地址，不生成缩略图的格式返回原图地址
+func GetThumbnailURL(key string, variant string) string {
+	if key == "" {
+		return ""
+	}
+	if !HasThumbnails(key) {
+		return GetFileURL(key)
+	}
+	return GetFileURL(ThumbnailKey(key, variant))
+}
+
+// GenerateThumbnails 读取 r 中的图片，按 widths 生成各规格缩略图保存到 key 旁边，格式与原图相同（WebP 为 JPEG）
+func GenerateThumbnails(key string, r io.Reader, widths map[string]int) error {
+	if !HasThumbnails(key) {
+		return nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("图片解码失败: %v", err)
+	}
+	if config.Width*config.Height > constant.MaxImagePixels {
+		return fmt.Errorf("图片尺寸过大: %dx%d", config.Width, config.Height)
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("图片解码失败: %v", err)
+	}
+
+	for variant, width := range widths {
+		var buf bytes.Buffer
+		thumb := resizeImage(img, width)
+		contentType := "image/png"
+		if format == "png" {
+			err = png.Encode(&buf, thumb)
+		} else {
+			// WebP 可能带透明通道，JPEG 不支持透明，先铺白色背景
+			if format == "webp" {
+				thumb = flattenImage(thumb)
+			}
+			contentType = "image/jpeg"
+			err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: constant.ThumbnailJPEGQuality})
+		}
+		if err != nil {
+			return err
+		}
+		err = UploadFileWithSize(ThumbnailKey(key, variant), &buf, int64(buf.Len()), contentType)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateStoredThumbnails 为存储中已有的图片生成缩略图（补全历史封面和头像）
+func GenerateStoredThumbnails(key string, widths map[string]int) error {
+	if !HasThumbnails(key) {
+		return nil
+	}
+	store, err := GetBlobStore()
+	if err != nil {
+		return err
+	}
+	reader, err := store.Get(context.Background(), key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return GenerateThumbnails(key, reader, widths)
+}
+
+// ThumbnailsExist 判断图片的缩略图是否已生成（以 list 规格为准）
+func ThumbnailsExist(key string) (bool, error) {
+	if !HasThumbnails(key) {
+		return true, nil
+	}
+	_, err := StatBlob(ThumbnailKey(key, constant.ImageVariantList))
+	if errors.Is(err, ErrBlobNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DeleteFileWithThumbnails 删除文件及其缩略图
+func DeleteFileWithThumbnails(key string) error {
+	if err := DeleteFile(key); err != nil {
+		return err
+	}
+	if key == "" || !HasThumbnails(key) {
+		return nil
+	}
+	for _, variant := range constant.ImageVariants {
+		if err := DeleteFile(ThumbnailKey(key, variant)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenImage 把图片画到白色背景上，去掉透明通道
+func flattenImage(src image.Image) image.Image {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Over)
+	return dst
+}
+
+// resizeImage 按宽度等比缩小图片（区域平均），原图不超过该宽度时原样返回
+func resizeImage(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() <= width {
+		return src
+	}
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+
+	// 先转换为 RGBA，直接读取像素比逐点调用 At 快得多
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, max((y+1)*srcH/height, y*srcH/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, max((x+1)*srcW/width, x*srcW/width+1)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					sum[0] += int(p[0])
+					sum[1] += int(p[1])
+					sum[2] += int(p[2])
+					sum[3] += int(p[3])
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			offset := y*dst.Stride + x*4
+			for i := 0; i < 4; i++ {
+				dst.Pix[offset+i] = uint8(sum[i] / n)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,265 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/antidote-kt/SSE_Library-back/config"
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/go-redis/redis/v8"
+)
+
+// UploadSession Redis 中保存的分片上传会话
+type UploadSession struct {
+	ID          string `json:"id"`
+	UserID      uint64 `json:"userId"`
+	FileName    string `json:"fileName"`
+	FileSize    int64  `json:"fileSize"`
+	PartSize    int64  `json:"partSize"`
+	TotalParts  int    `json:"totalParts"`
+	ContentType string `json:"contentType"`
+	ObjectKey   string `json:"objectKey"` // 合并后文件在存储中的路径
+	SHA256      string `json:"sha256"`    // 客户端提供的整个文件的 SHA-256，为空时不校验
+	CreatedAt   int64  `json:"createdAt"`
+}
+
+// UploadedObject 已完成上传、等待提交文档的对象
+type UploadedObject struct {
+	UserID uint64 `json:"userId"`
+	SHA256 string `json:"sha256"` // 直传的文件在提交文档时才计算
+}
+
+// NewUploadID 生成上传会话 ID
+func NewUploadID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// PartSizeOf 第 partNumber 个分片（从 1 开始）应有的大小
+func (s *UploadSession) PartSizeOf(partNumber int) int64 {
+	if partNumber == s.TotalParts {
+		return s.FileSize - s.PartSize*int64(s.TotalParts-1)
+	}
+	return s.PartSize
+}
+
+// uploadPartKey 分片在存储中的临时路径
+func uploadPartKey(uploadID string, partNumber int) string {
+	return fmt.Sprintf("%s%s/%d", constant.UploadTempKeyPrefix, uploadID, partNumber)
+}
+
+// SaveUploadSession 保存分片上传会话
+func SaveUploadSession(session *UploadSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	rdb := config.GetRedisClient()
+	return rdb.Set(config.Ctx, constant.UploadSessionKeyPrefix+session.ID, data, constant.UploadSessionTTLHours*time.Hour).Err()
+}
+
+// GetUploadSession 获取分片上传会话，不存在或已过期时返回 nil
+func GetUploadSession(uploadID string) (*UploadSession, error) {
+	rdb := config.GetRedisClient()
+	data, err := rdb.Get(config.Ctx, constant.UploadSessionKeyPrefix+uploadID).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session UploadSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetUploadedParts 获取会话已上传的分片及其校验和
+func GetUploadedParts(uploadID string) (map[int]string, error) {
+	rdb := config.GetRedisClient()
+	values, err := rdb.HGetAll(config.Ctx, constant.UploadPartsKeyPrefix+uploadID).Result()
+	if err != nil {
+		return nil, err
+	}
+	parts := make(map[int]string, len(values))
+	for field, checksum := range values {
+		if partNumber, err := strconv.Atoi(field); err == nil {
+			parts[partNumber] = checksum
+		}
+	}
+	return parts, nil
+}
+
+// UploadPart 校验分片的大小和 SHA-256 后写入存储并记录，重复上传同一分片会覆盖
+func UploadPart(session *UploadSession, partNumber int, data []byte, checksum string) error {
+	if int64(len(data)) != session.PartSizeOf(partNumber) {
+		return errors.New(constant.UploadPartSizeMismatch)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != checksum {
+		return errors.New(constant.UploadPartChecksumMismatch)
+	}
+	if err := UploadFileWithSize(uploadPartKey(session.ID, partNumber), bytes.NewReader(data), int64(len(data)), ""); err != nil {
+		return err
+	}
+
+	rdb := config.GetRedisClient()
+	key := constant.UploadPartsKeyPrefix + session.ID
+	pipe := rdb.TxPipeline()
+	pipe.HSet(config.Ctx, key, strconv.Itoa(partNumber), checksum)
+	pipe.Expire(config.Ctx, key, constant.UploadSessionTTLHours*time.Hour)
+	_, err := pipe.Exec(config.Ctx)
+	return err
+}
+
+// partsReader 依次读取各分片，读完一个再打开下一个
+type partsReader struct {
+	store   BlobStore
+	session *UploadSession
+	next    int
+	current io.ReadCloser
+}
+
+func (r *partsReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.next > r.session.TotalParts {
+				return 0, io.EOF
+			}
+			reader, err := r.store.Get(context.Background(), uploadPartKey(r.session.ID, r.next))
+			if err != nil {
+				return 0, err
+			}
+			r.current = reader
+			r.next++
+		}
+		n, err := r.current.Read(p)
+		if errors.Is(err, io.EOF) {
+			r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *partsReader) Close() error {
+	if r.current != nil {
+		return r.current.Close()
+	}
+	return nil
+}
+
+// CompleteUpload 按顺序合并全部分片到最终路径并返回文件的 SHA-256；客户端提供了整个文件的 SHA-256 时校验不一致则删除合并结果
+func CompleteUpload(session *UploadSession) (string, error) {
+	store, err := GetBlobStore()
+	if err != nil {
+		return "", err
+	}
+	reader := &partsReader{store: store, session: session, next: 1}
+	defer reader.Close()
+
+	hash := sha256.New()
+	err = store.Put(context.Background(), session.ObjectKey, io.TeeReader(reader, hash), session.FileSize, session.ContentType)
+	if err != nil {
+		return "", err
+	}
+	fileHash := hex.EncodeToString(hash.Sum(nil))
+	if session.SHA256 != "" && session.SHA256 != fileHash {
+		_ = store.Delete(context.Background(), session.ObjectKey)
+		return "", errors.New(constant.UploadFileChecksumMismatch)
+	}
+	return fileHash, nil
+}
+
+// RemoveUploadSession 删除会话的临时分片和 Redis 记录（完成或取消上传后调用）
+func RemoveUploadSession(session *UploadSession) error {
+	store, err := GetBlobStore()
+	if err != nil {
+		return err
+	}
+	for partNumber := 1; partNumber <= session.TotalParts; partNumber++ {
+		_ = store.Delete(context.Background(), uploadPartKey(session.ID, partNumber))
+	}
+	rdb := config.GetRedisClient()
+	return rdb.Del(config.Ctx, constant.UploadSessionKeyPrefix+session.ID, constant.UploadPartsKeyPrefix+session.ID).Err()
+}
+
+// SaveUploadedObject 记录已完成上传的对象所属用户，提交文档时校验
+func SaveUploadedObject(objectKey string, object UploadedObject) error {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	rdb := config.GetRedisClient()
+	return rdb.Set(config.Ctx, constant.UploadObjectKeyPrefix+objectKey, data, constant.UploadSessionTTLHours*time.Hour).Err()
+}
+
+// GetUploadedObject 获取已完成上传的对象，不存在或已过期时返回 nil
+func GetUploadedObject(objectKey string) (*UploadedObject, error) {
+	rdb := config.GetRedisClient()
+	data, err := rdb.Get(config.Ctx, constant.UploadObjectKeyPrefix+objectKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var object UploadedObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	return &object, nil
+}
+
+// RemoveUploadedObject 对象已用于创建文档后删除记录，避免重复提交
+func RemoveUploadedObject(objectKey string) error {
+	rdb := config.GetRedisClient()
+	return rdb.Del(config.Ctx, constant.UploadObjectKeyPrefix+objectKey).Err()
+}
+
+// RelocateUploadedObject 将通过校验的上传文件移到以检测出的扩展名命名的路径，并以检测出的类型重新保存，
+// 上传时的路径和类型来自客户端，不能直接使用；上传记录随之迁移，返回新路径
+func RelocateUploadedObject(objectKey string, object UploadedObject, size int64, checked UploadCheckResult) (string, error) {
+	store, err := GetBlobStore()
+	if err != nil {
+		return "", err
+	}
+	newKey := path.Join(path.Dir(objectKey), generateSecureFilename(checked.Extension))
+	reader, err := store.Get(context.Background(), objectKey)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	if err := store.Put(context.Background(), newKey, reader, size, checked.MIME); err != nil {
+		return "", err
+	}
+	if err := SaveUploadedObject(newKey, object); err != nil {
+		_ = store.Delete(context.Background(), newKey)
+		return "", err
+	}
+
+	if err := store.Delete(context.Background(), objectKey); err != nil {
+		log.Printf("[Upload] 删除已迁移的上传文件 %s 失败: %v", objectKey, err)
+	}
+	if err := RemoveUploadedObject(objectKey); err != nil {
+		log.Printf("[Upload] 删除已迁移的上传记录 %s 失败: %v", objectKey, err)
+	}
+	return newKey, nil
+}
@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/antidote-kt/SSE_Library-back/constant"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/ledongthuc/pdf"
+)
+
+var (
+	ErrUploadTypeNotAllowed = errors.New(constant.UploadFileTypeNotAllowed)
+	ErrCoverTypeNotAllowed  = errors.New(constant.UploadCoverTypeNotAllowed)
+	ErrAvatarTypeNotAllowed = errors.New(constant.UploadAvatarTypeNotAllowed)
+	ErrPDFEncrypted         = errors.New(constant.UploadPDFEncrypted)
+	ErrPDFCorrupted         = errors.New(constant.UploadPDFCorrupted)
+	ErrPDFPageInvalid       = errors.New(constant.UploadPDFPageInvalid)
+)
+
+// InfectedFileError 文件未通过恶意文件扫描
+type InfectedFileError struct {
+	Signature string
+}
+
+func (e *InfectedFileError) Error() string {
+	return fmt.Sprintf("%s（%s）", constant.UploadFileInfected, e.Signature)
+}
+
+// UploadCheckResult 按文件内容检测出的格式
+type UploadCheckResult struct {
+	MIME      string
+	Extension string // 带点的扩展名，如 ".pdf"
+}
+
+// IsUploadRejected 判断错误是否是文件未通过校验（而不是服务端出错）
+func IsUploadRejected(err error) bool {
+	var infected *InfectedFileError
+	return errors.As(err, &infected) ||
+		errors.Is(err, ErrUploadTypeNotAllowed) || errors.Is(err, ErrCoverTypeNotAllowed) || errors.Is(err, ErrAvatarTypeNotAllowed) ||
+		errors.Is(err, ErrPDFEncrypted) || errors.Is(err, ErrPDFCorrupted) || errors.Is(err, ErrPDFPageInvalid)
+}
+
+// ValidateUploadFile 校验文档主文件：按文件内容检测格式是否在 docType 的白名单中，PDF 检查加密、页数和损坏，最后做恶意文件扫描
+func ValidateUploadFile(file *multipart.FileHeader, docType string) (UploadCheckResult, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return UploadCheckResult{}, err
+	}
+	defer reader.Close()
+	return checkUpload(reader, file.Size, constant.AllowedUploadMIMETypes[docType], ErrUploadTypeNotAllowed)
+}
+
+// ValidateCoverImage 校验封面图片的格式并做恶意文件扫描
+func ValidateCoverImage(cover *multipart.FileHeader) (UploadCheckResult, error) {
+	reader, err := cover.Open()
+	if err != nil {
+		return UploadCheckResult{}, err
+	}
+	defer reader.Close()
+	return checkUpload(reader, cover.Size, constant.AllowedCoverMIMETypes, ErrCoverTypeNotAllowed)
+}
+
+// ValidateAvatarImage 校验头像图片的格式（与封面相同，只允许 JPEG、PNG、WebP）并做恶意文件扫描
+func ValidateAvatarImage(avatar *multipart.FileHeader) (UploadCheckResult, error) {
+	reader, err := avatar.Open()
+	if err != nil {
+		return UploadCheckResult{}, err
+	}
+	defer reader.Close()
+	return checkUpload(reader, avatar.Size, constant.AllowedCoverMIMETypes, ErrAvatarTypeNotAllowed)
+}
+
+// ValidateStoredFile 校验已在存储中的文档主文件（分片上传或直传完成的文件），规则与 ValidateUploadFile 相同
+func ValidateStoredFile(key string, docType string) (UploadCheckResult, error) {
+	tmpPath, err := DownloadFromCOSToTemp(key)
+	if err != nil {
+		return UploadCheckResult{}, err
+	}
+	defer os.Remove(tmpPath)
+	return ValidateLocalFile(tmpPath, docType)
+}
+
+// ValidateLocalFile 校验本地的文档主文件（如批量导入时从压缩包中解出的文件），规则与 ValidateUploadFile 相同
+func ValidateLocalFile(filePath string, docType string) (UploadCheckResult, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return UploadCheckResult{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return UploadCheckResult{}, err
+	}
+	return checkUpload(f, info.Size(), constant.AllowedUploadMIMETypes[docType], ErrUploadTypeNotAllowed)
+}
+
+// checkUpload 检测格式、检查 PDF 并扫描，格式不在 allowed 中时返回 typeErr
+func checkUpload(r io.ReaderAt, size int64, allowed []string, typeErr error) (UploadCheckResult, error) {
+	result, err := detectUploadType(r, size)
+	if err != nil {
+		return UploadCheckResult{}, err
+	}
+	if !slices.Contains(allowed, result.MIME) {
+		return UploadCheckResult{}, typeErr
+	}
+	if result.MIME == constant.MIMEPDF {
+		if err := checkPDF(r, size); err != nil {
+			return UploadCheckResult{}, err
+		}
+	}
+
+	scanner, err := GetMalwareScanner()
+	if err != nil {
+		return UploadCheckResult{}, fmt.Errorf("%w: %v", ErrScanUnavailable, err)
+	}
+	scan, err := scanner.Scan(context.Background(), io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return UploadCheckResult{}, err
+	}
+	if scan.Infected {
+		return UploadCheckResult{}, &InfectedFileError{Signature: scan.Signature}
+	}
+	return result, nil
+}
+
+// detectUploadType 按文件头检测格式；识别为普通 zip 时再检查压缩包内容，区分 EPUB、DOCX、PPTX
+func detectUploadType(r io.ReaderAt, size int64) (UploadCheckResult, error) {
+	mtype, err := mimetype.DetectReader(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return UploadCheckResult{}, err
+	}
+	for m := mtype; m != nil; m = m.Parent() {
+		if m.Is("application/zip") {
+			if zipType := detectZipContainer(r, size); zipType != nil {
+				mtype = zipType
+			}
+			break
+		}
+	}
+	// 去掉 charset 等参数，只保留类型本身
+	mimeType, _, _ := strings.Cut(mtype.String(), ";")
+	return UploadCheckResult{MIME: mimeType, Extension: mtype.Extension()}, nil
+}
+
+// detectZipContainer 根据压缩包中的特征文件判断具体格式，无法判断时返回 nil
+func detectZipContainer(r io.ReaderAt, size int64) *mimetype.MIME {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil
+	}
+	for _, f := range archive.File {
+		switch f.Name {
+		case "word/document.xml":
+			return mimetype.Lookup(constant.MIMEDOCX)
+		case "ppt/presentation.xml":
+			return mimetype.Lookup(constant.MIMEPPTX)
+		case "mimetype":
+			rc, err := f.Open()
+			if err != nil {
+				continue
+			}
+			content, _ := io.ReadAll(io.LimitReader(rc, 64))
+			rc.Close()
+			if string(bytes.TrimSpace(content)) == constant.MIMEEPUB {
+				return mimetype.Lookup(constant.MIMEEPUB)
+			}
+		}
+	}
+	return nil
+}
+
+// checkPDF 检查 PDF 是否加密、损坏以及页数是否正常。解析库只支持 PDF 1.x，其他版本只检查文件结尾
+func checkPDF(r io.ReaderAt, size int64) (err error) {
+	// 解析库遇到损坏的文件可能 panic
+	defer func() {
+		if recover() != nil {
+			err = ErrPDFCorrupted
+		}
+	}()
+
+	header := make([]byte, 10)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return ErrPDFCorrupted
+	}
+	if !bytes.HasPrefix(header, []byte("%PDF-1.")) || (header[8] != '\r' && header[8] != '\n') {
+		return checkPDFTrailer(r, size)
+	}
+
+	reader, err := pdf.NewReaderEncrypted(r, size, func() string { return "" })
+	if err != nil {
+		if errors.Is(err, pdf.ErrInvalidPassword) {
+			return ErrPDFEncrypted
+		}
+		return ErrPDFCorrupted
+	}
+	// 只有所有者密码的 PDF 可以用空密码打开，但仍然是加密文件
+	if !reader.Trailer().Key("Encrypt").IsNull() {
+		return ErrPDFEncrypted
+	}
+	pages := reader.NumPage()
+	if pages <= 0 || pages > constant.MaxPDFPages {
+		return ErrPDFPageInvalid
+	}
+	return nil
+}
+
+// checkPDFTrailer 检查文件结尾的 %%EOF 标记，用于解析库不支持的 PDF 版本
+func checkPDFTrailer(r io.ReaderAt, size int64) error {
+	tail := make([]byte, min(size, 1024))
+	if _, err := r.ReadAt(tail, size-int64(len(tail))); err != nil && !errors.Is(err, io.EOF) {
+		return ErrPDFCorrupted
+	}
+	if !bytes.Contains(tail, []byte("%%EOF")) {
+		return ErrPDFCorrupted
+	}
+	if bytes.Contains(tail, []byte("/Encrypt")) {
+		return ErrPDFEncrypted
+	}
+	return nil
+}
+
+// DetectFileExtension 按文件内容检测扩展名，无法识别时返回空字符串
+func DetectFileExtension(file *multipart.FileHeader) string {
+	return DetectFileType(file).Extension
+}
+
+// DetectFileType 按文件内容检测上传文件的格式，无法检测时返回零值
+func DetectFileType(file *multipart.FileHeader) UploadCheckResult {
+	reader, err := file.Open()
+	if err != nil {
+		return UploadCheckResult{}
+	}
+	defer reader.Close()
+	result, err := detectUploadType(reader, file.Size)
+	if err != nil {
+		return UploadCheckResult{}
+	}
+	return result
+}